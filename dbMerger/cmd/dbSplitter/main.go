@@ -0,0 +1,214 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/multiversx/mx-chain-go/sharding"
+	logger "github.com/multiversx/mx-chain-logger-go"
+	"github.com/multiversx/mx-chain-tools-go/dbmerger/cmd/shared"
+	"github.com/multiversx/mx-chain-tools-go/dbmerger/common"
+	"github.com/multiversx/mx-chain-tools-go/dbmerger/path"
+	"github.com/multiversx/mx-chain-tools-go/dbmerger/storer"
+	"github.com/urfave/cli"
+)
+
+const listDelimiter = ","
+
+var (
+	log = logger.GetOrCreate("main")
+
+	source = cli.StringFlag{
+		Name:  "source",
+		Usage: "This flag specifies the path of the database to be split",
+		Value: "",
+	}
+	dests = cli.StringFlag{
+		Name:  "dests",
+		Usage: `This flag specifies the destination paths separated by ",", every destination must be an empty directory. Example "-dests ` + strings.Join([]string{"shard0/db", "shard1/db"}, listDelimiter) + "\"",
+		Value: "",
+	}
+	filters = cli.StringFlag{
+		Name: "filters",
+		Usage: `This flag specifies, for every destination and in the same order, the filter of the copied keys, separated by ",". ` +
+			`Supported filters: "all", "prefix:<hex prefix>", "range:<hex start>-<hex end>" (start included, end excluded, ` +
+			`any of them can be empty), "shard:<shard ID>" or "shard:metachain". Example "-filters ` + strings.Join([]string{"shard:0", "shard:1"}, listDelimiter) + "\"",
+		Value: "",
+	}
+	sourceDBType = cli.StringFlag{
+		Name:  "source-db-type",
+		Usage: "This flag specifies the backend of the source database: " + common.DBTypesUsage(),
+		Value: string(common.LevelDB),
+	}
+	destsDBTypes = cli.StringFlag{
+		Name: "dests-db-types",
+		Usage: `This flag specifies the backends of the destination databases separated by ",", in the order of the destinations, ` +
+			"or a single backend used for all the destinations: " + common.DBTypesUsage(),
+		Value: string(common.LevelDB),
+	}
+	numShards = cli.UintFlag{
+		Name:  "num-shards",
+		Usage: "This flag specifies the number of shards used to compute the shard of a key by the shard filters",
+		Value: 3,
+	}
+
+	errEmptyPathProvided      = errors.New("empty path provided")
+	errInvalidNumberOfFilters = errors.New("invalid number of filters")
+)
+
+type parsedFlags struct {
+	source           common.DBPath
+	destinations     []common.DBPath
+	filters          []string
+	numShards        uint32
+	logLevel         string
+	logSave          bool
+	progressInterval time.Duration
+	persisterArgs    storer.ArgsPersisterCreator
+}
+
+func main() {
+	app := cli.NewApp()
+	cli.AppHelpTemplate = shared.HelpTemplate
+	app.Name = "DB splitter tool CLI App"
+	app.Version = "v1.0.0"
+	app.Usage = "This is the entry point for DB split tool able to copy the data of a level DB database into several ones, filtered by key"
+	app.Flags = []cli.Flag{
+		source,
+		dests,
+		filters,
+		sourceDBType,
+		destsDBTypes,
+		numShards,
+		shared.LogLevel,
+		shared.LogSaveFile,
+		shared.ProgressInterval,
+		shared.PersisterMaxBatchSize,
+		shared.PersisterBatchDelay,
+		shared.PersisterMaxOpenFiles,
+	}
+	app.Authors = []cli.Author{
+		{
+			Name:  "The MultiversX Team",
+			Email: "contact@multiversx.com",
+		},
+	}
+
+	app.Action = action
+
+	err := app.Run(os.Args)
+	if err != nil {
+		log.Error(err.Error())
+		os.Exit(1)
+	}
+}
+
+func action(ctx *cli.Context) {
+	flags, err := parseFlags(ctx)
+	if err != nil {
+		log.Error("cannot process input flags", "error", err)
+		return
+	}
+
+	err = doAction(flags)
+	if err != nil {
+		log.Error("cannot perform action", "error", err)
+		return
+	}
+
+	log.Info("action performed")
+}
+
+func parseFlags(ctx *cli.Context) (parsedFlags, error) {
+	destPaths := strings.Split(ctx.GlobalString(dests.Name), listDelimiter)
+	destsTypes, err := common.ParseDBTypes(strings.Split(ctx.GlobalString(destsDBTypes.Name), listDelimiter), len(destPaths))
+	if err != nil {
+		return parsedFlags{}, err
+	}
+
+	flags := parsedFlags{
+		source: common.DBPath{
+			Path: ctx.GlobalString(source.Name),
+			Type: common.DBType(ctx.GlobalString(sourceDBType.Name)),
+		},
+		destinations:     make([]common.DBPath, 0, len(destPaths)),
+		filters:          strings.Split(ctx.GlobalString(filters.Name), listDelimiter),
+		numShards:        uint32(ctx.GlobalUint(numShards.Name)),
+		logLevel:         ctx.GlobalString(shared.LogLevel.Name),
+		logSave:          ctx.GlobalBool(shared.LogSaveFile.Name),
+		progressInterval: time.Duration(ctx.GlobalInt(shared.ProgressInterval.Name)) * time.Second,
+		persisterArgs: storer.ArgsPersisterCreator{
+			BatchDelaySeconds: ctx.GlobalInt(shared.PersisterBatchDelay.Name),
+			MaxBatchSize:      ctx.GlobalInt(shared.PersisterMaxBatchSize.Name),
+			MaxOpenFiles:      ctx.GlobalInt(shared.PersisterMaxOpenFiles.Name),
+		},
+	}
+
+	if len(flags.source.Path) == 0 {
+		return parsedFlags{}, fmt.Errorf("%w for `source` flag", errEmptyPathProvided)
+	}
+	for idx, dest := range destPaths {
+		if len(dest) == 0 {
+			return parsedFlags{}, fmt.Errorf("%w for destination flag with index %d", errEmptyPathProvided, idx)
+		}
+
+		flags.destinations = append(flags.destinations, common.DBPath{Path: dest, Type: destsTypes[idx]})
+	}
+	if len(flags.filters) != len(flags.destinations) {
+		return parsedFlags{}, fmt.Errorf("%w, provided %d filters for %d destinations", errInvalidNumberOfFilters, len(flags.filters), len(flags.destinations))
+	}
+
+	return flags, nil
+}
+
+func doAction(flags parsedFlags) error {
+	err := shared.ProcessFileLogger(log, flags.logLevel, flags.logSave)
+	if err != nil {
+		return err
+	}
+
+	shardCoordinator, err := sharding.NewMultiShardCoordinator(flags.numShards, 0)
+	if err != nil {
+		return err
+	}
+
+	destinations := make([]storer.SplitDestination, 0, len(flags.destinations))
+	for idx, destination := range flags.destinations {
+		filter, errCreate := storer.CreateKeyFilter(flags.filters[idx], shardCoordinator)
+		if errCreate != nil {
+			return fmt.Errorf("%w for the destination with index %d", errCreate, idx)
+		}
+
+		destinations = append(destinations, storer.SplitDestination{
+			DBPath: destination,
+			Filter: filter,
+		})
+	}
+
+	progressReporter, err := storer.NewProgressReporter(flags.progressInterval)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		log.LogIfError(progressReporter.Close())
+	}()
+
+	persisterCreator, err := storer.NewPersisterCreator(flags.persisterArgs)
+	if err != nil {
+		return err
+	}
+
+	splitter, err := storer.NewDBSplitter(storer.ArgsDBSplitter{
+		PersisterCreator:    persisterCreator,
+		OsOperationsHandler: path.NewOsOperationsHandler(),
+		ProgressHandler:     progressReporter,
+	})
+	if err != nil {
+		return err
+	}
+
+	return splitter.SplitDB(flags.source, destinations...)
+}
@@ -5,17 +5,17 @@ import (
 	"fmt"
 	"os"
 	"strings"
+	"time"
 
 	logger "github.com/multiversx/mx-chain-logger-go"
-	"github.com/multiversx/mx-chain-logger-go/file"
+	"github.com/multiversx/mx-chain-tools-go/dbmerger/cmd/shared"
+	"github.com/multiversx/mx-chain-tools-go/dbmerger/common"
 	"github.com/multiversx/mx-chain-tools-go/dbmerger/path"
 	"github.com/multiversx/mx-chain-tools-go/dbmerger/storer"
 	"github.com/urfave/cli"
 )
 
-const sourcePathsDelimiter = ","
-const defaultLogsPath = "logs"
-const logFilePrefix = "log"
+const listDelimiter = ","
 
 var (
 	log = logger.GetOrCreate("main")
@@ -27,59 +27,57 @@ var (
 	}
 	sources = cli.StringFlag{
 		Name:  "sources",
-		Usage: `This flag specifies the source paths separated by ",". Example "-sources ` + strings.Join([]string{"path/1", "path/2", "path/3"}, sourcePathsDelimiter) + "\"",
+		Usage: `This flag specifies the source paths separated by ",". Example "-sources ` + strings.Join([]string{"path/1", "path/2", "path/3"}, listDelimiter) + "\"",
 		Value: "",
 	}
-	logLevel = cli.StringFlag{
-		Name: "log-level",
-		Usage: "This flag specifies the logger `level(s)`. It can contain multiple comma-separated value. For example" +
-			", if set to *:INFO the logs for all packages will have the INFO level. However, if set to *:INFO,api:DEBUG" +
-			" the logs for all packages will have the INFO level, excepting the api package which will receive a DEBUG" +
-			" log level.",
-		Value: "*:" + logger.LogDebug.String(),
+	destDBType = cli.StringFlag{
+		Name:  "dest-db-type",
+		Usage: "This flag specifies the backend of the destination database: " + common.DBTypesUsage(),
+		Value: string(common.LevelDB),
 	}
-	logSaveFile = cli.BoolFlag{
-		Name:  "log-save",
-		Usage: "Boolean option for enabling log saving. If set, it will automatically save all the logs into a file.",
+	sourcesDBTypes = cli.StringFlag{
+		Name: "sources-db-types",
+		Usage: `This flag specifies the backends of the source databases separated by ",", in the order of the sources, or a single ` +
+			"backend used for all the sources: " + common.DBTypesUsage(),
+		Value: string(common.LevelDB),
 	}
 
 	errEmptyPathProvided = errors.New("empty path provided")
 )
 
-const helpTemplate = `NAME:
-   {{.Name}} - {{.Usage}}
-USAGE:
-   {{.HelpName}} {{if .VisibleFlags}}[global options]{{end}}
-   {{if len .Authors}}
-AUTHOR:
-   {{range .Authors}}{{ . }}{{end}}
-   {{end}}{{if .Commands}}
-GLOBAL OPTIONS:
-   {{range .VisibleFlags}}{{.}}
-   {{end}}
-VERSION:
-   {{.Version}}
-   {{end}}
-`
-
 type parsedFlags struct {
-	destPath    string
-	sourcePaths []string
-	logLevel    string
-	logSave     bool
+	destination        common.DBPath
+	sources            []common.DBPath
+	logLevel           string
+	logSave            bool
+	conflictPolicy     string
+	conflictReportPath string
+	batchSizeBytes     int
+	readAheadBatches   int
+	progressInterval   time.Duration
+	persisterArgs      storer.ArgsPersisterCreator
 }
 
 func main() {
 	app := cli.NewApp()
-	cli.AppHelpTemplate = helpTemplate
+	cli.AppHelpTemplate = shared.HelpTemplate
 	app.Name = "DB merger tool CLI App"
 	app.Version = "v1.0.0"
 	app.Usage = "This is the entry point for DB merge tool able to merge 2 or more level DB databases"
 	app.Flags = []cli.Flag{
 		dest,
 		sources,
-		logLevel,
-		logSaveFile,
+		destDBType,
+		sourcesDBTypes,
+		shared.LogLevel,
+		shared.LogSaveFile,
+		shared.ConflictPolicy,
+		shared.ConflictReport,
+		shared.BatchSizeBytes,
+		shared.ReadAheadBatches,
+		shared.ProgressInterval,
+		shared.PersisterMaxBatchSize,
+		shared.PersisterMaxOpenFiles,
 	}
 	app.Authors = []cli.Author{
 		{
@@ -114,72 +112,100 @@ func action(ctx *cli.Context) {
 }
 
 func parseFlags(ctx *cli.Context) (parsedFlags, error) {
-	sourcePaths := ctx.GlobalString(sources.Name)
+	sourcePaths := strings.Split(ctx.GlobalString(sources.Name), listDelimiter)
+	sourcesTypes, err := common.ParseDBTypes(strings.Split(ctx.GlobalString(sourcesDBTypes.Name), listDelimiter), len(sourcePaths))
+	if err != nil {
+		return parsedFlags{}, err
+	}
 
 	flags := parsedFlags{
-		destPath:    ctx.GlobalString(dest.Name),
-		sourcePaths: strings.Split(sourcePaths, sourcePathsDelimiter),
-		logLevel:    ctx.GlobalString(logLevel.Name),
-		logSave:     ctx.GlobalBool(logSaveFile.Name),
+		destination: common.DBPath{
+			Path: ctx.GlobalString(dest.Name),
+			Type: common.DBType(ctx.GlobalString(destDBType.Name)),
+		},
+		sources:            make([]common.DBPath, 0, len(sourcePaths)),
+		logLevel:           ctx.GlobalString(shared.LogLevel.Name),
+		logSave:            ctx.GlobalBool(shared.LogSaveFile.Name),
+		conflictPolicy:     ctx.GlobalString(shared.ConflictPolicy.Name),
+		conflictReportPath: ctx.GlobalString(shared.ConflictReport.Name),
+		batchSizeBytes:     ctx.GlobalInt(shared.BatchSizeBytes.Name),
+		readAheadBatches:   ctx.GlobalInt(shared.ReadAheadBatches.Name),
+		progressInterval:   time.Duration(ctx.GlobalInt(shared.ProgressInterval.Name)) * time.Second,
+		persisterArgs: storer.ArgsPersisterCreator{
+			BatchDelaySeconds: storer.DefaultBatchDelaySeconds,
+			MaxBatchSize:      ctx.GlobalInt(shared.PersisterMaxBatchSize.Name),
+			MaxOpenFiles:      ctx.GlobalInt(shared.PersisterMaxOpenFiles.Name),
+		},
 	}
 
 	// TODO add separate check functions
-	if len(flags.destPath) == 0 {
+	if len(flags.destination.Path) == 0 {
 		return parsedFlags{}, fmt.Errorf("%w for `dest` flag", errEmptyPathProvided)
 	}
-	for idx, src := range flags.sourcePaths {
+	for idx, src := range sourcePaths {
 		if len(src) == 0 {
 			return parsedFlags{}, fmt.Errorf("%w for source flag with index %d", errEmptyPathProvided, idx)
 		}
+
+		flags.sources = append(flags.sources, common.DBPath{Path: src, Type: sourcesTypes[idx]})
 	}
 
 	return flags, nil
 }
 
 func doAction(flags parsedFlags) error {
-	err := processFileLogger(log, flags)
+	err := shared.ProcessFileLogger(log, flags.logLevel, flags.logSave)
 	if err != nil {
 		return err
 	}
 
-	persisterCreator := storer.NewPersisterCreator()
-	args := storer.ArgsFullDBMerger{
-		DataMergerInstance:  storer.NewDataMerger(),
-		PersisterCreator:    persisterCreator,
-		OsOperationsHandler: path.NewOsOperationsHandler(),
-	}
-	fullDataMerger, err := storer.NewFullDBMerger(args)
+	report, err := shared.CreateConflictReport(flags.conflictReportPath)
 	if err != nil {
 		return err
 	}
+	defer func() {
+		log.LogIfError(report.Close())
+	}()
 
-	destDB, err := fullDataMerger.MergeDBs(flags.destPath, flags.sourcePaths...)
+	progressReporter, err := storer.NewProgressReporter(flags.progressInterval)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		log.LogIfError(progressReporter.Close())
+	}()
+
+	dataMerger, err := storer.NewDataMerger(storer.ArgsDataMerger{
+		ConflictPolicy:      flags.conflictPolicy,
+		ConflictReport:      report,
+		ProgressHandler:     progressReporter,
+		BatchSizeInBytes:    flags.batchSizeBytes,
+		NumReadAheadBatches: flags.readAheadBatches,
+	})
 	if err != nil {
 		return err
 	}
 
-	return destDB.Close()
-}
-
-func processFileLogger(log logger.Logger, flags parsedFlags) error {
-	var err error
-	if flags.logSave {
-		_, err = file.NewFileLogging(file.ArgsFileLogging{
-			WorkingDir:      "",
-			DefaultLogsPath: defaultLogsPath,
-			LogFilePrefix:   logFilePrefix,
-		})
-		if err != nil {
-			return fmt.Errorf("%w creating a log file", err)
-		}
+	persisterCreator, err := storer.NewPersisterCreator(flags.persisterArgs)
+	if err != nil {
+		return err
 	}
 
-	err = logger.SetLogLevel(flags.logLevel)
+	args := storer.ArgsFullDBMerger{
+		DataMergerInstance:  dataMerger,
+		PersisterCreator:    persisterCreator,
+		OsOperationsHandler: path.NewOsOperationsHandler(),
+		ProgressHandler:     progressReporter,
+	}
+	fullDataMerger, err := storer.NewFullDBMerger(args)
 	if err != nil {
 		return err
 	}
 
-	log.Trace("logger updated", "level", flags.logLevel)
+	destDB, err := fullDataMerger.MergeDBs(flags.destination, flags.sources...)
+	if err != nil {
+		return err
+	}
 
-	return nil
+	return destDB.Close()
 }
@@ -0,0 +1,208 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	logger "github.com/multiversx/mx-chain-logger-go"
+	"github.com/multiversx/mx-chain-tools-go/dbmerger/cmd/shared"
+	"github.com/multiversx/mx-chain-tools-go/dbmerger/common"
+	"github.com/multiversx/mx-chain-tools-go/dbmerger/path"
+	"github.com/multiversx/mx-chain-tools-go/dbmerger/storer"
+	"github.com/urfave/cli"
+)
+
+const sourcePathsDelimiter = ","
+
+var (
+	log = logger.GetOrCreate("main")
+
+	dest = cli.StringFlag{
+		Name:  "dest",
+		Usage: "This flag specifies the destination db root path, it must be an empty directory",
+		Value: "",
+	}
+	sources = cli.StringFlag{
+		Name:  "sources",
+		Usage: `This flag specifies the source node db root paths separated by ",". Example "-sources ` + strings.Join([]string{"node1/db", "node2/db", "node3/db"}, sourcePathsDelimiter) + "\"",
+		Value: "",
+	}
+	dbType = cli.StringFlag{
+		Name:  "db-type",
+		Usage: "This flag specifies the backend of the storage units of the source and destination node databases: " + common.DBTypesUsage(),
+		Value: string(common.LevelDB),
+	}
+
+	errEmptyPathProvided = errors.New("empty path provided")
+)
+
+type parsedFlags struct {
+	destPath           string
+	sourcePaths        []string
+	dbType             common.DBType
+	logLevel           string
+	logSave            bool
+	conflictPolicy     string
+	conflictReportPath string
+	batchSizeBytes     int
+	readAheadBatches   int
+	progressInterval   time.Duration
+	persisterArgs      storer.ArgsPersisterCreator
+}
+
+func main() {
+	app := cli.NewApp()
+	cli.AppHelpTemplate = shared.HelpTemplate
+	app.Name = "Node DB merger tool CLI App"
+	app.Version = "v1.0.0"
+	app.Usage = "This is the entry point for the node DB merge tool able to merge the db directories of 2 or more nodes of the same shard"
+	app.Flags = []cli.Flag{
+		dest,
+		sources,
+		dbType,
+		shared.LogLevel,
+		shared.LogSaveFile,
+		shared.ConflictPolicy,
+		shared.ConflictReport,
+		shared.BatchSizeBytes,
+		shared.ReadAheadBatches,
+		shared.ProgressInterval,
+		shared.PersisterMaxBatchSize,
+		shared.PersisterMaxOpenFiles,
+	}
+	app.Authors = []cli.Author{
+		{
+			Name:  "The MultiversX Team",
+			Email: "contact@multiversx.com",
+		},
+	}
+
+	app.Action = action
+
+	err := app.Run(os.Args)
+	if err != nil {
+		log.Error(err.Error())
+		os.Exit(1)
+	}
+}
+
+func action(ctx *cli.Context) {
+	flags, err := parseFlags(ctx)
+	if err != nil {
+		log.Error("cannot process input flags", "error", err)
+		return
+	}
+
+	err = doAction(flags)
+	if err != nil {
+		log.Error("cannot perform action", "error", err)
+		return
+	}
+
+	log.Info("action performed")
+}
+
+func parseFlags(ctx *cli.Context) (parsedFlags, error) {
+	sourcePaths := ctx.GlobalString(sources.Name)
+
+	flags := parsedFlags{
+		destPath:           ctx.GlobalString(dest.Name),
+		sourcePaths:        strings.Split(sourcePaths, sourcePathsDelimiter),
+		dbType:             common.DBType(ctx.GlobalString(dbType.Name)),
+		logLevel:           ctx.GlobalString(shared.LogLevel.Name),
+		logSave:            ctx.GlobalBool(shared.LogSaveFile.Name),
+		conflictPolicy:     ctx.GlobalString(shared.ConflictPolicy.Name),
+		conflictReportPath: ctx.GlobalString(shared.ConflictReport.Name),
+		batchSizeBytes:     ctx.GlobalInt(shared.BatchSizeBytes.Name),
+		readAheadBatches:   ctx.GlobalInt(shared.ReadAheadBatches.Name),
+		progressInterval:   time.Duration(ctx.GlobalInt(shared.ProgressInterval.Name)) * time.Second,
+		persisterArgs: storer.ArgsPersisterCreator{
+			BatchDelaySeconds: storer.DefaultBatchDelaySeconds,
+			MaxBatchSize:      ctx.GlobalInt(shared.PersisterMaxBatchSize.Name),
+			MaxOpenFiles:      ctx.GlobalInt(shared.PersisterMaxOpenFiles.Name),
+		},
+	}
+
+	// TODO add separate check functions
+	if len(flags.destPath) == 0 {
+		return parsedFlags{}, fmt.Errorf("%w for `dest` flag", errEmptyPathProvided)
+	}
+	for idx, src := range flags.sourcePaths {
+		if len(src) == 0 {
+			return parsedFlags{}, fmt.Errorf("%w for source flag with index %d", errEmptyPathProvided, idx)
+		}
+	}
+
+	return flags, nil
+}
+
+func doAction(flags parsedFlags) error {
+	err := shared.ProcessFileLogger(log, flags.logLevel, flags.logSave)
+	if err != nil {
+		return err
+	}
+
+	report, err := shared.CreateConflictReport(flags.conflictReportPath)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		log.LogIfError(report.Close())
+	}()
+
+	progressReporter, err := storer.NewProgressReporter(flags.progressInterval)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		log.LogIfError(progressReporter.Close())
+	}()
+
+	dataMerger, err := storer.NewDataMerger(storer.ArgsDataMerger{
+		ConflictPolicy:      flags.conflictPolicy,
+		ConflictReport:      report,
+		ProgressHandler:     progressReporter,
+		BatchSizeInBytes:    flags.batchSizeBytes,
+		NumReadAheadBatches: flags.readAheadBatches,
+	})
+	if err != nil {
+		return err
+	}
+
+	persisterCreator, err := storer.NewPersisterCreator(flags.persisterArgs)
+	if err != nil {
+		return err
+	}
+
+	osOperationsHandler := path.NewOsOperationsHandler()
+	argsFullDBMerger := storer.ArgsFullDBMerger{
+		DataMergerInstance:  dataMerger,
+		PersisterCreator:    persisterCreator,
+		OsOperationsHandler: osOperationsHandler,
+		ProgressHandler:     progressReporter,
+	}
+	fullDataMerger, err := storer.NewFullDBMerger(argsFullDBMerger)
+	if err != nil {
+		return err
+	}
+
+	argsNodeDBMerger := storer.ArgsNodeDBMerger{
+		FullDBMergerInstance: fullDataMerger,
+		OsOperationsHandler:  osOperationsHandler,
+		DBType:               flags.dbType,
+	}
+	nodeDataMerger, err := storer.NewNodeDBMerger(argsNodeDBMerger)
+	if err != nil {
+		return err
+	}
+
+	parsers := make([]storer.NodeDBParser, 0, len(flags.sourcePaths))
+	for _, sourcePath := range flags.sourcePaths {
+		parsers = append(parsers, path.NewParser(sourcePath))
+	}
+
+	return nodeDataMerger.MergeNodeDBs(flags.destPath, parsers...)
+}
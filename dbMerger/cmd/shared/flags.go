@@ -0,0 +1,93 @@
+package shared
+
+import (
+	"strings"
+
+	logger "github.com/multiversx/mx-chain-logger-go"
+	"github.com/multiversx/mx-chain-tools-go/dbmerger/storer"
+	"github.com/urfave/cli"
+)
+
+// The flags shared by the database tools
+var (
+	// LogLevel defines a flag for setting the logger levels
+	LogLevel = cli.StringFlag{
+		Name: "log-level",
+		Usage: "This flag specifies the logger `level(s)`. It can contain multiple comma-separated value. For example" +
+			", if set to *:INFO the logs for all packages will have the INFO level. However, if set to *:INFO,api:DEBUG" +
+			" the logs for all packages will have the INFO level, excepting the api package which will receive a DEBUG" +
+			" log level.",
+		Value: "*:" + logger.LogDebug.String(),
+	}
+	// LogSaveFile defines a flag for enabling the log saving
+	LogSaveFile = cli.BoolFlag{
+		Name:  "log-save",
+		Usage: "Boolean option for enabling log saving. If set, it will automatically save all the logs into a file.",
+	}
+	// ConflictPolicy defines a flag for setting how the conflicting keys are solved
+	ConflictPolicy = cli.StringFlag{
+		Name: "conflict-policy",
+		Usage: "This flag specifies what happens when a key already exists in the destination with a different value: " +
+			strings.Join([]string{storer.ConflictPolicyLastWins, storer.ConflictPolicyFirstWins, storer.ConflictPolicyFailOnConflict, storer.ConflictPolicyKeepLargerValue}, ", "),
+		Value: storer.ConflictPolicyLastWins,
+	}
+	// ConflictReport defines a flag for setting the path of the conflicts CSV report
+	ConflictReport = cli.StringFlag{
+		Name:  "conflict-report",
+		Usage: "This flag specifies the path of the CSV file where the conflicting keys are listed together with the hashes of both values. If not set, the conflicts are only counted",
+		Value: "",
+	}
+	// BatchSizeBytes defines a flag for setting the size of the merged batches
+	BatchSizeBytes = cli.IntFlag{
+		Name:  "batch-size-bytes",
+		Usage: "This flag specifies the size, in bytes, of the batches of key-values read from the sources and written in the destination",
+		Value: 4 * 1024 * 1024,
+	}
+	// ReadAheadBatches defines a flag for setting how many batches are read in advance from every source
+	ReadAheadBatches = cli.IntFlag{
+		Name:  "read-ahead-batches",
+		Usage: "This flag specifies how many batches are read in advance from every source, all the sources being read in parallel",
+		Value: 4,
+	}
+	// ProgressInterval defines a flag for setting the interval between the progress logs
+	ProgressInterval = cli.IntFlag{
+		Name:  "progress-interval",
+		Usage: "This flag specifies the interval, in seconds, between the progress logs. 0 disables the progress logs",
+		Value: 30,
+	}
+	// PersisterMaxBatchSize defines a flag for setting the maximum number of key-values of a LevelDB write
+	PersisterMaxBatchSize = cli.IntFlag{
+		Name:  "persister-max-batch-size",
+		Usage: "This flag specifies the maximum number of key-values the LevelDB persisters write with a single write",
+		Value: 10000,
+	}
+	// PersisterBatchDelay defines a flag for setting the interval at which the LevelDB persisters write their batch
+	PersisterBatchDelay = cli.IntFlag{
+		Name:  "persister-batch-delay",
+		Usage: "This flag specifies the interval, in seconds, at which the LevelDB persisters write their internal batch",
+		Value: storer.DefaultBatchDelaySeconds,
+	}
+	// PersisterMaxOpenFiles defines a flag for setting the maximum number of files a LevelDB persister keeps open
+	PersisterMaxOpenFiles = cli.IntFlag{
+		Name:  "persister-max-open-files",
+		Usage: "This flag specifies the maximum number of files every LevelDB persister keeps open",
+		Value: 10,
+	}
+)
+
+// HelpTemplate is the help template of the database tools
+const HelpTemplate = `NAME:
+   {{.Name}} - {{.Usage}}
+USAGE:
+   {{.HelpName}} {{if .VisibleFlags}}[global options]{{end}}
+   {{if len .Authors}}
+AUTHOR:
+   {{range .Authors}}{{ . }}{{end}}
+   {{end}}{{if .Commands}}
+GLOBAL OPTIONS:
+   {{range .VisibleFlags}}{{.}}
+   {{end}}
+VERSION:
+   {{.Version}}
+   {{end}}
+`
@@ -0,0 +1,51 @@
+package shared
+
+import (
+	"fmt"
+
+	logger "github.com/multiversx/mx-chain-logger-go"
+	"github.com/multiversx/mx-chain-logger-go/file"
+	"github.com/multiversx/mx-chain-tools-go/dbmerger/storer"
+)
+
+const defaultLogsPath = "logs"
+const logFilePrefix = "log"
+
+// ClosableConflictReport is a conflict report that has to be closed at the end of the merge
+type ClosableConflictReport interface {
+	storer.ConflictReport
+	Close() error
+}
+
+// CreateConflictReport creates the CSV conflict report, or a disabled one if the report path is empty
+func CreateConflictReport(reportPath string) (ClosableConflictReport, error) {
+	if len(reportPath) == 0 {
+		return storer.NewDisabledConflictReport(), nil
+	}
+
+	return storer.NewFileConflictReport(reportPath)
+}
+
+// ProcessFileLogger sets the logger levels and, if required, saves the logs into a file
+func ProcessFileLogger(log logger.Logger, logLevel string, logSave bool) error {
+	var err error
+	if logSave {
+		_, err = file.NewFileLogging(file.ArgsFileLogging{
+			WorkingDir:      "",
+			DefaultLogsPath: defaultLogsPath,
+			LogFilePrefix:   logFilePrefix,
+		})
+		if err != nil {
+			return fmt.Errorf("%w creating a log file", err)
+		}
+	}
+
+	err = logger.SetLogLevel(logLevel)
+	if err != nil {
+		return err
+	}
+
+	log.Trace("logger updated", "level", logLevel)
+
+	return nil
+}
@@ -0,0 +1,65 @@
+package common
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrInvalidNumberOfDBTypes signals that the number of the provided backends does not match the number of the paths
+var ErrInvalidNumberOfDBTypes = errors.New("invalid number of database backends")
+
+// DBType is the backend of a persister, named as in the node's storage configuration
+type DBType string
+
+const (
+	// LevelDB is the LevelDB backend
+	LevelDB DBType = "LvlDB"
+	// LevelDBSerial is the LevelDB backend serializing all the operations
+	LevelDBSerial DBType = "LvlDBSerial"
+	// FileKV is a simple backend holding all the key-values in memory and saving them in a single file on close, meant
+	// for tests and small databases
+	FileKV DBType = "FileKV"
+)
+
+// DBTypes returns all the supported persister backends
+func DBTypes() []DBType {
+	return []DBType{LevelDB, LevelDBSerial, FileKV}
+}
+
+// DBTypesNames returns the names of all the supported persister backends
+func DBTypesNames() []string {
+	names := make([]string, 0, len(DBTypes()))
+	for _, dbType := range DBTypes() {
+		names = append(names, string(dbType))
+	}
+
+	return names
+}
+
+// DBTypesUsage returns the supported persister backends, as listed in the usage of the command line flags
+func DBTypesUsage() string {
+	return strings.Join(DBTypesNames(), ", ") + fmt.Sprintf(" (%s loads the whole database in memory, it is meant for tests "+
+		"and small databases, not for converting node databases)", FileKV)
+}
+
+// ParseDBTypes returns the backend of every path from the provided backends names, a single backend being used for all
+// the paths
+func ParseDBTypes(names []string, numPaths int) ([]DBType, error) {
+	if len(names) != 1 && len(names) != numPaths {
+		return nil, fmt.Errorf("%w, provided %d backends for %d paths", ErrInvalidNumberOfDBTypes, len(names), numPaths)
+	}
+
+	dbTypes := make([]DBType, 0, numPaths)
+	for i := 0; i < numPaths; i++ {
+		dbTypes = append(dbTypes, DBType(names[i%len(names)]))
+	}
+
+	return dbTypes, nil
+}
+
+// DBPath holds the path of a database and its backend
+type DBPath struct {
+	Path string
+	Type DBType
+}
@@ -0,0 +1,43 @@
+package common
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDBTypesUsage(t *testing.T) {
+	t.Parallel()
+
+	usage := DBTypesUsage()
+	assert.True(t, strings.HasPrefix(usage, "LvlDB, LvlDBSerial, FileKV "))
+	assert.True(t, strings.Contains(usage, "not for converting node databases"))
+}
+
+func TestParseDBTypes(t *testing.T) {
+	t.Parallel()
+
+	t.Run("invalid number of backends should error", func(t *testing.T) {
+		t.Parallel()
+
+		dbTypes, err := ParseDBTypes([]string{"LvlDB", "FileKV"}, 3)
+		assert.Nil(t, dbTypes)
+		assert.True(t, errors.Is(err, ErrInvalidNumberOfDBTypes))
+	})
+	t.Run("single backend should be used for all the paths", func(t *testing.T) {
+		t.Parallel()
+
+		dbTypes, err := ParseDBTypes([]string{"FileKV"}, 2)
+		assert.Nil(t, err)
+		assert.Equal(t, []DBType{FileKV, FileKV}, dbTypes)
+	})
+	t.Run("a backend for every path", func(t *testing.T) {
+		t.Parallel()
+
+		dbTypes, err := ParseDBTypes([]string{"LvlDB", "LvlDBSerial"}, 2)
+		assert.Nil(t, err)
+		assert.Equal(t, []DBType{LevelDB, LevelDBSerial}, dbTypes)
+	})
+}
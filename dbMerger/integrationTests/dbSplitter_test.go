@@ -0,0 +1,107 @@
+package integrationTests
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/multiversx/mx-chain-go/sharding"
+	"github.com/multiversx/mx-chain-tools-go/dbmerger/common"
+	"github.com/multiversx/mx-chain-tools-go/dbmerger/path"
+	"github.com/multiversx/mx-chain-tools-go/dbmerger/storer"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDBSplitterByShardThenMergeShouldRecreateTheSource(t *testing.T) {
+	persisterCreator := createPersisterCreator(t)
+	writeChecker := NewDBDataWriteChecker()
+	sourcePath := createDBAndAddData(t, persisterCreator, writeChecker, common.LevelDB, 100)
+
+	shardCoordinator, err := sharding.NewMultiShardCoordinator(3, 0)
+	assert.Nil(t, err)
+
+	destinations := make([]storer.SplitDestination, 0, shardCoordinator.NumberOfShards())
+	for shardID := uint32(0); shardID < shardCoordinator.NumberOfShards(); shardID++ {
+		filter, errCreate := storer.CreateKeyFilter(fmt.Sprintf("shard:%d", shardID), shardCoordinator)
+		assert.Nil(t, errCreate)
+
+		destinations = append(destinations, storer.SplitDestination{
+			DBPath: common.DBPath{Path: t.TempDir(), Type: common.LevelDB},
+			Filter: filter,
+		})
+	}
+
+	splitter := createDBSplitter(t, persisterCreator)
+	err = splitter.SplitDB(sourcePath, destinations...)
+	assert.Nil(t, err)
+
+	numKeys := 0
+	for shardID, destination := range destinations {
+		db, errCreate := persisterCreator.CreatePersister(destination.DBPath)
+		assert.Nil(t, errCreate)
+
+		db.RangeKeys(func(key []byte, val []byte) bool {
+			numKeys++
+			assert.Equal(t, uint32(shardID), shardCoordinator.ComputeId(key))
+			return true
+		})
+		_ = db.Close()
+	}
+	assert.Equal(t, 100, numKeys)
+
+	fullDataMerger := createFullDBMerger(t, persisterCreator)
+	dest, err := fullDataMerger.MergeDBs(
+		common.DBPath{Path: t.TempDir(), Type: common.LevelDB},
+		destinations[0].DBPath, destinations[1].DBPath, destinations[2].DBPath,
+	)
+	assert.Nil(t, err)
+
+	writeChecker.CheckDB(t, dest)
+	_ = dest.Close()
+}
+
+func TestDBSplitterFilterByPrefix(t *testing.T) {
+	persisterCreator := createPersisterCreator(t)
+	writeChecker := NewDBDataWriteChecker()
+	sourcePath := createDBAndAddData(t, persisterCreator, writeChecker, common.LevelDBSerial, 30)
+
+	// the keys are formatted as key_<counter>, so the prefix "key_1" matches key_1 and key_10 ... key_19
+	filter, err := storer.NewPrefixKeyFilter([]byte("key_1"))
+	assert.Nil(t, err)
+	// the subset is written with another backend than the source one
+	destPath := common.DBPath{Path: t.TempDir(), Type: common.FileKV}
+
+	splitter := createDBSplitter(t, persisterCreator)
+	err = splitter.SplitDB(sourcePath, storer.SplitDestination{DBPath: destPath, Filter: filter})
+	assert.Nil(t, err)
+
+	db, err := persisterCreator.CreatePersister(destPath)
+	assert.Nil(t, err)
+	defer func() {
+		_ = db.Close()
+	}()
+
+	keys := make(map[string]struct{})
+	db.RangeKeys(func(key []byte, val []byte) bool {
+		keys[string(key)] = struct{}{}
+		return true
+	})
+	assert.Equal(t, 11, len(keys))
+	for i := 10; i < 20; i++ {
+		_, found := keys[fmt.Sprintf(keyFormat, i)]
+		assert.True(t, found)
+	}
+}
+
+func createDBSplitter(tb testing.TB, persisterCreator storer.PersisterCreator) storer.DBSplitter {
+	progressReporter, err := storer.NewProgressReporter(0)
+	assert.Nil(tb, err)
+
+	splitter, err := storer.NewDBSplitter(storer.ArgsDBSplitter{
+		PersisterCreator:    persisterCreator,
+		OsOperationsHandler: path.NewOsOperationsHandler(),
+		ProgressHandler:     progressReporter,
+	})
+	assert.Nil(tb, err)
+
+	return splitter
+}
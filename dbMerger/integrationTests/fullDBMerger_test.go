@@ -3,36 +3,91 @@ package integrationTests
 import (
 	"testing"
 
+	"github.com/multiversx/mx-chain-tools-go/dbmerger/common"
 	"github.com/multiversx/mx-chain-tools-go/dbmerger/path"
 	"github.com/multiversx/mx-chain-tools-go/dbmerger/storer"
 	"github.com/stretchr/testify/assert"
 )
 
 func TestFullDBMergerWith3Persisters(t *testing.T) {
-	persisterCreator := storer.NewPersisterCreator()
+	persisterCreator := createPersisterCreator(t)
 	writeChecker := NewDBDataWriteChecker()
 
-	dbPath1 := createDBAndAddData(t, persisterCreator, writeChecker, 10)
-	dbPath2 := createDBAndAddData(t, persisterCreator, writeChecker, 20)
-	dbPath3 := createDBAndAddData(t, persisterCreator, writeChecker, 30)
-	dbPathDest := t.TempDir()
+	dbPath1 := createDBAndAddData(t, persisterCreator, writeChecker, common.LevelDB, 10)
+	dbPath2 := createDBAndAddData(t, persisterCreator, writeChecker, common.LevelDB, 20)
+	dbPath3 := createDBAndAddData(t, persisterCreator, writeChecker, common.LevelDB, 30)
+	dbPathDest := common.DBPath{Path: t.TempDir(), Type: common.LevelDB}
+
+	fullDataMerger := createFullDBMerger(t, persisterCreator)
+
+	dest, err := fullDataMerger.MergeDBs(dbPathDest, dbPath1, dbPath2, dbPath3)
+	assert.Nil(t, err)
+
+	writeChecker.CheckDB(t, dest)
+}
+
+func TestFullDBMergerWithDifferentBackends(t *testing.T) {
+	for _, destType := range common.DBTypes() {
+		persisterCreator := createPersisterCreator(t)
+		writeChecker := NewDBDataWriteChecker()
+
+		dbPath1 := createDBAndAddData(t, persisterCreator, writeChecker, common.LevelDB, 10)
+		dbPath2 := createDBAndAddData(t, persisterCreator, writeChecker, common.LevelDBSerial, 20)
+		dbPath3 := createDBAndAddData(t, persisterCreator, writeChecker, common.FileKV, 30)
+		dbPathDest := common.DBPath{Path: t.TempDir(), Type: destType}
+
+		fullDataMerger := createFullDBMerger(t, persisterCreator)
+
+		dest, err := fullDataMerger.MergeDBs(dbPathDest, dbPath1, dbPath2, dbPath3)
+		assert.Nil(t, err)
+		assert.Nil(t, dest.Close())
+
+		// reopening the destination checks the data was written with the destination backend
+		dest, err = persisterCreator.CreatePersister(dbPathDest)
+		assert.Nil(t, err)
+		writeChecker.CheckDB(t, dest)
+		_ = dest.Close()
+	}
+}
+
+func createPersisterCreator(tb testing.TB) storer.PersisterCreator {
+	persisterCreator, err := storer.NewPersisterCreator(storer.ArgsPersisterCreator{
+		BatchDelaySeconds: 2,
+		MaxBatchSize:      100,
+		MaxOpenFiles:      10,
+	})
+	assert.Nil(tb, err)
+
+	return persisterCreator
+}
+
+func createFullDBMerger(tb testing.TB, persisterCreator storer.PersisterCreator) storer.FullDBMerger {
+	progressReporter, err := storer.NewProgressReporter(0)
+	assert.Nil(tb, err)
+
+	dataMerger, err := storer.NewDataMerger(storer.ArgsDataMerger{
+		ConflictPolicy:      storer.ConflictPolicyFailOnConflict,
+		ConflictReport:      storer.NewDisabledConflictReport(),
+		ProgressHandler:     progressReporter,
+		BatchSizeInBytes:    64,
+		NumReadAheadBatches: 2,
+	})
+	assert.Nil(tb, err)
 
 	args := storer.ArgsFullDBMerger{
-		DataMergerInstance:  storer.NewDataMerger(),
+		DataMergerInstance:  dataMerger,
 		PersisterCreator:    persisterCreator,
 		OsOperationsHandler: path.NewOsOperationsHandler(),
+		ProgressHandler:     progressReporter,
 	}
 	fullDataMerger, err := storer.NewFullDBMerger(args)
-	assert.Nil(t, err)
-
-	dest, err := fullDataMerger.MergeDBs(dbPathDest, dbPath1, dbPath2, dbPath3)
-	assert.Nil(t, err)
+	assert.Nil(tb, err)
 
-	writeChecker.CheckDB(t, dest)
+	return fullDataMerger
 }
 
-func createDBAndAddData(tb testing.TB, persisterCreator storer.PersisterCreator, writeChecker *dbDataWriteChecker, numData int) string {
-	dbPath := tb.TempDir()
+func createDBAndAddData(tb testing.TB, persisterCreator storer.PersisterCreator, writeChecker *dbDataWriteChecker, dbType common.DBType, numData int) common.DBPath {
+	dbPath := common.DBPath{Path: tb.TempDir(), Type: dbType}
 	db, err := persisterCreator.CreatePersister(dbPath)
 	assert.Nil(tb, err)
 	writeChecker.AddDataToDB(db, numData)
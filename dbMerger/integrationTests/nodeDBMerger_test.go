@@ -0,0 +1,63 @@
+package integrationTests
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/multiversx/mx-chain-tools-go/dbmerger/common"
+	"github.com/multiversx/mx-chain-tools-go/dbmerger/path"
+	"github.com/multiversx/mx-chain-tools-go/dbmerger/storer"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNodeDBMergerWith2NodeDatabases(t *testing.T) {
+	persisterCreator := createPersisterCreator(t)
+	osOperationsHandler := path.NewOsOperationsHandler()
+	epoch3Checker := NewDBDataWriteChecker()
+	epoch4Checker := NewDBDataWriteChecker()
+	staticChecker := NewDBDataWriteChecker()
+
+	dbRoot1 := t.TempDir()
+	createStorageUnitAndAddData(t, persisterCreator, epoch3Checker, filepath.Join(dbRoot1, "1/Epoch_3/Shard_0/BlockHeaders"), 10)
+	createStorageUnitAndAddData(t, persisterCreator, epoch4Checker, filepath.Join(dbRoot1, "1/Epoch_4/Shard_0/BlockHeaders"), 10)
+	createStorageUnitAndAddData(t, persisterCreator, staticChecker, filepath.Join(dbRoot1, "1/Static/Shard_0/AccountsTrie"), 10)
+
+	dbRoot2 := t.TempDir()
+	createStorageUnitAndAddData(t, persisterCreator, epoch4Checker, filepath.Join(dbRoot2, "1/Epoch_4/Shard_0/BlockHeaders"), 20)
+	createStorageUnitAndAddData(t, persisterCreator, staticChecker, filepath.Join(dbRoot2, "1/Static/Shard_0/AccountsTrie"), 20)
+
+	fullDataMerger := createFullDBMerger(t, persisterCreator)
+
+	nodeDataMerger, err := storer.NewNodeDBMerger(storer.ArgsNodeDBMerger{
+		FullDBMergerInstance: fullDataMerger,
+		OsOperationsHandler:  osOperationsHandler,
+		DBType:               common.LevelDB,
+	})
+	assert.Nil(t, err)
+
+	dbRootDest := t.TempDir()
+	err = nodeDataMerger.MergeNodeDBs(dbRootDest, path.NewParser(dbRoot1), path.NewParser(dbRoot2))
+	assert.Nil(t, err)
+
+	destParser := path.NewParser(dbRootDest)
+	assert.Nil(t, destParser.ParseDirectory())
+	assert.Equal(t, []uint64{3, 4}, destParser.Epochs())
+
+	checkStorageUnit(t, persisterCreator, epoch3Checker, filepath.Join(dbRootDest, "1/Epoch_3/Shard_0/BlockHeaders"))
+	checkStorageUnit(t, persisterCreator, epoch4Checker, filepath.Join(dbRootDest, "1/Epoch_4/Shard_0/BlockHeaders"))
+	checkStorageUnit(t, persisterCreator, staticChecker, filepath.Join(dbRootDest, "1/Static/Shard_0/AccountsTrie"))
+}
+
+func createStorageUnitAndAddData(tb testing.TB, persisterCreator storer.PersisterCreator, writeChecker *dbDataWriteChecker, dbPath string, numData int) {
+	db, err := persisterCreator.CreatePersister(common.DBPath{Path: dbPath, Type: common.LevelDB})
+	assert.Nil(tb, err)
+	writeChecker.AddDataToDB(db, numData)
+	_ = db.Close()
+}
+
+func checkStorageUnit(tb testing.TB, persisterCreator storer.PersisterCreator, writeChecker *dbDataWriteChecker, dbPath string) {
+	db, err := persisterCreator.CreatePersister(common.DBPath{Path: dbPath, Type: common.LevelDB})
+	assert.Nil(tb, err)
+	writeChecker.CheckDB(tb, db)
+	_ = db.Close()
+}
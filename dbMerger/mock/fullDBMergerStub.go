@@ -0,0 +1,25 @@
+package mock
+
+import (
+	"github.com/multiversx/mx-chain-go/storage"
+	"github.com/multiversx/mx-chain-tools-go/dbmerger/common"
+)
+
+// FullDBMergerStub -
+type FullDBMergerStub struct {
+	MergeDBsCalled func(destination common.DBPath, sources ...common.DBPath) (storage.Persister, error)
+}
+
+// MergeDBs -
+func (stub *FullDBMergerStub) MergeDBs(destination common.DBPath, sources ...common.DBPath) (storage.Persister, error) {
+	if stub.MergeDBsCalled != nil {
+		return stub.MergeDBsCalled(destination, sources...)
+	}
+
+	return NewPersisterMock(), nil
+}
+
+// IsInterfaceNil -
+func (stub *FullDBMergerStub) IsInterfaceNil() bool {
+	return stub == nil
+}
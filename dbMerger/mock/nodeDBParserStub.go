@@ -0,0 +1,50 @@
+package mock
+
+// NodeDBParserStub -
+type NodeDBParserStub struct {
+	ParseDirectoryCalled func() error
+	DBRootPathValue      string
+	ChainIDValue         string
+	ShardIDValue         uint64
+	EpochsValue          []uint64
+	HighestEpochValue    uint64
+}
+
+// ParseDirectory -
+func (stub *NodeDBParserStub) ParseDirectory() error {
+	if stub.ParseDirectoryCalled != nil {
+		return stub.ParseDirectoryCalled()
+	}
+
+	return nil
+}
+
+// DBRootPath -
+func (stub *NodeDBParserStub) DBRootPath() string {
+	return stub.DBRootPathValue
+}
+
+// ChainID -
+func (stub *NodeDBParserStub) ChainID() string {
+	return stub.ChainIDValue
+}
+
+// ShardID -
+func (stub *NodeDBParserStub) ShardID() uint64 {
+	return stub.ShardIDValue
+}
+
+// Epochs -
+func (stub *NodeDBParserStub) Epochs() []uint64 {
+	return stub.EpochsValue
+}
+
+// HighestEpoch -
+func (stub *NodeDBParserStub) HighestEpoch() uint64 {
+	return stub.HighestEpochValue
+}
+
+// IsInterfaceNil -
+func (stub *NodeDBParserStub) IsInterfaceNil() bool {
+	return stub == nil
+}
@@ -4,6 +4,9 @@ package mock
 type OsOperationsHandlerStub struct {
 	CheckIfDirectoryIsEmptyCalled func(directory string) error
 	CopyDirectoryCalled           func(destination string, source string) error
+	ListDirectoriesCalled         func(directory string) ([]string, error)
+	CreateDirectoryCalled         func(directory string) error
+	GetDirectorySizeCalled        func(directory string) (uint64, error)
 }
 
 // CopyDirectory -
@@ -24,6 +27,33 @@ func (stub *OsOperationsHandlerStub) CheckIfDirectoryIsEmpty(directory string) e
 	return nil
 }
 
+// ListDirectories -
+func (stub *OsOperationsHandlerStub) ListDirectories(directory string) ([]string, error) {
+	if stub.ListDirectoriesCalled != nil {
+		return stub.ListDirectoriesCalled(directory)
+	}
+
+	return nil, nil
+}
+
+// CreateDirectory -
+func (stub *OsOperationsHandlerStub) CreateDirectory(directory string) error {
+	if stub.CreateDirectoryCalled != nil {
+		return stub.CreateDirectoryCalled(directory)
+	}
+
+	return nil
+}
+
+// GetDirectorySize -
+func (stub *OsOperationsHandlerStub) GetDirectorySize(directory string) (uint64, error) {
+	if stub.GetDirectorySizeCalled != nil {
+		return stub.GetDirectorySizeCalled(directory)
+	}
+
+	return 0, nil
+}
+
 // IsInterfaceNil -
 func (stub *OsOperationsHandlerStub) IsInterfaceNil() bool {
 	return stub == nil
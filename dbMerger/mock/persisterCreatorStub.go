@@ -4,17 +4,28 @@ import (
 	"errors"
 
 	"github.com/multiversx/mx-chain-storage-go/types"
+	"github.com/multiversx/mx-chain-tools-go/dbmerger/common"
 )
 
 // PersisterCreatorStub -
 type PersisterCreatorStub struct {
-	CreatePersisterCalled func(path string) (types.Persister, error)
+	CreatePersisterCalled      func(dbPath common.DBPath) (types.Persister, error)
+	CreateBatchPersisterCalled func(dbPath common.DBPath) (types.Persister, error)
 }
 
 // CreatePersister -
-func (stub *PersisterCreatorStub) CreatePersister(path string) (types.Persister, error) {
+func (stub *PersisterCreatorStub) CreatePersister(dbPath common.DBPath) (types.Persister, error) {
 	if stub.CreatePersisterCalled != nil {
-		return stub.CreatePersisterCalled(path)
+		return stub.CreatePersisterCalled(dbPath)
+	}
+
+	return nil, errors.New("not implemented")
+}
+
+// CreateBatchPersister -
+func (stub *PersisterCreatorStub) CreateBatchPersister(dbPath common.DBPath) (types.Persister, error) {
+	if stub.CreateBatchPersisterCalled != nil {
+		return stub.CreateBatchPersisterCalled(dbPath)
 	}
 
 	return nil, errors.New("not implemented")
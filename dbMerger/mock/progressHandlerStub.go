@@ -0,0 +1,26 @@
+package mock
+
+// ProgressHandlerStub -
+type ProgressHandlerStub struct {
+	AddExpectedBytesCalled func(numBytes uint64)
+	AddCopiedCalled        func(numKeys uint64, numBytes uint64)
+}
+
+// AddExpectedBytes -
+func (stub *ProgressHandlerStub) AddExpectedBytes(numBytes uint64) {
+	if stub.AddExpectedBytesCalled != nil {
+		stub.AddExpectedBytesCalled(numBytes)
+	}
+}
+
+// AddCopied -
+func (stub *ProgressHandlerStub) AddCopied(numKeys uint64, numBytes uint64) {
+	if stub.AddCopiedCalled != nil {
+		stub.AddCopiedCalled(numKeys, numBytes)
+	}
+}
+
+// IsInterfaceNil -
+func (stub *ProgressHandlerStub) IsInterfaceNil() bool {
+	return stub == nil
+}
@@ -3,6 +3,7 @@ package path
 import (
 	"fmt"
 	"io"
+	"io/fs"
 	"os"
 	"path/filepath"
 	"syscall"
@@ -162,6 +163,54 @@ func (handler *osOperationsHandler) CheckIfDirectoryIsEmpty(directory string) er
 	return nil
 }
 
+// ListDirectories returns the sorted names of the directories contained in the provided directory
+func (handler *osOperationsHandler) ListDirectories(directory string) ([]string, error) {
+	entries, errReadDir := os.ReadDir(directory)
+	if errReadDir != nil {
+		return nil, fmt.Errorf("%w while reading the directory %s", errReadDir, directory)
+	}
+
+	names := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		if entry.IsDir() {
+			names = append(names, entry.Name())
+		}
+	}
+
+	return names, nil
+}
+
+// CreateDirectory creates the provided directory, together with its missing parents
+func (handler *osOperationsHandler) CreateDirectory(directory string) error {
+	return createIfNotExists(directory, dirPermMode)
+}
+
+// GetDirectorySize returns the total size of the files contained, recursively, in the provided directory
+func (handler *osOperationsHandler) GetDirectorySize(directory string) (uint64, error) {
+	size := uint64(0)
+	err := filepath.WalkDir(directory, func(_ string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !entry.Type().IsRegular() {
+			return nil
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+		size += uint64(info.Size())
+
+		return nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("%w while computing the size of the directory %s", err, directory)
+	}
+
+	return size, nil
+}
+
 // IsInterfaceNil returns true if there is no value under the interface
 func (handler *osOperationsHandler) IsInterfaceNil() bool {
 	return handler == nil
@@ -109,3 +109,50 @@ func readFileContent(tb testing.TB, path string) string {
 
 	return contents
 }
+
+func TestOsOperationsHandler_ListDirectories(t *testing.T) {
+	t.Parallel()
+
+	handler := NewOsOperationsHandler()
+
+	directories, err := handler.ListDirectories("./testdata/srcDir")
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"a", "b"}, directories)
+
+	directories, err = handler.ListDirectories("./testdata/missing")
+	assert.Nil(t, directories)
+	assert.NotNil(t, err)
+}
+
+func TestOsOperationsHandler_CreateDirectory(t *testing.T) {
+	t.Parallel()
+
+	workingDir := path.Join(t.TempDir(), "a", "b")
+
+	handler := NewOsOperationsHandler()
+	err := handler.CreateDirectory(workingDir)
+	assert.Nil(t, err)
+	assert.Nil(t, handler.CheckIfDirectoryIsEmpty(workingDir))
+
+	err = handler.CreateDirectory(workingDir)
+	assert.Nil(t, err)
+}
+
+func TestOsOperationsHandler_GetDirectorySize(t *testing.T) {
+	t.Parallel()
+
+	handler := NewOsOperationsHandler()
+
+	expectedSize := 0
+	for _, file := range []string{"a/1", "a/file2.file", "b/a.txt", "c.log"} {
+		expectedSize += len(readFileContent(t, path.Join("./testdata/srcDir", file)))
+	}
+
+	size, err := handler.GetDirectorySize("./testdata/srcDir")
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(expectedSize), size)
+
+	size, err = handler.GetDirectorySize("./testdata/missing")
+	assert.Equal(t, uint64(0), size)
+	assert.NotNil(t, err)
+}
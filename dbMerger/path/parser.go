@@ -24,6 +24,7 @@ type parser struct {
 	chainID               string
 	highestEpoch          uint64
 	lowestContinuousEpoch uint64
+	epochs                []uint64
 	foundStatic           bool
 	shardID               uint64
 }
@@ -77,6 +78,7 @@ func (p *parser) resetData() {
 	p.chainID = ""
 	p.highestEpoch = 0
 	p.lowestContinuousEpoch = 0
+	p.epochs = nil
 	p.foundStatic = false
 	p.shardID = 0
 }
@@ -131,6 +133,11 @@ func (p *parser) processEpochs(directories []os.DirEntry) error {
 		return epochs[i] > epochs[j]
 	})
 
+	p.epochs = make([]uint64, 0, len(epochs))
+	for i := len(epochs) - 1; i >= 0; i-- {
+		p.epochs = append(p.epochs, uint64(epochs[i]))
+	}
+
 	p.highestEpoch = uint64(epochs[0])
 	p.lowestContinuousEpoch = uint64(epochs[0])
 	for i := 1; i < len(epochs); i++ {
@@ -195,6 +202,22 @@ func (p *parser) LowestContinuousEpoch() uint64 {
 	return p.lowestContinuousEpoch
 }
 
+// Epochs returns all the parsed epoch values, in ascending order
+func (p *parser) Epochs() []uint64 {
+	p.mutData.RLock()
+	defer p.mutData.RUnlock()
+
+	epochs := make([]uint64, len(p.epochs))
+	copy(epochs, p.epochs)
+
+	return epochs
+}
+
+// DBRootPath returns the parsed db root path
+func (p *parser) DBRootPath() string {
+	return p.dbRootPath
+}
+
 // ShardID returns the parsed shard ID value
 func (p *parser) ShardID() uint64 {
 	p.mutData.RLock()
@@ -202,3 +225,18 @@ func (p *parser) ShardID() uint64 {
 
 	return p.shardID
 }
+
+// IsInterfaceNil returns true if there is no value under the interface
+func (p *parser) IsInterfaceNil() bool {
+	return p == nil
+}
+
+// EpochDirectory returns the path, relative to the db root path, of the provided epoch and shard directory
+func EpochDirectory(chainID string, epoch uint64, shardID uint64) string {
+	return path.Join(chainID, fmt.Sprintf("%s%d", epochPathPart, epoch), fmt.Sprintf("%s%d", shardPathPart, shardID))
+}
+
+// StaticDirectory returns the path, relative to the db root path, of the static directory of the provided shard
+func StaticDirectory(chainID string, shardID uint64) string {
+	return path.Join(chainID, staticPath, fmt.Sprintf("%s%d", shardPathPart, shardID))
+}
@@ -138,6 +138,7 @@ func TestParser_ParseDirectory(t *testing.T) {
 		assert.Equal(t, "1", p.ChainID())
 		assert.Equal(t, 726, int(p.HighestEpoch()))
 		assert.Equal(t, 724, int(p.LowestContinuousEpoch()))
+		assert.Equal(t, []uint64{724, 725, 726}, p.Epochs())
 		assert.Equal(t, uint64(1), p.ShardID())
 	})
 	t.Run("should work with not continuous epochs", func(t *testing.T) {
@@ -158,6 +159,14 @@ func TestParser_ParseDirectory(t *testing.T) {
 		assert.Equal(t, "1", p.ChainID())
 		assert.Equal(t, 726, int(p.HighestEpoch()))
 		assert.Equal(t, 723, int(p.LowestContinuousEpoch()))
+		assert.Equal(t, []uint64{0, 1, 721, 723, 724, 725, 726}, p.Epochs())
 		assert.Equal(t, uint64(1), p.ShardID())
 	})
 }
+
+func TestEpochAndStaticDirectories(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "1/Epoch_724/Shard_2", EpochDirectory("1", 724, 2))
+	assert.Equal(t, "1/Static/Shard_0", StaticDirectory("1", 0))
+}
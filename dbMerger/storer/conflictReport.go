@@ -0,0 +1,104 @@
+package storer
+
+import (
+	"encoding/csv"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"strconv"
+	"sync"
+)
+
+var conflictReportHeader = []string{"key", "source index", "existing value hash", "new value hash", "resolution"}
+
+// KeyConflict holds a key found with different values in the destination and in a source persister
+type KeyConflict struct {
+	Key               []byte
+	SourceIndex       int
+	ExistingValueHash []byte
+	NewValueHash      []byte
+	Resolution        string
+}
+
+type fileConflictReport struct {
+	mut    sync.Mutex
+	file   *os.File
+	writer *csv.Writer
+}
+
+// NewFileConflictReport creates a conflict report writing the conflicts, as CSV lines, in the provided file
+func NewFileConflictReport(filePath string) (*fileConflictReport, error) {
+	file, err := os.Create(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("%w while creating the conflict report file", err)
+	}
+
+	writer := csv.NewWriter(file)
+	err = writer.Write(conflictReportHeader)
+	if err != nil {
+		_ = file.Close()
+		return nil, err
+	}
+
+	return &fileConflictReport{
+		file:   file,
+		writer: writer,
+	}, nil
+}
+
+// AddConflict writes the provided conflict in the report file
+func (report *fileConflictReport) AddConflict(conflict *KeyConflict) error {
+	report.mut.Lock()
+	defer report.mut.Unlock()
+
+	return report.writer.Write([]string{
+		hex.EncodeToString(conflict.Key),
+		strconv.Itoa(conflict.SourceIndex),
+		hex.EncodeToString(conflict.ExistingValueHash),
+		hex.EncodeToString(conflict.NewValueHash),
+		conflict.Resolution,
+	})
+}
+
+// Close flushes the written conflicts and closes the report file
+func (report *fileConflictReport) Close() error {
+	report.mut.Lock()
+	defer report.mut.Unlock()
+
+	report.writer.Flush()
+	err := report.writer.Error()
+	if err != nil {
+		_ = report.file.Close()
+		return err
+	}
+
+	return report.file.Close()
+}
+
+// IsInterfaceNil returns true if there is no value under the interface
+func (report *fileConflictReport) IsInterfaceNil() bool {
+	return report == nil
+}
+
+type disabledConflictReport struct {
+}
+
+// NewDisabledConflictReport creates a conflict report that does not record anything
+func NewDisabledConflictReport() *disabledConflictReport {
+	return &disabledConflictReport{}
+}
+
+// AddConflict does nothing
+func (report *disabledConflictReport) AddConflict(_ *KeyConflict) error {
+	return nil
+}
+
+// Close does nothing
+func (report *disabledConflictReport) Close() error {
+	return nil
+}
+
+// IsInterfaceNil returns true if there is no value under the interface
+func (report *disabledConflictReport) IsInterfaceNil() bool {
+	return report == nil
+}
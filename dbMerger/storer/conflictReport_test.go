@@ -0,0 +1,53 @@
+package storer
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/multiversx/mx-chain-core-go/core/check"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFileConflictReport(t *testing.T) {
+	t.Parallel()
+
+	t.Run("invalid path should error", func(t *testing.T) {
+		t.Parallel()
+
+		report, err := NewFileConflictReport(filepath.Join(t.TempDir(), "missing", "report.csv"))
+		assert.True(t, check.IfNil(report))
+		assert.NotNil(t, err)
+	})
+	t.Run("should write the conflicts", func(t *testing.T) {
+		t.Parallel()
+
+		reportPath := filepath.Join(t.TempDir(), "report.csv")
+		report, err := NewFileConflictReport(reportPath)
+		assert.Nil(t, err)
+		assert.False(t, check.IfNil(report))
+
+		err = report.AddConflict(&KeyConflict{
+			Key:               []byte("key"),
+			SourceIndex:       2,
+			ExistingValueHash: []byte{0xaa},
+			NewValueHash:      []byte{0xbb},
+			Resolution:        resolutionKept,
+		})
+		assert.Nil(t, err)
+		assert.Nil(t, report.Close())
+
+		content, err := ioutil.ReadFile(reportPath)
+		assert.Nil(t, err)
+		assert.Equal(t, "key,source index,existing value hash,new value hash,resolution\n6b6579,2,aa,bb,kept\n", string(content))
+	})
+}
+
+func TestDisabledConflictReport(t *testing.T) {
+	t.Parallel()
+
+	report := NewDisabledConflictReport()
+	assert.False(t, check.IfNil(report))
+	assert.Nil(t, report.AddConflict(&KeyConflict{}))
+	assert.Nil(t, report.Close())
+}
@@ -1,7 +1,10 @@
 package storer
 
 import (
+	"bytes"
+	"crypto/sha256"
 	"fmt"
+	"sync"
 
 	"github.com/multiversx/mx-chain-core-go/core/check"
 	logger "github.com/multiversx/mx-chain-logger-go"
@@ -10,13 +13,82 @@ import (
 
 var log = logger.GetOrCreate("storer")
 
-// dataMerger is able to copy key by key all values from the provided sources persisters into the destination persister
+const (
+	// ConflictPolicyLastWins overwrites the existing value with the value of the source
+	ConflictPolicyLastWins = "last-wins"
+	// ConflictPolicyFirstWins keeps the existing value
+	ConflictPolicyFirstWins = "first-wins"
+	// ConflictPolicyFailOnConflict stops the merge at the first conflict
+	ConflictPolicyFailOnConflict = "fail-on-conflict"
+	// ConflictPolicyKeepLargerValue keeps the longest of the two values, the existing one if they have the same length
+	ConflictPolicyKeepLargerValue = "keep-larger-value"
+)
+
+const (
+	resolutionOverwritten = "overwritten"
+	resolutionKept        = "kept"
+	resolutionFailed      = "failed"
+)
+
+// ArgsDataMerger is the DTO used in the NewDataMerger constructor function
+type ArgsDataMerger struct {
+	ConflictPolicy      string
+	ConflictReport      ConflictReport
+	ProgressHandler     ProgressHandler
+	BatchSizeInBytes    int
+	NumReadAheadBatches int
+}
+
+// dataMerger is able to copy key by key all values from the provided sources persisters into the destination persister.
+// A key already existing in the destination with a different value is a conflict, solved by the configured policy and
+// recorded in the conflict report.
+// All the sources are read in parallel, in batches of key-values sized by bytes, while the batches are written in the
+// sources order, so the conflicts are solved in the same way as when merging the sources one by one
 type dataMerger struct {
+	conflictPolicy      string
+	conflictReport      ConflictReport
+	progressHandler     ProgressHandler
+	batchSizeInBytes    int
+	numReadAheadBatches int
+}
+
+type keyValuesBatch struct {
+	keys      [][]byte
+	values    [][]byte
+	sizeBytes int
 }
 
 // NewDataMerger returns a new instance of a data merger
-func NewDataMerger() *dataMerger {
-	return &dataMerger{}
+func NewDataMerger(args ArgsDataMerger) (*dataMerger, error) {
+	if check.IfNil(args.ConflictReport) {
+		return nil, fmt.Errorf("%w, ConflictReport", errNilComponent)
+	}
+	if check.IfNil(args.ProgressHandler) {
+		return nil, fmt.Errorf("%w, ProgressHandler", errNilComponent)
+	}
+	if args.BatchSizeInBytes < 1 {
+		return nil, fmt.Errorf("%w, BatchSizeInBytes %d", errInvalidValue, args.BatchSizeInBytes)
+	}
+	if args.NumReadAheadBatches < 0 {
+		return nil, fmt.Errorf("%w, NumReadAheadBatches %d", errInvalidValue, args.NumReadAheadBatches)
+	}
+
+	conflictPolicy := args.ConflictPolicy
+	switch conflictPolicy {
+	case "":
+		conflictPolicy = ConflictPolicyLastWins
+	case ConflictPolicyLastWins, ConflictPolicyFirstWins, ConflictPolicyFailOnConflict, ConflictPolicyKeepLargerValue:
+	default:
+		return nil, fmt.Errorf("%w %s", errInvalidConflictPolicy, conflictPolicy)
+	}
+
+	return &dataMerger{
+		conflictPolicy:      conflictPolicy,
+		conflictReport:      args.ConflictReport,
+		progressHandler:     args.ProgressHandler,
+		batchSizeInBytes:    args.BatchSizeInBytes,
+		numReadAheadBatches: args.NumReadAheadBatches,
+	}, nil
 }
 
 // MergeDBs will iterate over all provided sources and take all key-value pairs and write them in the destination persister
@@ -26,23 +98,85 @@ func (dm *dataMerger) MergeDBs(dest types.Persister, sources ...types.Persister)
 		return err
 	}
 
-	numKeys := 0
+	// closing the done channel stops the readers still running when the writing fails. The readers are waited for
+	// before returning, as the caller closes the sources afterwards
+	done := make(chan struct{})
+	wg := &sync.WaitGroup{}
+	defer func() {
+		close(done)
+		wg.Wait()
+	}()
 
+	sourcesBatches := make([]chan *keyValuesBatch, 0, len(sources))
 	for _, source := range sources {
-		copiedKeys, errMerge := mergeDB(dest, source)
-		if errMerge != nil {
-			return errMerge
-		}
+		batches := make(chan *keyValuesBatch, dm.numReadAheadBatches)
+		sourcesBatches = append(sourcesBatches, batches)
 
-		numKeys += copiedKeys
+		wg.Add(1)
+		go func(source types.Persister) {
+			defer wg.Done()
+
+			dm.readSource(source, batches, done)
+		}(source)
+	}
+
+	numKeys := 0
+	numConflicts := 0
+
+	for idx, batches := range sourcesBatches {
+		for batch := range batches {
+			foundConflicts, errWrite := dm.writeBatch(dest, batch, idx)
+			numConflicts += foundConflicts
+			if errWrite != nil {
+				return errWrite
+			}
+
+			numKeys += len(batch.keys)
+			dm.progressHandler.AddCopied(uint64(len(batch.keys)), uint64(batch.sizeBytes))
+		}
 	}
 
 	log.Debug("finished copying data",
-		"num source persisters", len(sources), "num key-values copied", numKeys)
+		"num source persisters", len(sources), "num key-values copied", numKeys, "num conflicts", numConflicts)
 
 	return nil
 }
 
+// readSource iterates over all the key-values of the source and sends them in batches, closing the batches channel at
+// the end
+func (dm *dataMerger) readSource(source types.Persister, batches chan<- *keyValuesBatch, done <-chan struct{}) {
+	defer close(batches)
+
+	sendBatch := func(batch *keyValuesBatch) bool {
+		select {
+		case batches <- batch:
+			return true
+		case <-done:
+			return false
+		}
+	}
+
+	batch := &keyValuesBatch{}
+	isStopped := false
+	source.RangeKeys(func(key []byte, val []byte) bool {
+		batch.keys = append(batch.keys, key)
+		batch.values = append(batch.values, val)
+		batch.sizeBytes += len(key) + len(val)
+		if batch.sizeBytes < dm.batchSizeInBytes {
+			return true
+		}
+
+		isStopped = !sendBatch(batch)
+		batch = &keyValuesBatch{}
+
+		return !isStopped
+	})
+
+	if !isStopped && len(batch.keys) > 0 {
+		sendBatch(batch)
+	}
+}
+
 func checkArgs(dest types.Persister, sources ...types.Persister) error {
 	if check.IfNil(dest) {
 		return fmt.Errorf("%w for the destination persister", errNilPersister)
@@ -56,20 +190,95 @@ func checkArgs(dest types.Persister, sources ...types.Persister) error {
 	return nil
 }
 
-func mergeDB(dest types.Persister, source types.Persister) (int, error) {
-	var foundErr error
-	numKeysCopied := 0
-	source.RangeKeys(func(key []byte, val []byte) bool {
-		numKeysCopied++
-		foundErr = dest.Put(key, val)
-		if foundErr != nil {
-			return false
+// writeBatch solves the conflicts of the batch key-values with the destination, then writes the key-values to be put
+// with a single write, if the destination supports it
+func (dm *dataMerger) writeBatch(dest types.Persister, batch *keyValuesBatch, sourceIndex int) (int, error) {
+	numConflicts := 0
+	keysToPut := make([][]byte, 0, len(batch.keys))
+	valuesToPut := make([][]byte, 0, len(batch.keys))
+	for idx, key := range batch.keys {
+		val := batch.values[idx]
+
+		existingVal, errGet := dest.Get(key)
+		if errGet != nil {
+			keysToPut = append(keysToPut, key)
+			valuesToPut = append(valuesToPut, val)
+			continue
+		}
+		if bytes.Equal(existingVal, val) {
+			continue
+		}
+
+		numConflicts++
+		shouldOverwrite, err := dm.resolveConflict(key, existingVal, val, sourceIndex)
+		if err != nil {
+			return numConflicts, err
+		}
+		if shouldOverwrite {
+			keysToPut = append(keysToPut, key)
+			valuesToPut = append(valuesToPut, val)
+		}
+	}
+
+	return numConflicts, putKeyValues(dest, keysToPut, valuesToPut)
+}
+
+func putKeyValues(dest types.Persister, keys [][]byte, values [][]byte) error {
+	if len(keys) == 0 {
+		return nil
+	}
+
+	batchDest, canPutBatch := dest.(batchPutter)
+	if canPutBatch {
+		return batchDest.PutBatch(keys, values)
+	}
+
+	for idx, key := range keys {
+		err := dest.Put(key, values[idx])
+		if err != nil {
+			return err
 		}
+	}
+
+	return nil
+}
+
+// resolveConflict applies the conflict policy, records the conflict and returns true if the existing value has to be
+// overwritten
+func (dm *dataMerger) resolveConflict(key []byte, existingVal []byte, newVal []byte, sourceIndex int) (bool, error) {
+	var errConflict error
+	shouldOverwrite := false
+	switch dm.conflictPolicy {
+	case ConflictPolicyLastWins:
+		shouldOverwrite = true
+	case ConflictPolicyKeepLargerValue:
+		shouldOverwrite = len(newVal) > len(existingVal)
+	case ConflictPolicyFailOnConflict:
+		errConflict = fmt.Errorf("%w, key %x, source index %d", errConflictingValues, key, sourceIndex)
+	}
+
+	resolution := resolutionKept
+	if shouldOverwrite {
+		resolution = resolutionOverwritten
+	}
+	if errConflict != nil {
+		resolution = resolutionFailed
+	}
 
-		return true
+	existingValueHash := sha256.Sum256(existingVal)
+	newValueHash := sha256.Sum256(newVal)
+	err := dm.conflictReport.AddConflict(&KeyConflict{
+		Key:               key,
+		SourceIndex:       sourceIndex,
+		ExistingValueHash: existingValueHash[:],
+		NewValueHash:      newValueHash[:],
+		Resolution:        resolution,
 	})
+	if err != nil {
+		return false, fmt.Errorf("%w while writing the conflict report", err)
+	}
 
-	return numKeysCopied, foundErr
+	return shouldOverwrite, errConflict
 }
 
 // IsInterfaceNil returns true if there is no value under the interface
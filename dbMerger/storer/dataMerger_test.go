@@ -1,20 +1,146 @@
 package storer
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
+	"fmt"
 	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/multiversx/mx-chain-core-go/core/check"
+	"github.com/multiversx/mx-chain-storage-go/types"
 	"github.com/multiversx/mx-chain-tools-go/dbmerger/mock"
 	"github.com/stretchr/testify/assert"
 )
 
+type conflictReportStub struct {
+	mut       sync.Mutex
+	conflicts []*KeyConflict
+	err       error
+}
+
+func (stub *conflictReportStub) AddConflict(conflict *KeyConflict) error {
+	stub.mut.Lock()
+	defer stub.mut.Unlock()
+
+	stub.conflicts = append(stub.conflicts, conflict)
+
+	return stub.err
+}
+
+func (stub *conflictReportStub) IsInterfaceNil() bool {
+	return stub == nil
+}
+
+type batchPersisterStub struct {
+	types.Persister
+	numPuts      int
+	batchesSizes []int
+}
+
+func (stub *batchPersisterStub) Put(key, val []byte) error {
+	stub.numPuts++
+
+	return stub.Persister.Put(key, val)
+}
+
+func (stub *batchPersisterStub) PutBatch(keys [][]byte, values [][]byte) error {
+	stub.batchesSizes = append(stub.batchesSizes, len(keys))
+	for idx, key := range keys {
+		_ = stub.Persister.Put(key, values[idx])
+	}
+
+	return nil
+}
+
+func createMockArgsDataMerger() ArgsDataMerger {
+	return ArgsDataMerger{
+		ConflictPolicy:      ConflictPolicyLastWins,
+		ConflictReport:      &conflictReportStub{},
+		ProgressHandler:     &mock.ProgressHandlerStub{},
+		BatchSizeInBytes:    1024,
+		NumReadAheadBatches: 2,
+	}
+}
+
 func TestNewDataMerger(t *testing.T) {
 	t.Parallel()
 
-	dm := NewDataMerger()
-	assert.False(t, check.IfNil(dm))
+	t.Run("nil ConflictReport", func(t *testing.T) {
+		t.Parallel()
+
+		args := createMockArgsDataMerger()
+		args.ConflictReport = nil
+		dm, err := NewDataMerger(args)
+
+		assert.True(t, check.IfNil(dm))
+		assert.True(t, errors.Is(err, errNilComponent))
+		assert.True(t, strings.Contains(err.Error(), "ConflictReport"))
+	})
+	t.Run("nil ProgressHandler", func(t *testing.T) {
+		t.Parallel()
+
+		args := createMockArgsDataMerger()
+		args.ProgressHandler = nil
+		dm, err := NewDataMerger(args)
+
+		assert.True(t, check.IfNil(dm))
+		assert.True(t, errors.Is(err, errNilComponent))
+		assert.True(t, strings.Contains(err.Error(), "ProgressHandler"))
+	})
+	t.Run("invalid BatchSizeInBytes", func(t *testing.T) {
+		t.Parallel()
+
+		args := createMockArgsDataMerger()
+		args.BatchSizeInBytes = 0
+		dm, err := NewDataMerger(args)
+
+		assert.True(t, check.IfNil(dm))
+		assert.True(t, errors.Is(err, errInvalidValue))
+		assert.True(t, strings.Contains(err.Error(), "BatchSizeInBytes"))
+	})
+	t.Run("invalid NumReadAheadBatches", func(t *testing.T) {
+		t.Parallel()
+
+		args := createMockArgsDataMerger()
+		args.NumReadAheadBatches = -1
+		dm, err := NewDataMerger(args)
+
+		assert.True(t, check.IfNil(dm))
+		assert.True(t, errors.Is(err, errInvalidValue))
+		assert.True(t, strings.Contains(err.Error(), "NumReadAheadBatches"))
+	})
+	t.Run("invalid conflict policy", func(t *testing.T) {
+		t.Parallel()
+
+		args := createMockArgsDataMerger()
+		args.ConflictPolicy = "random"
+		dm, err := NewDataMerger(args)
+
+		assert.True(t, check.IfNil(dm))
+		assert.True(t, errors.Is(err, errInvalidConflictPolicy))
+	})
+	t.Run("empty conflict policy defaults to last-wins", func(t *testing.T) {
+		t.Parallel()
+
+		args := createMockArgsDataMerger()
+		args.ConflictPolicy = ""
+		dm, err := NewDataMerger(args)
+
+		assert.Nil(t, err)
+		assert.Equal(t, ConflictPolicyLastWins, dm.conflictPolicy)
+	})
+	t.Run("should work", func(t *testing.T) {
+		t.Parallel()
+
+		dm, err := NewDataMerger(createMockArgsDataMerger())
+
+		assert.Nil(t, err)
+		assert.False(t, check.IfNil(dm))
+	})
 }
 
 func TestMergeDBs(t *testing.T) {
@@ -23,7 +149,7 @@ func TestMergeDBs(t *testing.T) {
 	t.Run("nil destination should error", func(t *testing.T) {
 		t.Parallel()
 
-		dm := NewDataMerger()
+		dm, _ := NewDataMerger(createMockArgsDataMerger())
 		err := dm.MergeDBs(nil)
 		assert.True(t, errors.Is(err, errNilPersister))
 		assert.True(t, strings.Contains(err.Error(), "for the destination persister"))
@@ -31,7 +157,7 @@ func TestMergeDBs(t *testing.T) {
 	t.Run("sources contains a nil persister should error", func(t *testing.T) {
 		t.Parallel()
 
-		dm := NewDataMerger()
+		dm, _ := NewDataMerger(createMockArgsDataMerger())
 		err := dm.MergeDBs(&mock.PersisterStub{}, nil)
 		assert.True(t, errors.Is(err, errNilPersister))
 		assert.True(t, strings.Contains(err.Error(), "for the source persister, index 0"))
@@ -43,7 +169,7 @@ func TestMergeDBs(t *testing.T) {
 	t.Run("empty sources list should not put", func(t *testing.T) {
 		t.Parallel()
 
-		dm := NewDataMerger()
+		dm, _ := NewDataMerger(createMockArgsDataMerger())
 		err := dm.MergeDBs(&mock.PersisterStub{
 			PutCalled: func(key, val []byte) error {
 				assert.Fail(t, "should have not called put")
@@ -72,7 +198,7 @@ func TestMergeDBs(t *testing.T) {
 
 		result := make(map[string]string)
 
-		dm := NewDataMerger()
+		dm, _ := NewDataMerger(createMockArgsDataMerger())
 		err := dm.MergeDBs(&mock.PersisterStub{
 			PutCalled: func(key, val []byte) error {
 				result[string(key)] = string(val)
@@ -99,7 +225,7 @@ func TestMergeDBs(t *testing.T) {
 			"key2": "val2",
 		}
 
-		dm := NewDataMerger()
+		dm, _ := NewDataMerger(createMockArgsDataMerger())
 		err := dm.MergeDBs(&mock.PersisterStub{
 			PutCalled: func(key, val []byte) error {
 				return expectedErr
@@ -112,6 +238,245 @@ func TestMergeDBs(t *testing.T) {
 	})
 }
 
+func TestMergeDBs_Batches(t *testing.T) {
+	t.Parallel()
+
+	t.Run("sources should be written in order", func(t *testing.T) {
+		t.Parallel()
+
+		sources := make([]types.Persister, 0)
+		for i := 0; i < 5; i++ {
+			source := mock.NewPersisterMock()
+			for j := 0; j < 100; j++ {
+				_ = source.Put([]byte(fmt.Sprintf("key%d", j)), []byte(fmt.Sprintf("source%d", i)))
+			}
+			sources = append(sources, source)
+		}
+
+		numKeys, numBytes := uint64(0), uint64(0)
+		args := createMockArgsDataMerger()
+		args.BatchSizeInBytes = 64
+		args.ProgressHandler = &mock.ProgressHandlerStub{
+			AddCopiedCalled: func(keys uint64, bytes uint64) {
+				numKeys += keys
+				numBytes += bytes
+			},
+		}
+		dm, _ := NewDataMerger(args)
+
+		dest := mock.NewPersisterMock()
+		err := dm.MergeDBs(dest, sources...)
+		assert.Nil(t, err)
+		assert.Equal(t, uint64(500), numKeys)
+		assert.Equal(t, uint64(5*(490+700)), numBytes)
+		for j := 0; j < 100; j++ {
+			val, errGet := dest.Get([]byte(fmt.Sprintf("key%d", j)))
+			assert.Nil(t, errGet)
+			assert.Equal(t, "source4", string(val))
+		}
+	})
+	t.Run("every batch should be written with a single write", func(t *testing.T) {
+		t.Parallel()
+
+		source := mock.NewPersisterMock()
+		for j := 0; j < 10; j++ {
+			_ = source.Put([]byte(fmt.Sprintf("key%d", j)), []byte("val"))
+		}
+		dest := &batchPersisterStub{Persister: mock.NewPersisterMock()}
+		_ = dest.Persister.Put([]byte("key0"), []byte("val"))
+
+		args := createMockArgsDataMerger()
+		args.BatchSizeInBytes = 35
+		dm, _ := NewDataMerger(args)
+
+		err := dm.MergeDBs(dest, source)
+		assert.Nil(t, err)
+		assert.Equal(t, 0, dest.numPuts)
+		// 5 key-values of 7 bytes per batch, the key already in the destination is not written again
+		assert.ElementsMatch(t, []int{4, 5}, dest.batchesSizes)
+		for j := 0; j < 10; j++ {
+			assert.Nil(t, dest.Has([]byte(fmt.Sprintf("key%d", j))))
+		}
+	})
+	t.Run("write error should stop the readers", func(t *testing.T) {
+		t.Parallel()
+
+		expectedErr := errors.New("expected error")
+		source := mock.NewPersisterMock()
+		for j := 0; j < 1000; j++ {
+			_ = source.Put([]byte(fmt.Sprintf("key%d", j)), []byte("val"))
+		}
+
+		args := createMockArgsDataMerger()
+		args.BatchSizeInBytes = 10
+		args.NumReadAheadBatches = 0
+		dm, _ := NewDataMerger(args)
+
+		numPuts := 0
+		dest := &mock.PersisterStub{
+			GetCalled: func(key []byte) ([]byte, error) {
+				return nil, errors.New("key not found")
+			},
+			PutCalled: func(key, val []byte) error {
+				numPuts++
+				return expectedErr
+			},
+		}
+		err := dm.MergeDBs(dest, source, source)
+		assert.Equal(t, expectedErr, err)
+		assert.Equal(t, 1, numPuts)
+	})
+	t.Run("destination failing mid-merge should wait for the readers", func(t *testing.T) {
+		t.Parallel()
+
+		expectedErr := errors.New("expected error")
+		mutReaders := sync.Mutex{}
+		numRunningReaders := 0
+		createSource := func() types.Persister {
+			return &mock.PersisterStub{
+				RangeKeysCalled: func(handler func(key []byte, val []byte) bool) {
+					mutReaders.Lock()
+					numRunningReaders++
+					mutReaders.Unlock()
+
+					defer func() {
+						time.Sleep(time.Millisecond * 10)
+
+						mutReaders.Lock()
+						numRunningReaders--
+						mutReaders.Unlock()
+					}()
+
+					for j := 0; j < 1000; j++ {
+						if !handler([]byte(fmt.Sprintf("key%d", j)), []byte("val")) {
+							return
+						}
+					}
+				},
+			}
+		}
+
+		args := createMockArgsDataMerger()
+		args.BatchSizeInBytes = 10
+		args.NumReadAheadBatches = 0
+		dm, _ := NewDataMerger(args)
+
+		numPuts := 0
+		dest := &mock.PersisterStub{
+			GetCalled: func(key []byte) ([]byte, error) {
+				return nil, errors.New("key not found")
+			},
+			PutCalled: func(key, val []byte) error {
+				numPuts++
+				if numPuts == 10 {
+					return expectedErr
+				}
+
+				return nil
+			},
+		}
+		err := dm.MergeDBs(dest, createSource(), createSource(), createSource())
+		assert.Equal(t, expectedErr, err)
+
+		mutReaders.Lock()
+		assert.Equal(t, 0, numRunningReaders)
+		mutReaders.Unlock()
+	})
+}
+
+func TestMergeDBs_Conflicts(t *testing.T) {
+	t.Parallel()
+
+	createPersister := func(data map[string]string) types.Persister {
+		persister := mock.NewPersisterMock()
+		for key, val := range data {
+			_ = persister.Put([]byte(key), []byte(val))
+		}
+
+		return persister
+	}
+	mergeWithPolicy := func(policy string, report *conflictReportStub) (types.Persister, error) {
+		dest := createPersister(map[string]string{"key1": "val1", "key2": "val2"})
+		args := createMockArgsDataMerger()
+		args.ConflictPolicy = policy
+		args.ConflictReport = report
+		dm, _ := NewDataMerger(args)
+
+		err := dm.MergeDBs(dest,
+			createPersister(map[string]string{"key1": "longer value1", "key2": "val2", "key3": "val3"}),
+			createPersister(map[string]string{"key2": "v2"}),
+		)
+
+		return dest, err
+	}
+	checkValues := func(t *testing.T, dest types.Persister, expected map[string]string) {
+		for key, val := range expected {
+			recovered, err := dest.Get([]byte(key))
+			assert.Nil(t, err)
+			assert.Equal(t, val, string(recovered))
+		}
+	}
+
+	t.Run("last-wins should overwrite", func(t *testing.T) {
+		t.Parallel()
+
+		report := &conflictReportStub{}
+		dest, err := mergeWithPolicy(ConflictPolicyLastWins, report)
+		assert.Nil(t, err)
+		checkValues(t, dest, map[string]string{"key1": "longer value1", "key2": "v2", "key3": "val3"})
+
+		assert.Equal(t, 2, len(report.conflicts))
+		hash1, hash2 := sha256.Sum256([]byte("val1")), sha256.Sum256([]byte("longer value1"))
+		assert.Equal(t, &KeyConflict{
+			Key:               []byte("key1"),
+			SourceIndex:       0,
+			ExistingValueHash: hash1[:],
+			NewValueHash:      hash2[:],
+			Resolution:        resolutionOverwritten,
+		}, report.conflicts[0])
+		assert.Equal(t, []byte("key2"), report.conflicts[1].Key)
+		assert.Equal(t, 1, report.conflicts[1].SourceIndex)
+	})
+	t.Run("first-wins should keep the existing values", func(t *testing.T) {
+		t.Parallel()
+
+		report := &conflictReportStub{}
+		dest, err := mergeWithPolicy(ConflictPolicyFirstWins, report)
+		assert.Nil(t, err)
+		checkValues(t, dest, map[string]string{"key1": "val1", "key2": "val2", "key3": "val3"})
+		assert.Equal(t, 2, len(report.conflicts))
+		assert.Equal(t, resolutionKept, report.conflicts[0].Resolution)
+		assert.Equal(t, resolutionKept, report.conflicts[1].Resolution)
+	})
+	t.Run("keep-larger-value should keep the longest values", func(t *testing.T) {
+		t.Parallel()
+
+		report := &conflictReportStub{}
+		dest, err := mergeWithPolicy(ConflictPolicyKeepLargerValue, report)
+		assert.Nil(t, err)
+		checkValues(t, dest, map[string]string{"key1": "longer value1", "key2": "val2", "key3": "val3"})
+		assert.Equal(t, resolutionOverwritten, report.conflicts[0].Resolution)
+		assert.Equal(t, resolutionKept, report.conflicts[1].Resolution)
+	})
+	t.Run("fail-on-conflict should error", func(t *testing.T) {
+		t.Parallel()
+
+		report := &conflictReportStub{}
+		_, err := mergeWithPolicy(ConflictPolicyFailOnConflict, report)
+		assert.True(t, errors.Is(err, errConflictingValues))
+		assert.True(t, strings.Contains(err.Error(), hex.EncodeToString([]byte("key1"))))
+		assert.Equal(t, 1, len(report.conflicts))
+		assert.Equal(t, resolutionFailed, report.conflicts[0].Resolution)
+	})
+	t.Run("conflict report errors should error", func(t *testing.T) {
+		t.Parallel()
+
+		expectedErr := errors.New("expected error")
+		_, err := mergeWithPolicy(ConflictPolicyFirstWins, &conflictReportStub{err: expectedErr})
+		assert.True(t, errors.Is(err, expectedErr))
+	})
+}
+
 func createPersisterStub(rangeMap map[string]string) *mock.PersisterStub {
 	return &mock.PersisterStub{
 		RangeKeysCalled: func(handler func(key []byte, val []byte) bool) {
@@ -0,0 +1,181 @@
+package storer
+
+import (
+	"fmt"
+
+	"github.com/multiversx/mx-chain-core-go/core/check"
+	"github.com/multiversx/mx-chain-storage-go/types"
+	"github.com/multiversx/mx-chain-tools-go/dbmerger/common"
+)
+
+// ArgsDBSplitter is the DTO used in the NewDBSplitter constructor function
+type ArgsDBSplitter struct {
+	PersisterCreator    PersisterCreator
+	OsOperationsHandler OsOperationsHandler
+	ProgressHandler     ProgressHandler
+}
+
+// SplitDestination is a database receiving the keys, and their values, matched by the filter
+type SplitDestination struct {
+	common.DBPath
+	Filter KeyFilter
+}
+
+// dbSplitter is the counterpart of the fullDBMerger: it copies key by key the data of one source database into
+// several new databases, every destination receiving only the keys matched by its filter. A key matched by several
+// filters is copied in all the corresponding destinations, while a key not matched by any filter is not copied
+type dbSplitter struct {
+	persisterCreator    PersisterCreator
+	osOperationsHandler OsOperationsHandler
+	progressHandler     ProgressHandler
+}
+
+// NewDBSplitter creates a new instance of type dbSplitter
+func NewDBSplitter(args ArgsDBSplitter) (*dbSplitter, error) {
+	if check.IfNil(args.PersisterCreator) {
+		return nil, fmt.Errorf("%w, PersisterCreator", errNilComponent)
+	}
+	if check.IfNil(args.OsOperationsHandler) {
+		return nil, fmt.Errorf("%w, OsOperationsHandler", errNilComponent)
+	}
+	if check.IfNil(args.ProgressHandler) {
+		return nil, fmt.Errorf("%w, ProgressHandler", errNilComponent)
+	}
+
+	return &dbSplitter{
+		persisterCreator:    args.PersisterCreator,
+		osOperationsHandler: args.OsOperationsHandler,
+		progressHandler:     args.ProgressHandler,
+	}, nil
+}
+
+// SplitDB copies the key-values of the source database in the destinations matching them. The destination paths
+// must be empty directories, the destinations backends can differ from the source backend
+func (splitter *dbSplitter) SplitDB(source common.DBPath, destinations ...SplitDestination) error {
+	err := splitter.checkDestinations(destinations)
+	if err != nil {
+		return err
+	}
+
+	dbPaths := []common.DBPath{source}
+	for _, destination := range destinations {
+		dbPaths = append(dbPaths, destination.DBPath)
+	}
+	err = checkDBPathsTypes(dbPaths)
+	if err != nil {
+		return err
+	}
+
+	size, err := splitter.osOperationsHandler.GetDirectorySize(source.Path)
+	if err != nil {
+		log.Debug("cannot compute the size of the source", "path", source.Path, "error", err.Error())
+	} else {
+		splitter.progressHandler.AddExpectedBytes(size)
+	}
+
+	sourcePersister, err := splitter.persisterCreator.CreatePersister(source)
+	if err != nil {
+		return fmt.Errorf("%w for source persister", err)
+	}
+
+	destPersisters, err := splitter.createDestinationPersisters(destinations)
+	if err != nil {
+		_ = sourcePersister.Close()
+		return err
+	}
+
+	err = splitter.copyMatchingData(sourcePersister, destPersisters, destinations)
+	errClose := closePersisters(append(destPersisters, sourcePersister))
+	if err != nil {
+		return err
+	}
+
+	return errClose
+}
+
+func (splitter *dbSplitter) checkDestinations(destinations []SplitDestination) error {
+	if len(destinations) == 0 {
+		return fmt.Errorf("%w, provided 0, minimum 1", errInvalidNumberOfDestinations)
+	}
+
+	for idx, destination := range destinations {
+		if check.IfNil(destination.Filter) {
+			return fmt.Errorf("%w, Filter for the destination with index %d", errNilComponent, idx)
+		}
+
+		err := splitter.osOperationsHandler.CheckIfDirectoryIsEmpty(destination.Path)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (splitter *dbSplitter) createDestinationPersisters(destinations []SplitDestination) ([]types.Persister, error) {
+	destPersisters := make([]types.Persister, 0, len(destinations))
+	for idx, destination := range destinations {
+		destPersister, err := splitter.persisterCreator.CreatePersister(destination.DBPath)
+		if err != nil {
+			_ = closePersisters(destPersisters)
+			return nil, fmt.Errorf("%w for destination persister with index %d", err, idx)
+		}
+
+		destPersisters = append(destPersisters, destPersister)
+	}
+
+	return destPersisters, nil
+}
+
+func (splitter *dbSplitter) copyMatchingData(source types.Persister, destPersisters []types.Persister, destinations []SplitDestination) error {
+	numKeys := 0
+	numCopiedKeys := make([]int, len(destinations))
+
+	var errPut error
+	source.RangeKeys(func(key []byte, val []byte) bool {
+		numKeys++
+		for idx, destination := range destinations {
+			if !destination.Filter.IsMatching(key) {
+				continue
+			}
+
+			errPut = destPersisters[idx].Put(key, val)
+			if errPut != nil {
+				errPut = fmt.Errorf("%w for destination persister with index %d", errPut, idx)
+				return false
+			}
+			numCopiedKeys[idx]++
+		}
+
+		// the progress is measured on the source, the only size known before splitting
+		splitter.progressHandler.AddCopied(1, uint64(len(key)+len(val)))
+
+		return true
+	})
+	if errPut != nil {
+		return errPut
+	}
+
+	log.Debug("finished splitting data",
+		"num source key-values", numKeys, "num destinations", len(destinations), "num key-values copied", numCopiedKeys)
+
+	return nil
+}
+
+func closePersisters(persisters []types.Persister) error {
+	var lastErrFound error
+
+	for _, persister := range persisters {
+		err := persister.Close()
+		if err != nil {
+			lastErrFound = err
+		}
+	}
+
+	return lastErrFound
+}
+
+// IsInterfaceNil returns true if there is no value under the interface
+func (splitter *dbSplitter) IsInterfaceNil() bool {
+	return splitter == nil
+}
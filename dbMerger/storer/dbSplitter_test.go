@@ -0,0 +1,283 @@
+package storer
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/multiversx/mx-chain-core-go/core/check"
+	"github.com/multiversx/mx-chain-storage-go/types"
+	"github.com/multiversx/mx-chain-tools-go/dbmerger/common"
+	"github.com/multiversx/mx-chain-tools-go/dbmerger/mock"
+	"github.com/stretchr/testify/assert"
+)
+
+func createMockArgsDBSplitter() ArgsDBSplitter {
+	return ArgsDBSplitter{
+		PersisterCreator:    &mock.PersisterCreatorStub{},
+		OsOperationsHandler: &mock.OsOperationsHandlerStub{},
+		ProgressHandler:     &mock.ProgressHandlerStub{},
+	}
+}
+
+func createSplitDestinations(paths ...string) []SplitDestination {
+	destinations := make([]SplitDestination, 0, len(paths))
+	for _, path := range paths {
+		destinations = append(destinations, SplitDestination{
+			DBPath: createLevelDBPath(path),
+			Filter: NewAllKeysFilter(),
+		})
+	}
+
+	return destinations
+}
+
+func TestNewDBSplitter(t *testing.T) {
+	t.Parallel()
+
+	t.Run("nil PersisterCreator", func(t *testing.T) {
+		t.Parallel()
+
+		args := createMockArgsDBSplitter()
+		args.PersisterCreator = nil
+		splitter, err := NewDBSplitter(args)
+
+		assert.True(t, check.IfNil(splitter))
+		assert.True(t, errors.Is(err, errNilComponent))
+		assert.True(t, strings.Contains(err.Error(), "PersisterCreator"))
+	})
+	t.Run("nil OsOperationsHandler", func(t *testing.T) {
+		t.Parallel()
+
+		args := createMockArgsDBSplitter()
+		args.OsOperationsHandler = nil
+		splitter, err := NewDBSplitter(args)
+
+		assert.True(t, check.IfNil(splitter))
+		assert.True(t, errors.Is(err, errNilComponent))
+		assert.True(t, strings.Contains(err.Error(), "OsOperationsHandler"))
+	})
+	t.Run("nil ProgressHandler", func(t *testing.T) {
+		t.Parallel()
+
+		args := createMockArgsDBSplitter()
+		args.ProgressHandler = nil
+		splitter, err := NewDBSplitter(args)
+
+		assert.True(t, check.IfNil(splitter))
+		assert.True(t, errors.Is(err, errNilComponent))
+		assert.True(t, strings.Contains(err.Error(), "ProgressHandler"))
+	})
+	t.Run("should work", func(t *testing.T) {
+		t.Parallel()
+
+		splitter, err := NewDBSplitter(createMockArgsDBSplitter())
+
+		assert.False(t, check.IfNil(splitter))
+		assert.Nil(t, err)
+	})
+}
+
+func TestDBSplitter_SplitDB(t *testing.T) {
+	t.Parallel()
+
+	t.Run("no destinations should error", func(t *testing.T) {
+		t.Parallel()
+
+		splitter, _ := NewDBSplitter(createMockArgsDBSplitter())
+
+		err := splitter.SplitDB(createLevelDBPath("src"))
+		assert.True(t, errors.Is(err, errInvalidNumberOfDestinations))
+		assert.True(t, strings.Contains(err.Error(), "provided 0, minimum 1"))
+	})
+	t.Run("nil filter should error", func(t *testing.T) {
+		t.Parallel()
+
+		splitter, _ := NewDBSplitter(createMockArgsDBSplitter())
+		destinations := createSplitDestinations("dest0", "dest1")
+		destinations[1].Filter = nil
+
+		err := splitter.SplitDB(createLevelDBPath("src"), destinations...)
+		assert.True(t, errors.Is(err, errNilComponent))
+		assert.True(t, strings.Contains(err.Error(), "index 1"))
+	})
+	t.Run("unknown backend should error", func(t *testing.T) {
+		t.Parallel()
+
+		splitter, _ := NewDBSplitter(createMockArgsDBSplitter())
+		destinations := createSplitDestinations("dest0", "dest1")
+		destinations[1].Type = "MemoryDB"
+
+		err := splitter.SplitDB(createLevelDBPath("src"), destinations...)
+		assert.True(t, errors.Is(err, errUnknownDBType))
+		assert.True(t, strings.Contains(err.Error(), "MemoryDB for dest1"))
+	})
+	t.Run("destination not empty should error", func(t *testing.T) {
+		t.Parallel()
+
+		expectedErr := errors.New("expected error")
+		args := createMockArgsDBSplitter()
+		args.OsOperationsHandler = &mock.OsOperationsHandlerStub{
+			CheckIfDirectoryIsEmptyCalled: func(directory string) error {
+				return expectedErr
+			},
+		}
+		splitter, _ := NewDBSplitter(args)
+
+		err := splitter.SplitDB(createLevelDBPath("src"), createSplitDestinations("dest0")...)
+		assert.Equal(t, expectedErr, err)
+	})
+	t.Run("create source persister errors", func(t *testing.T) {
+		t.Parallel()
+
+		expectedErr := errors.New("expected error")
+		args := createMockArgsDBSplitter()
+		args.PersisterCreator = &mock.PersisterCreatorStub{
+			CreatePersisterCalled: func(dbPath common.DBPath) (types.Persister, error) {
+				return nil, expectedErr
+			},
+		}
+		splitter, _ := NewDBSplitter(args)
+
+		err := splitter.SplitDB(createLevelDBPath("src"), createSplitDestinations("dest0")...)
+		assert.True(t, errors.Is(err, expectedErr))
+		assert.True(t, strings.Contains(err.Error(), "for source persister"))
+	})
+	t.Run("create destination persister errors should close the opened persisters", func(t *testing.T) {
+		t.Parallel()
+
+		expectedErr := errors.New("expected error")
+		closed := make([]string, 0)
+		args := createMockArgsDBSplitter()
+		args.PersisterCreator = &mock.PersisterCreatorStub{
+			CreatePersisterCalled: func(dbPath common.DBPath) (types.Persister, error) {
+				if dbPath.Path == "dest1" {
+					return nil, expectedErr
+				}
+
+				persister := mock.NewPersisterMock()
+				persister.CloseCalled = func() error {
+					closed = append(closed, dbPath.Path)
+					return nil
+				}
+
+				return persister, nil
+			},
+		}
+		splitter, _ := NewDBSplitter(args)
+
+		err := splitter.SplitDB(createLevelDBPath("src"), createSplitDestinations("dest0", "dest1")...)
+		assert.True(t, errors.Is(err, expectedErr))
+		assert.True(t, strings.Contains(err.Error(), "for destination persister with index 1"))
+		assert.Equal(t, []string{"dest0", "src"}, closed)
+	})
+	t.Run("put errors should error", func(t *testing.T) {
+		t.Parallel()
+
+		expectedErr := errors.New("expected error")
+		source := mock.NewPersisterMock()
+		_ = source.Put([]byte("key"), []byte("value"))
+		args := createMockArgsDBSplitter()
+		args.PersisterCreator = &mock.PersisterCreatorStub{
+			CreatePersisterCalled: func(dbPath common.DBPath) (types.Persister, error) {
+				if dbPath.Path == "src" {
+					return source, nil
+				}
+
+				return &mock.PersisterStub{
+					PutCalled: func(key, val []byte) error {
+						return expectedErr
+					},
+				}, nil
+			},
+		}
+		splitter, _ := NewDBSplitter(args)
+
+		err := splitter.SplitDB(createLevelDBPath("src"), createSplitDestinations("dest0")...)
+		assert.True(t, errors.Is(err, expectedErr))
+		assert.True(t, strings.Contains(err.Error(), "for destination persister with index 0"))
+	})
+	t.Run("should work", func(t *testing.T) {
+		t.Parallel()
+
+		source := mock.NewPersisterMock()
+		_ = source.Put([]byte("a1"), []byte("value a1"))
+		_ = source.Put([]byte("a2"), []byte("value a2"))
+		_ = source.Put([]byte("b1"), []byte("value b1"))
+		_ = source.Put([]byte("c1"), []byte("value c1"))
+
+		persisters := map[string]*mock.PersisterStub{}
+		numClosed := 0
+		args := createMockArgsDBSplitter()
+		args.PersisterCreator = &mock.PersisterCreatorStub{
+			CreatePersisterCalled: func(dbPath common.DBPath) (types.Persister, error) {
+				if dbPath.Path == "src" {
+					source.CloseCalled = func() error {
+						numClosed++
+						return nil
+					}
+
+					return source, nil
+				}
+
+				data := make(map[string]string)
+				persisters[dbPath.Path] = &mock.PersisterStub{
+					PutCalled: func(key, val []byte) error {
+						data[string(key)] = string(val)
+						return nil
+					},
+					CloseCalled: func() error {
+						numClosed++
+						return nil
+					},
+					RangeKeysCalled: func(handler func(key []byte, val []byte) bool) {
+						for key, val := range data {
+							handler([]byte(key), []byte(val))
+						}
+					},
+				}
+
+				return persisters[dbPath.Path], nil
+			},
+		}
+		expectedBytes := uint64(0)
+		numCopiedKeys := uint64(0)
+		args.ProgressHandler = &mock.ProgressHandlerStub{
+			AddExpectedBytesCalled: func(numBytes uint64) {
+				expectedBytes += numBytes
+			},
+			AddCopiedCalled: func(numKeys uint64, numBytes uint64) {
+				numCopiedKeys += numKeys
+			},
+		}
+		args.OsOperationsHandler = &mock.OsOperationsHandlerStub{
+			GetDirectorySizeCalled: func(directory string) (uint64, error) {
+				return 100, nil
+			},
+		}
+		splitter, _ := NewDBSplitter(args)
+
+		prefixFilter, _ := NewPrefixKeyFilter([]byte("a"))
+		rangeFilter, _ := NewKeyRangeFilter([]byte("a2"), []byte("c"))
+		err := splitter.SplitDB(createLevelDBPath("src"),
+			SplitDestination{DBPath: createLevelDBPath("dest0"), Filter: prefixFilter},
+			SplitDestination{DBPath: createLevelDBPath("dest1"), Filter: rangeFilter},
+		)
+		assert.Nil(t, err)
+		assert.Equal(t, map[string]string{"a1": "value a1", "a2": "value a2"}, getPersisterStubData(persisters["dest0"]))
+		assert.Equal(t, map[string]string{"a2": "value a2", "b1": "value b1"}, getPersisterStubData(persisters["dest1"]))
+		assert.Equal(t, 3, numClosed)
+		assert.Equal(t, uint64(100), expectedBytes)
+		assert.Equal(t, uint64(4), numCopiedKeys)
+	})
+}
+
+func getPersisterStubData(persister *mock.PersisterStub) map[string]string {
+	data := make(map[string]string)
+	persister.RangeKeys(func(key []byte, val []byte) bool {
+		data[string(key)] = string(val)
+		return true
+	})
+
+	return data
+}
@@ -5,3 +5,12 @@ import "errors"
 var errNilPersister = errors.New("nil persister")
 var errInvalidNumberOfPersisters = errors.New("invalid number of persisters")
 var errNilComponent = errors.New("nil component")
+var errChainIDMismatch = errors.New("the node databases have different chain IDs")
+var errShardIDMismatch = errors.New("the node databases have different shard IDs")
+var errInvalidConflictPolicy = errors.New("invalid conflict policy")
+var errConflictingValues = errors.New("conflicting values found for the same key")
+var errInvalidValue = errors.New("invalid value")
+var errInvalidProgressInterval = errors.New("invalid progress interval")
+var errInvalidKeyFilter = errors.New("invalid key filter")
+var errInvalidNumberOfDestinations = errors.New("invalid number of destinations")
+var errUnknownDBType = errors.New("unknown persister backend")
@@ -0,0 +1,287 @@
+package storer
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+
+	"github.com/multiversx/mx-chain-storage-go/common"
+)
+
+const (
+	fileKVDataFileName = "data.kv"
+	fileKVDirPermMode  = os.FileMode(0755)
+	fileKVFilePermMode = os.FileMode(0644)
+)
+
+// fileKVPersister holds all the key-values in memory. The key-values are loaded from a single file when the persister
+// is opened and saved in the same file, sorted by key, when the persister is closed. Every key-value is stored as the
+// uvarint encoded key length, the key, the uvarint encoded value length and the value
+type fileKVPersister struct {
+	mut      sync.RWMutex
+	data     map[string][]byte
+	path     string
+	isClosed bool
+}
+
+func newFileKVPersister(path string) (*fileKVPersister, error) {
+	err := os.MkdirAll(path, fileKVDirPermMode)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := loadFileKVData(filepath.Join(path, fileKVDataFileName))
+	if err != nil {
+		return nil, fmt.Errorf("%w while loading the file key-value persister %s", err, path)
+	}
+
+	return &fileKVPersister{
+		data: data,
+		path: path,
+	}, nil
+}
+
+func loadFileKVData(filePath string) (map[string][]byte, error) {
+	data := make(map[string][]byte)
+
+	file, err := os.Open(filePath)
+	if errors.Is(err, os.ErrNotExist) {
+		return data, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+
+	reader := bufio.NewReader(file)
+	for {
+		key, errRead := readFileKVRecord(reader)
+		if errRead == io.EOF {
+			return data, nil
+		}
+		if errRead != nil {
+			return nil, errRead
+		}
+
+		val, errRead := readFileKVRecord(reader)
+		if errRead == io.EOF {
+			return nil, io.ErrUnexpectedEOF
+		}
+		if errRead != nil {
+			return nil, errRead
+		}
+
+		data[string(key)] = val
+	}
+}
+
+func readFileKVRecord(reader *bufio.Reader) ([]byte, error) {
+	length, err := binary.ReadUvarint(reader)
+	if err != nil {
+		return nil, err
+	}
+
+	record := make([]byte, length)
+	_, err = io.ReadFull(reader, record)
+	if err == io.EOF {
+		return nil, io.ErrUnexpectedEOF
+	}
+
+	return record, err
+}
+
+// Put adds the value to the key-values
+func (persister *fileKVPersister) Put(key, val []byte) error {
+	persister.mut.Lock()
+	defer persister.mut.Unlock()
+
+	if persister.isClosed {
+		return common.ErrDBIsClosed
+	}
+
+	persister.data[string(key)] = copyBytes(val)
+
+	return nil
+}
+
+// PutBatch adds all the provided key-values
+func (persister *fileKVPersister) PutBatch(keys [][]byte, values [][]byte) error {
+	if len(keys) != len(values) {
+		return fmt.Errorf("%w, %d keys and %d values", errInvalidValue, len(keys), len(values))
+	}
+
+	persister.mut.Lock()
+	defer persister.mut.Unlock()
+
+	if persister.isClosed {
+		return common.ErrDBIsClosed
+	}
+
+	for idx, key := range keys {
+		persister.data[string(key)] = copyBytes(values[idx])
+	}
+
+	return nil
+}
+
+// Get returns the value associated to the key
+func (persister *fileKVPersister) Get(key []byte) ([]byte, error) {
+	persister.mut.RLock()
+	defer persister.mut.RUnlock()
+
+	if persister.isClosed {
+		return nil, common.ErrDBIsClosed
+	}
+
+	val, found := persister.data[string(key)]
+	if !found {
+		return nil, common.ErrKeyNotFound
+	}
+
+	return copyBytes(val), nil
+}
+
+// Has returns nil if the given key is present
+func (persister *fileKVPersister) Has(key []byte) error {
+	_, err := persister.Get(key)
+
+	return err
+}
+
+// Remove removes the data associated to the given key
+func (persister *fileKVPersister) Remove(key []byte) error {
+	persister.mut.Lock()
+	defer persister.mut.Unlock()
+
+	if persister.isClosed {
+		return common.ErrDBIsClosed
+	}
+
+	delete(persister.data, string(key))
+
+	return nil
+}
+
+// RangeKeys iterates over all the key-values, sorted by key, until the handler returns false
+func (persister *fileKVPersister) RangeKeys(handler func(key []byte, val []byte) bool) {
+	if handler == nil {
+		return
+	}
+
+	persister.mut.RLock()
+	defer persister.mut.RUnlock()
+
+	for _, key := range persister.sortedKeys() {
+		shouldContinue := handler([]byte(key), copyBytes(persister.data[key]))
+		if !shouldContinue {
+			return
+		}
+	}
+}
+
+// Close saves the key-values in the data file
+func (persister *fileKVPersister) Close() error {
+	persister.mut.Lock()
+	defer persister.mut.Unlock()
+
+	if persister.isClosed {
+		return nil
+	}
+	persister.isClosed = true
+
+	return persister.save()
+}
+
+func (persister *fileKVPersister) save() error {
+	// the key-values are written in a temporary file then moved, so an interrupted save does not corrupt the data file
+	filePath := filepath.Join(persister.path, fileKVDataFileName)
+	tempFilePath := filePath + ".tmp"
+	file, err := os.OpenFile(tempFilePath, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, fileKVFilePermMode)
+	if err != nil {
+		return err
+	}
+
+	writer := bufio.NewWriter(file)
+	for _, key := range persister.sortedKeys() {
+		err = writeFileKVRecord(writer, []byte(key))
+		if err != nil {
+			_ = file.Close()
+			return err
+		}
+
+		err = writeFileKVRecord(writer, persister.data[key])
+		if err != nil {
+			_ = file.Close()
+			return err
+		}
+	}
+
+	err = writer.Flush()
+	if err != nil {
+		_ = file.Close()
+		return err
+	}
+
+	err = file.Close()
+	if err != nil {
+		return err
+	}
+
+	return os.Rename(tempFilePath, filePath)
+}
+
+func writeFileKVRecord(writer *bufio.Writer, record []byte) error {
+	lengthBuff := make([]byte, binary.MaxVarintLen64)
+	numBytes := binary.PutUvarint(lengthBuff, uint64(len(record)))
+
+	_, err := writer.Write(lengthBuff[:numBytes])
+	if err != nil {
+		return err
+	}
+
+	_, err = writer.Write(record)
+
+	return err
+}
+
+func (persister *fileKVPersister) sortedKeys() []string {
+	keys := make([]string, 0, len(persister.data))
+	for key := range persister.data {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
+
+// Destroy removes the key-values and the persister directory
+func (persister *fileKVPersister) Destroy() error {
+	persister.mut.Lock()
+	persister.isClosed = true
+	persister.data = make(map[string][]byte)
+	persister.mut.Unlock()
+
+	return persister.DestroyClosed()
+}
+
+// DestroyClosed removes the persister directory
+func (persister *fileKVPersister) DestroyClosed() error {
+	return os.RemoveAll(persister.path)
+}
+
+// IsInterfaceNil returns true if there is no value under the interface
+func (persister *fileKVPersister) IsInterfaceNil() bool {
+	return persister == nil
+}
+
+func copyBytes(buff []byte) []byte {
+	return append(make([]byte, 0, len(buff)), buff...)
+}
@@ -0,0 +1,123 @@
+package storer
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/multiversx/mx-chain-core-go/core/check"
+	"github.com/multiversx/mx-chain-storage-go/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewFileKVPersister(t *testing.T) {
+	t.Parallel()
+
+	t.Run("corrupted data file should error", func(t *testing.T) {
+		t.Parallel()
+
+		dir := t.TempDir()
+		// the key length is 5 but the file ends after 2 bytes
+		err := os.WriteFile(filepath.Join(dir, fileKVDataFileName), []byte{5, 'a', 'b'}, fileKVFilePermMode)
+		assert.Nil(t, err)
+
+		persister, err := newFileKVPersister(dir)
+		assert.True(t, check.IfNil(persister))
+		assert.NotNil(t, err)
+	})
+	t.Run("missing directory should be created", func(t *testing.T) {
+		t.Parallel()
+
+		dir := filepath.Join(t.TempDir(), "db")
+		persister, err := newFileKVPersister(dir)
+		assert.False(t, check.IfNil(persister))
+		assert.Nil(t, err)
+
+		_, err = os.Stat(dir)
+		assert.Nil(t, err)
+	})
+}
+
+func TestFileKVPersister_Operations(t *testing.T) {
+	t.Parallel()
+
+	persister, _ := newFileKVPersister(t.TempDir())
+
+	_, err := persister.Get([]byte("key"))
+	assert.True(t, errors.Is(err, common.ErrKeyNotFound))
+	assert.True(t, errors.Is(persister.Has([]byte("key")), common.ErrKeyNotFound))
+
+	val := []byte("value")
+	assert.Nil(t, persister.Put([]byte("key"), val))
+	val[0] = 'V'
+
+	recovered, err := persister.Get([]byte("key"))
+	assert.Nil(t, err)
+	assert.Equal(t, []byte("value"), recovered)
+	assert.Nil(t, persister.Has([]byte("key")))
+
+	assert.Nil(t, persister.Remove([]byte("key")))
+	assert.True(t, errors.Is(persister.Has([]byte("key")), common.ErrKeyNotFound))
+
+	assert.Nil(t, persister.Close())
+	assert.True(t, errors.Is(persister.Put([]byte("key"), val), common.ErrDBIsClosed))
+	_, err = persister.Get([]byte("key"))
+	assert.True(t, errors.Is(err, common.ErrDBIsClosed))
+	assert.Nil(t, persister.Close())
+}
+
+func TestFileKVPersister_CloseAndReopenShouldKeepTheData(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	persister, _ := newFileKVPersister(dir)
+	_ = persister.Put([]byte("key2"), []byte("value2"))
+	_ = persister.Put([]byte("key1"), []byte("value1"))
+	_ = persister.Put([]byte("empty"), []byte{})
+	_ = persister.Put([]byte("key3"), []byte("value3"))
+	_ = persister.Remove([]byte("key3"))
+	assert.Nil(t, persister.Close())
+
+	reopened, err := newFileKVPersister(dir)
+	assert.Nil(t, err)
+
+	keys := make([]string, 0)
+	values := make([]string, 0)
+	reopened.RangeKeys(func(key []byte, val []byte) bool {
+		keys = append(keys, string(key))
+		values = append(values, string(val))
+		return true
+	})
+	assert.Equal(t, []string{"empty", "key1", "key2"}, keys)
+	assert.Equal(t, []string{"", "value1", "value2"}, values)
+}
+
+func TestFileKVPersister_RangeKeysShouldStopWhenTheHandlerReturnsFalse(t *testing.T) {
+	t.Parallel()
+
+	persister, _ := newFileKVPersister(t.TempDir())
+	_ = persister.Put([]byte("a"), []byte("1"))
+	_ = persister.Put([]byte("b"), []byte("2"))
+
+	persister.RangeKeys(nil)
+
+	numCalls := 0
+	persister.RangeKeys(func(key []byte, val []byte) bool {
+		numCalls++
+		return false
+	})
+	assert.Equal(t, 1, numCalls)
+}
+
+func TestFileKVPersister_Destroy(t *testing.T) {
+	t.Parallel()
+
+	dir := filepath.Join(t.TempDir(), "db")
+	persister, _ := newFileKVPersister(dir)
+	_ = persister.Put([]byte("key"), []byte("value"))
+
+	assert.Nil(t, persister.Destroy())
+	_, err := os.Stat(dir)
+	assert.True(t, errors.Is(err, os.ErrNotExist))
+}
@@ -6,6 +6,7 @@ import (
 	"github.com/multiversx/mx-chain-core-go/core/check"
 	"github.com/multiversx/mx-chain-go/storage"
 	"github.com/multiversx/mx-chain-storage-go/types"
+	"github.com/multiversx/mx-chain-tools-go/dbmerger/common"
 )
 
 const minNumOfPersisters = 2
@@ -15,12 +16,14 @@ type ArgsFullDBMerger struct {
 	DataMergerInstance  DataMerger
 	PersisterCreator    PersisterCreator
 	OsOperationsHandler OsOperationsHandler
+	ProgressHandler     ProgressHandler
 }
 
 type fullDBMerger struct {
 	dataMergerInstance  DataMerger
 	persisterCreator    PersisterCreator
 	osOperationsHandler OsOperationsHandler
+	progressHandler     ProgressHandler
 }
 
 // NewFullDBMerger creates a new instance of type fullDBMerger
@@ -34,46 +37,66 @@ func NewFullDBMerger(args ArgsFullDBMerger) (*fullDBMerger, error) {
 	if check.IfNil(args.OsOperationsHandler) {
 		return nil, fmt.Errorf("%w, OsOperationsHandler", errNilComponent)
 	}
+	if check.IfNil(args.ProgressHandler) {
+		return nil, fmt.Errorf("%w, ProgressHandler", errNilComponent)
+	}
 
 	return &fullDBMerger{
 		dataMergerInstance:  args.DataMergerInstance,
 		persisterCreator:    args.PersisterCreator,
 		osOperationsHandler: args.OsOperationsHandler,
+		progressHandler:     args.ProgressHandler,
 	}, nil
 }
 
-// MergeDBs will merge all data from the source persister paths into a new storage persister
-func (fdm *fullDBMerger) MergeDBs(destinationPath string, sourcePaths ...string) (storage.Persister, error) {
-	if len(sourcePaths) < minNumOfPersisters {
-		return nil, fmt.Errorf("%w, provided %d, minimum %d", errInvalidNumberOfPersisters, len(sourcePaths), minNumOfPersisters)
+// MergeDBs will merge all data from the source persisters into a new storage persister. When the destination has the
+// same backend as the first source, the first source is copied at the OS level and only the next sources are merged
+// key by key, otherwise all the sources are merged key by key, converting them to the destination backend
+func (fdm *fullDBMerger) MergeDBs(destination common.DBPath, sources ...common.DBPath) (storage.Persister, error) {
+	if len(sources) < minNumOfPersisters {
+		return nil, fmt.Errorf("%w, provided %d, minimum %d", errInvalidNumberOfPersisters, len(sources), minNumOfPersisters)
 	}
 
-	err := fdm.osOperationsHandler.CheckIfDirectoryIsEmpty(destinationPath)
+	err := checkDBPathsTypes(append([]common.DBPath{destination}, sources...))
 	if err != nil {
 		return nil, err
 	}
 
-	err = fdm.osOperationsHandler.CopyDirectory(destinationPath, sourcePaths[0])
+	err = fdm.osOperationsHandler.CheckIfDirectoryIsEmpty(destination.Path)
 	if err != nil {
 		return nil, err
 	}
 
-	destPersister, err := fdm.persisterCreator.CreatePersister(destinationPath)
+	keyByKeySources := sources
+	if destination.Type == sources[0].Type {
+		err = fdm.osOperationsHandler.CopyDirectory(destination.Path, sources[0].Path)
+		if err != nil {
+			return nil, err
+		}
+
+		keyByKeySources = sources[1:]
+	}
+
+	fdm.addExpectedBytes(keyByKeySources)
+
+	destPersister, err := fdm.persisterCreator.CreateBatchPersister(destination)
 	if err != nil {
 		return nil, fmt.Errorf("%w for destination persister", err)
 	}
 
-	sourcePersisters, err := fdm.createSourcePersisters(sourcePaths...)
+	sourcePersisters, err := fdm.createSourcePersisters(keyByKeySources)
 	if err != nil {
+		_ = destPersister.Close()
 		return nil, err
 	}
 
 	err = fdm.dataMergerInstance.MergeDBs(destPersister, sourcePersisters...)
 	if err != nil {
+		_ = closePersisters(append(sourcePersisters, destPersister))
 		return nil, err
 	}
 
-	err = fdm.closeSourcePersisters(sourcePersisters)
+	err = closePersisters(sourcePersisters)
 	if err != nil {
 		return nil, err
 	}
@@ -81,31 +104,32 @@ func (fdm *fullDBMerger) MergeDBs(destinationPath string, sourcePaths ...string)
 	return destPersister, nil
 }
 
-func (fdm *fullDBMerger) createSourcePersisters(sourcePaths ...string) ([]types.Persister, error) {
-	sourcePersisters := make([]types.Persister, 0, len(sourcePaths)-1)
-	for i := 1; i < len(sourcePaths); i++ {
-		srcPersister, errPersister := fdm.persisterCreator.CreatePersister(sourcePaths[i])
-		if errPersister != nil {
-			return nil, fmt.Errorf("%w for source persister with index %d", errPersister, i)
+// addExpectedBytes reports the size of the sources copied key by key, used for estimating the remaining time
+func (fdm *fullDBMerger) addExpectedBytes(sources []common.DBPath) {
+	for _, source := range sources {
+		size, err := fdm.osOperationsHandler.GetDirectorySize(source.Path)
+		if err != nil {
+			log.Debug("cannot compute the size of the source", "path", source.Path, "error", err.Error())
+			continue
 		}
 
-		sourcePersisters = append(sourcePersisters, srcPersister)
+		fdm.progressHandler.AddExpectedBytes(size)
 	}
-
-	return sourcePersisters, nil
 }
 
-func (fdm *fullDBMerger) closeSourcePersisters(sourcePersisters []types.Persister) error {
-	var lastErrFound error
-
-	for _, persister := range sourcePersisters {
-		err := persister.Close()
-		if err != nil {
-			lastErrFound = err
+func (fdm *fullDBMerger) createSourcePersisters(sources []common.DBPath) ([]types.Persister, error) {
+	sourcePersisters := make([]types.Persister, 0, len(sources))
+	for _, source := range sources {
+		srcPersister, errPersister := fdm.persisterCreator.CreatePersister(source)
+		if errPersister != nil {
+			_ = closePersisters(sourcePersisters)
+			return nil, fmt.Errorf("%w for source persister %s", errPersister, source.Path)
 		}
+
+		sourcePersisters = append(sourcePersisters, srcPersister)
 	}
 
-	return lastErrFound
+	return sourcePersisters, nil
 }
 
 // IsInterfaceNil returns true if there is no value under the interface
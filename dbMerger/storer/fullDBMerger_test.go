@@ -7,6 +7,7 @@ import (
 
 	"github.com/multiversx/mx-chain-core-go/core/check"
 	"github.com/multiversx/mx-chain-storage-go/types"
+	"github.com/multiversx/mx-chain-tools-go/dbmerger/common"
 	"github.com/multiversx/mx-chain-tools-go/dbmerger/mock"
 	"github.com/stretchr/testify/assert"
 )
@@ -16,6 +17,7 @@ func createMockArgsFullDBMerger() ArgsFullDBMerger {
 		DataMergerInstance:  &mock.DataMergerStub{},
 		PersisterCreator:    &mock.PersisterCreatorStub{},
 		OsOperationsHandler: &mock.OsOperationsHandlerStub{},
+		ProgressHandler:     &mock.ProgressHandlerStub{},
 	}
 }
 
@@ -55,6 +57,17 @@ func TestNewFullDBMerger(t *testing.T) {
 		assert.True(t, errors.Is(err, errNilComponent))
 		assert.True(t, strings.Contains(err.Error(), "OsOperationsHandler"))
 	})
+	t.Run("nil ProgressHandler", func(t *testing.T) {
+		t.Parallel()
+
+		args := createMockArgsFullDBMerger()
+		args.ProgressHandler = nil
+		merger, err := NewFullDBMerger(args)
+
+		assert.True(t, check.IfNil(merger))
+		assert.True(t, errors.Is(err, errNilComponent))
+		assert.True(t, strings.Contains(err.Error(), "ProgressHandler"))
+	})
 	t.Run("should work", func(t *testing.T) {
 		t.Parallel()
 
@@ -75,16 +88,28 @@ func TestDataMerger_MergeDBs(t *testing.T) {
 		args := createMockArgsFullDBMerger()
 		merger, _ := NewFullDBMerger(args)
 
-		destPersister, err := merger.MergeDBs("dest")
+		destPersister, err := merger.MergeDBs(createLevelDBPath("dest"))
 		assert.True(t, check.IfNil(destPersister))
 		assert.True(t, errors.Is(err, errInvalidNumberOfPersisters))
 		assert.True(t, strings.Contains(err.Error(), "provided 0, minimum 2"))
 
-		destPersister, err = merger.MergeDBs("dest", "src1")
+		destPersister, err = merger.MergeDBs(createLevelDBPath("dest"), createLevelDBPaths("src1")...)
 		assert.True(t, check.IfNil(destPersister))
 		assert.True(t, errors.Is(err, errInvalidNumberOfPersisters))
 		assert.True(t, strings.Contains(err.Error(), "provided 1, minimum 2"))
 	})
+	t.Run("unknown backend should error", func(t *testing.T) {
+		t.Parallel()
+
+		merger, _ := NewFullDBMerger(createMockArgsFullDBMerger())
+
+		sources := createLevelDBPaths("src1", "src2")
+		sources[1].Type = "MemoryDB"
+		destPersister, err := merger.MergeDBs(createLevelDBPath("dest"), sources...)
+		assert.True(t, check.IfNil(destPersister))
+		assert.True(t, errors.Is(err, errUnknownDBType))
+		assert.True(t, strings.Contains(err.Error(), "MemoryDB for src2"))
+	})
 	t.Run("directory not empty", func(t *testing.T) {
 		t.Parallel()
 
@@ -97,7 +122,7 @@ func TestDataMerger_MergeDBs(t *testing.T) {
 		}
 		merger, _ := NewFullDBMerger(args)
 
-		destPersister, err := merger.MergeDBs("dest", "src1", "src2")
+		destPersister, err := merger.MergeDBs(createLevelDBPath("dest"), createLevelDBPaths("src1", "src2")...)
 		assert.True(t, check.IfNil(destPersister))
 		assert.Equal(t, expectedErr, err)
 	})
@@ -113,7 +138,7 @@ func TestDataMerger_MergeDBs(t *testing.T) {
 		}
 		merger, _ := NewFullDBMerger(args)
 
-		destPersister, err := merger.MergeDBs("dest", "src1", "src2")
+		destPersister, err := merger.MergeDBs(createLevelDBPath("dest"), createLevelDBPaths("src1", "src2")...)
 		assert.True(t, check.IfNil(destPersister))
 		assert.True(t, errors.Is(err, expectedErr))
 	})
@@ -123,17 +148,17 @@ func TestDataMerger_MergeDBs(t *testing.T) {
 		expectedErr := errors.New("expected error")
 		args := createMockArgsFullDBMerger()
 		args.PersisterCreator = &mock.PersisterCreatorStub{
-			CreatePersisterCalled: func(path string) (types.Persister, error) {
-				if path == "dest" {
-					return nil, expectedErr
-				}
-
+			CreatePersisterCalled: func(dbPath common.DBPath) (types.Persister, error) {
 				return mock.NewPersisterMock(), nil
 			},
+			CreateBatchPersisterCalled: func(dbPath common.DBPath) (types.Persister, error) {
+				assert.Equal(t, "dest", dbPath.Path)
+				return nil, expectedErr
+			},
 		}
 		merger, _ := NewFullDBMerger(args)
 
-		destPersister, err := merger.MergeDBs("dest", "src1", "src2")
+		destPersister, err := merger.MergeDBs(createLevelDBPath("dest"), createLevelDBPaths("src1", "src2")...)
 		assert.True(t, check.IfNil(destPersister))
 		assert.True(t, errors.Is(err, expectedErr))
 		assert.True(t, strings.Contains(err.Error(), "for destination persister"))
@@ -144,20 +169,23 @@ func TestDataMerger_MergeDBs(t *testing.T) {
 		expectedErr := errors.New("expected error")
 		args := createMockArgsFullDBMerger()
 		args.PersisterCreator = &mock.PersisterCreatorStub{
-			CreatePersisterCalled: func(path string) (types.Persister, error) {
-				if strings.Contains(path, "src") {
+			CreatePersisterCalled: func(dbPath common.DBPath) (types.Persister, error) {
+				if strings.Contains(dbPath.Path, "src") {
 					return nil, expectedErr
 				}
 
 				return mock.NewPersisterMock(), nil
 			},
+			CreateBatchPersisterCalled: func(dbPath common.DBPath) (types.Persister, error) {
+				return mock.NewPersisterMock(), nil
+			},
 		}
 		merger, _ := NewFullDBMerger(args)
 
-		destPersister, err := merger.MergeDBs("dest", "src1", "src2")
+		destPersister, err := merger.MergeDBs(createLevelDBPath("dest"), createLevelDBPaths("src1", "src2")...)
 		assert.True(t, check.IfNil(destPersister))
 		assert.True(t, errors.Is(err, expectedErr))
-		assert.True(t, strings.Contains(err.Error(), "for source persister with index 1"))
+		assert.True(t, strings.Contains(err.Error(), "for source persister src2"))
 	})
 	t.Run("data merge errors", func(t *testing.T) {
 		t.Parallel()
@@ -165,7 +193,10 @@ func TestDataMerger_MergeDBs(t *testing.T) {
 		expectedErr := errors.New("expected error")
 		args := createMockArgsFullDBMerger()
 		args.PersisterCreator = &mock.PersisterCreatorStub{
-			CreatePersisterCalled: func(path string) (types.Persister, error) {
+			CreatePersisterCalled: func(dbPath common.DBPath) (types.Persister, error) {
+				return mock.NewPersisterMock(), nil
+			},
+			CreateBatchPersisterCalled: func(dbPath common.DBPath) (types.Persister, error) {
 				return mock.NewPersisterMock(), nil
 			},
 		}
@@ -176,7 +207,7 @@ func TestDataMerger_MergeDBs(t *testing.T) {
 		}
 		merger, _ := NewFullDBMerger(args)
 
-		destPersister, err := merger.MergeDBs("dest", "src1", "src2", "src3")
+		destPersister, err := merger.MergeDBs(createLevelDBPath("dest"), createLevelDBPaths("src1", "src2", "src3")...)
 		assert.True(t, check.IfNil(destPersister))
 		assert.True(t, errors.Is(err, expectedErr))
 	})
@@ -187,6 +218,7 @@ func TestDataMerger_MergeDBs(t *testing.T) {
 		copyCalled := false
 		numPersistersCreated := 0
 		mergeDBCalled := false
+		expectedBytes := uint64(0)
 		args := createMockArgsFullDBMerger()
 		args.OsOperationsHandler = &mock.OsOperationsHandlerStub{
 			CopyDirectoryCalled: func(destination string, source string) error {
@@ -194,19 +226,29 @@ func TestDataMerger_MergeDBs(t *testing.T) {
 
 				return nil
 			},
+			GetDirectorySizeCalled: func(directory string) (uint64, error) {
+				return uint64(len(directory)) * 100, nil
+			},
 		}
-		args.PersisterCreator = &mock.PersisterCreatorStub{
-			CreatePersisterCalled: func(path string) (types.Persister, error) {
-				numPersistersCreated++
-				persisterMock := mock.NewPersisterMock()
-				persisterMock.CloseCalled = func() error {
-					numClosedPersisters++
-
-					return nil
-				}
-				return persisterMock, nil
+		args.ProgressHandler = &mock.ProgressHandlerStub{
+			AddExpectedBytesCalled: func(numBytes uint64) {
+				expectedBytes += numBytes
 			},
 		}
+		createPersister := func(dbPath common.DBPath) (types.Persister, error) {
+			numPersistersCreated++
+			persisterMock := mock.NewPersisterMock()
+			persisterMock.CloseCalled = func() error {
+				numClosedPersisters++
+
+				return nil
+			}
+			return persisterMock, nil
+		}
+		args.PersisterCreator = &mock.PersisterCreatorStub{
+			CreatePersisterCalled:      createPersister,
+			CreateBatchPersisterCalled: createPersister,
+		}
 		args.DataMergerInstance = &mock.DataMergerStub{
 			MergeDBsCalled: func(dest types.Persister, sources ...types.Persister) error {
 				assert.Equal(t, 2, len(sources))
@@ -218,12 +260,70 @@ func TestDataMerger_MergeDBs(t *testing.T) {
 		}
 		merger, _ := NewFullDBMerger(args)
 
-		destPersister, err := merger.MergeDBs("dest", "src1", "src2", "src3")
+		destPersister, err := merger.MergeDBs(createLevelDBPath("dest"), createLevelDBPaths("src1", "src2", "src3")...)
 		assert.False(t, check.IfNil(destPersister))
 		assert.Nil(t, err)
 		assert.True(t, copyCalled)
 		assert.Equal(t, 3, numPersistersCreated)
 		assert.True(t, mergeDBCalled)
 		assert.Equal(t, 2, numClosedPersisters) // 3 sources, 1 copied, 2 opened to copy key by key
+		assert.Equal(t, uint64(800), expectedBytes)
+	})
+	t.Run("different backends should merge all the sources key by key", func(t *testing.T) {
+		t.Parallel()
+
+		copyCalled := false
+		createdPersisters := make([]common.DBPath, 0)
+		expectedBytes := uint64(0)
+		args := createMockArgsFullDBMerger()
+		args.OsOperationsHandler = &mock.OsOperationsHandlerStub{
+			CopyDirectoryCalled: func(destination string, source string) error {
+				copyCalled = true
+
+				return nil
+			},
+			GetDirectorySizeCalled: func(directory string) (uint64, error) {
+				return 100, nil
+			},
+		}
+		args.ProgressHandler = &mock.ProgressHandlerStub{
+			AddExpectedBytesCalled: func(numBytes uint64) {
+				expectedBytes += numBytes
+			},
+		}
+		batchPersisters := make([]common.DBPath, 0)
+		args.PersisterCreator = &mock.PersisterCreatorStub{
+			CreatePersisterCalled: func(dbPath common.DBPath) (types.Persister, error) {
+				createdPersisters = append(createdPersisters, dbPath)
+
+				return mock.NewPersisterMock(), nil
+			},
+			CreateBatchPersisterCalled: func(dbPath common.DBPath) (types.Persister, error) {
+				batchPersisters = append(batchPersisters, dbPath)
+
+				return mock.NewPersisterMock(), nil
+			},
+		}
+		args.DataMergerInstance = &mock.DataMergerStub{
+			MergeDBsCalled: func(dest types.Persister, sources ...types.Persister) error {
+				assert.Equal(t, 2, len(sources))
+
+				return nil
+			},
+		}
+		merger, _ := NewFullDBMerger(args)
+
+		destination := common.DBPath{Path: "dest", Type: common.FileKV}
+		sources := []common.DBPath{
+			{Path: "src1", Type: common.LevelDB},
+			{Path: "src2", Type: common.LevelDBSerial},
+		}
+		destPersister, err := merger.MergeDBs(destination, sources...)
+		assert.False(t, check.IfNil(destPersister))
+		assert.Nil(t, err)
+		assert.False(t, copyCalled)
+		assert.Equal(t, []common.DBPath{destination}, batchPersisters)
+		assert.Equal(t, sources, createdPersisters)
+		assert.Equal(t, uint64(200), expectedBytes)
 	})
 }
@@ -1,7 +1,9 @@
 package storer
 
 import (
+	"github.com/multiversx/mx-chain-go/storage"
 	"github.com/multiversx/mx-chain-storage-go/types"
+	"github.com/multiversx/mx-chain-tools-go/dbmerger/common"
 )
 
 // DataMerger specify the operations supported by a component able to merge data between persisters
@@ -10,15 +12,73 @@ type DataMerger interface {
 	IsInterfaceNil() bool
 }
 
-// PersisterCreator is able to create a persister instance based on the provided path
+// ConflictReport is able to record the keys found with different values in the merged persisters
+type ConflictReport interface {
+	AddConflict(conflict *KeyConflict) error
+	IsInterfaceNil() bool
+}
+
+// ProgressHandler is able to track the progress of the merge or of the split
+type ProgressHandler interface {
+	AddExpectedBytes(numBytes uint64)
+	AddCopied(numKeys uint64, numBytes uint64)
+	IsInterfaceNil() bool
+}
+
+// PersisterCreator is able to create a persister instance based on the provided path and backend
 type PersisterCreator interface {
-	CreatePersister(path string) (types.Persister, error)
+	CreatePersister(dbPath common.DBPath) (types.Persister, error)
+	CreateBatchPersister(dbPath common.DBPath) (types.Persister, error)
 	IsInterfaceNil() bool
 }
 
+// batchPutter is implemented by the persisters able to write several key-values with a single write
+type batchPutter interface {
+	PutBatch(keys [][]byte, values [][]byte) error
+}
+
 // OsOperationsHandler is able to handle the os-level functions
 type OsOperationsHandler interface {
 	CheckIfDirectoryIsEmpty(directory string) error
 	CopyDirectory(destination string, source string) error
+	ListDirectories(directory string) ([]string, error)
+	CreateDirectory(directory string) error
+	GetDirectorySize(directory string) (uint64, error)
+	IsInterfaceNil() bool
+}
+
+// FullDBMerger is able to merge the databases found in the source paths into a new database
+type FullDBMerger interface {
+	MergeDBs(destination common.DBPath, sources ...common.DBPath) (storage.Persister, error)
+	IsInterfaceNil() bool
+}
+
+// DBSplitter is able to copy the data of a database into several new databases, filtered by key
+type DBSplitter interface {
+	SplitDB(source common.DBPath, destinations ...SplitDestination) error
+	IsInterfaceNil() bool
+}
+
+// NodeDBParser is able to parse the db directory of a node, holding a directory for every epoch and a static directory
+type NodeDBParser interface {
+	ParseDirectory() error
+	DBRootPath() string
+	ChainID() string
+	ShardID() uint64
+	Epochs() []uint64
+	HighestEpoch() uint64
+	IsInterfaceNil() bool
+}
+
+// KeyFilter is able to tell if a key has to be copied in a split destination
+type KeyFilter interface {
+	IsMatching(key []byte) bool
+	IsInterfaceNil() bool
+}
+
+// ShardCoordinator is able to compute the shard a key belongs to
+type ShardCoordinator interface {
+	ComputeId(address []byte) uint32
+	NumberOfShards() uint32
 	IsInterfaceNil() bool
 }
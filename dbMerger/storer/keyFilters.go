@@ -0,0 +1,206 @@
+package storer
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/multiversx/mx-chain-core-go/core"
+	"github.com/multiversx/mx-chain-core-go/core/check"
+)
+
+const (
+	// KeyFilterTypePrefix selects the keys starting with a hex encoded prefix, e.g. "prefix:0a0b"
+	KeyFilterTypePrefix = "prefix"
+	// KeyFilterTypeRange selects the keys between two hex encoded keys, the start included and the end excluded,
+	// e.g. "range:00-7f". Any of the two ends can be empty, meaning no bound
+	KeyFilterTypeRange = "range"
+	// KeyFilterTypeShard selects the keys assigned by the shard coordinator to a shard, e.g. "shard:1" or
+	// "shard:metachain"
+	KeyFilterTypeShard = "shard"
+	// KeyFilterTypeAll selects all the keys
+	KeyFilterTypeAll = "all"
+)
+
+const (
+	keyFilterTypeSeparator  = ":"
+	keyRangeSeparator       = "-"
+	metachainShardIDKeyword = "metachain"
+)
+
+type prefixKeyFilter struct {
+	prefix []byte
+}
+
+// NewPrefixKeyFilter creates a key filter matching the keys starting with the provided prefix
+func NewPrefixKeyFilter(prefix []byte) (*prefixKeyFilter, error) {
+	if len(prefix) == 0 {
+		return nil, fmt.Errorf("%w, empty prefix", errInvalidKeyFilter)
+	}
+
+	return &prefixKeyFilter{
+		prefix: prefix,
+	}, nil
+}
+
+// IsMatching returns true if the key starts with the filter prefix
+func (filter *prefixKeyFilter) IsMatching(key []byte) bool {
+	return bytes.HasPrefix(key, filter.prefix)
+}
+
+// IsInterfaceNil returns true if there is no value under the interface
+func (filter *prefixKeyFilter) IsInterfaceNil() bool {
+	return filter == nil
+}
+
+type keyRangeFilter struct {
+	start []byte
+	end   []byte
+}
+
+// NewKeyRangeFilter creates a key filter matching the keys greater than or equal to start and lower than end.
+// An empty end means no upper bound
+func NewKeyRangeFilter(start []byte, end []byte) (*keyRangeFilter, error) {
+	if len(end) > 0 && bytes.Compare(start, end) >= 0 {
+		return nil, fmt.Errorf("%w, the start %x is not lower than the end %x", errInvalidKeyFilter, start, end)
+	}
+
+	return &keyRangeFilter{
+		start: start,
+		end:   end,
+	}, nil
+}
+
+// IsMatching returns true if the key is in the filter range
+func (filter *keyRangeFilter) IsMatching(key []byte) bool {
+	if bytes.Compare(key, filter.start) < 0 {
+		return false
+	}
+
+	return len(filter.end) == 0 || bytes.Compare(key, filter.end) < 0
+}
+
+// IsInterfaceNil returns true if there is no value under the interface
+func (filter *keyRangeFilter) IsInterfaceNil() bool {
+	return filter == nil
+}
+
+type shardKeyFilter struct {
+	shardCoordinator ShardCoordinator
+	shardID          uint32
+}
+
+// NewShardKeyFilter creates a key filter matching the keys the shard coordinator assigns to the provided shard
+func NewShardKeyFilter(shardCoordinator ShardCoordinator, shardID uint32) (*shardKeyFilter, error) {
+	if check.IfNil(shardCoordinator) {
+		return nil, fmt.Errorf("%w, ShardCoordinator", errNilComponent)
+	}
+	if shardID >= shardCoordinator.NumberOfShards() && shardID != core.MetachainShardId {
+		return nil, fmt.Errorf("%w, shard ID %d, number of shards %d", errInvalidKeyFilter, shardID, shardCoordinator.NumberOfShards())
+	}
+
+	return &shardKeyFilter{
+		shardCoordinator: shardCoordinator,
+		shardID:          shardID,
+	}, nil
+}
+
+// IsMatching returns true if the key belongs to the filter shard
+func (filter *shardKeyFilter) IsMatching(key []byte) bool {
+	return filter.shardCoordinator.ComputeId(key) == filter.shardID
+}
+
+// IsInterfaceNil returns true if there is no value under the interface
+func (filter *shardKeyFilter) IsInterfaceNil() bool {
+	return filter == nil
+}
+
+type allKeysFilter struct {
+}
+
+// NewAllKeysFilter creates a key filter matching all the keys
+func NewAllKeysFilter() *allKeysFilter {
+	return &allKeysFilter{}
+}
+
+// IsMatching returns true
+func (filter *allKeysFilter) IsMatching(_ []byte) bool {
+	return true
+}
+
+// IsInterfaceNil returns true if there is no value under the interface
+func (filter *allKeysFilter) IsInterfaceNil() bool {
+	return filter == nil
+}
+
+// CreateKeyFilter creates a key filter from its description, formatted as <type>:<value> (see the KeyFilterType
+// constants). The shard coordinator is only used by the shard filters
+func CreateKeyFilter(description string, shardCoordinator ShardCoordinator) (KeyFilter, error) {
+	if description == KeyFilterTypeAll {
+		return NewAllKeysFilter(), nil
+	}
+
+	filterType, value, found := cutString(description, keyFilterTypeSeparator)
+	if !found {
+		return nil, fmt.Errorf("%w, missing the filter type in %s", errInvalidKeyFilter, description)
+	}
+
+	switch filterType {
+	case KeyFilterTypePrefix:
+		prefix, err := hex.DecodeString(value)
+		if err != nil {
+			return nil, fmt.Errorf("%w, prefix %s: %s", errInvalidKeyFilter, value, err.Error())
+		}
+
+		return NewPrefixKeyFilter(prefix)
+	case KeyFilterTypeRange:
+		startValue, endValue, hasSeparator := cutString(value, keyRangeSeparator)
+		if !hasSeparator {
+			return nil, fmt.Errorf("%w, missing the range separator in %s", errInvalidKeyFilter, value)
+		}
+		start, err := hex.DecodeString(startValue)
+		if err != nil {
+			return nil, fmt.Errorf("%w, range start %s: %s", errInvalidKeyFilter, startValue, err.Error())
+		}
+		end, err := hex.DecodeString(endValue)
+		if err != nil {
+			return nil, fmt.Errorf("%w, range end %s: %s", errInvalidKeyFilter, endValue, err.Error())
+		}
+
+		return NewKeyRangeFilter(start, end)
+	case KeyFilterTypeShard:
+		shardID, err := parseShardID(value)
+		if err != nil {
+			return nil, err
+		}
+
+		return NewShardKeyFilter(shardCoordinator, shardID)
+	default:
+		return nil, fmt.Errorf("%w, unknown filter type %s", errInvalidKeyFilter, filterType)
+	}
+}
+
+func parseShardID(value string) (uint32, error) {
+	if value == metachainShardIDKeyword {
+		return core.MetachainShardId, nil
+	}
+
+	shardID, err := strconv.ParseUint(value, 10, 32)
+	if err != nil {
+		return 0, fmt.Errorf("%w, shard ID %s: %s", errInvalidKeyFilter, value, err.Error())
+	}
+
+	return uint32(shardID), nil
+}
+
+// cutString slices the string around the first instance of the separator (strings.Cut is not available in go 1.17)
+func cutString(str string, separator string) (string, string, bool) {
+	idx := strings.Index(str, separator)
+	if idx < 0 {
+		return str, "", false
+	}
+
+	return str[:idx], str[idx+len(separator):], true
+}
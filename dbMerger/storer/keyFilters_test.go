@@ -0,0 +1,185 @@
+package storer
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/multiversx/mx-chain-core-go/core"
+	"github.com/multiversx/mx-chain-core-go/core/check"
+	"github.com/multiversx/mx-chain-tools-go/dbmerger/mock"
+	"github.com/stretchr/testify/assert"
+)
+
+func createShardCoordinatorStub(numShards uint32) *mock.ShardCoordinatorStub {
+	return &mock.ShardCoordinatorStub{
+		ComputeIdCalled: func(address []byte) uint32 {
+			return uint32(address[len(address)-1]) % numShards
+		},
+		NumberOfShardsCalled: func() uint32 {
+			return numShards
+		},
+	}
+}
+
+func TestPrefixKeyFilter(t *testing.T) {
+	t.Parallel()
+
+	t.Run("empty prefix should error", func(t *testing.T) {
+		t.Parallel()
+
+		filter, err := NewPrefixKeyFilter(nil)
+		assert.True(t, check.IfNil(filter))
+		assert.True(t, errors.Is(err, errInvalidKeyFilter))
+	})
+	t.Run("should work", func(t *testing.T) {
+		t.Parallel()
+
+		filter, err := NewPrefixKeyFilter([]byte("ab"))
+		assert.False(t, check.IfNil(filter))
+		assert.Nil(t, err)
+
+		assert.True(t, filter.IsMatching([]byte("ab")))
+		assert.True(t, filter.IsMatching([]byte("abc")))
+		assert.False(t, filter.IsMatching([]byte("a")))
+		assert.False(t, filter.IsMatching([]byte("bab")))
+	})
+}
+
+func TestKeyRangeFilter(t *testing.T) {
+	t.Parallel()
+
+	t.Run("start not lower than the end should error", func(t *testing.T) {
+		t.Parallel()
+
+		filter, err := NewKeyRangeFilter([]byte("b"), []byte("a"))
+		assert.True(t, check.IfNil(filter))
+		assert.True(t, errors.Is(err, errInvalidKeyFilter))
+
+		filter, err = NewKeyRangeFilter([]byte("b"), []byte("b"))
+		assert.True(t, check.IfNil(filter))
+		assert.True(t, errors.Is(err, errInvalidKeyFilter))
+	})
+	t.Run("bounded range should work", func(t *testing.T) {
+		t.Parallel()
+
+		filter, err := NewKeyRangeFilter([]byte("b"), []byte("d"))
+		assert.False(t, check.IfNil(filter))
+		assert.Nil(t, err)
+
+		assert.False(t, filter.IsMatching([]byte("a")))
+		assert.True(t, filter.IsMatching([]byte("b")))
+		assert.True(t, filter.IsMatching([]byte("cz")))
+		assert.False(t, filter.IsMatching([]byte("d")))
+		assert.False(t, filter.IsMatching([]byte("da")))
+	})
+	t.Run("unbounded ends should work", func(t *testing.T) {
+		t.Parallel()
+
+		filter, _ := NewKeyRangeFilter(nil, []byte("b"))
+		assert.True(t, filter.IsMatching([]byte("")))
+		assert.True(t, filter.IsMatching([]byte("a")))
+		assert.False(t, filter.IsMatching([]byte("b")))
+
+		filter, _ = NewKeyRangeFilter([]byte("b"), nil)
+		assert.False(t, filter.IsMatching([]byte("a")))
+		assert.True(t, filter.IsMatching([]byte("b")))
+		assert.True(t, filter.IsMatching([]byte("zzz")))
+	})
+}
+
+func TestShardKeyFilter(t *testing.T) {
+	t.Parallel()
+
+	t.Run("nil shard coordinator should error", func(t *testing.T) {
+		t.Parallel()
+
+		filter, err := NewShardKeyFilter(nil, 0)
+		assert.True(t, check.IfNil(filter))
+		assert.True(t, errors.Is(err, errNilComponent))
+		assert.True(t, strings.Contains(err.Error(), "ShardCoordinator"))
+	})
+	t.Run("invalid shard ID should error", func(t *testing.T) {
+		t.Parallel()
+
+		filter, err := NewShardKeyFilter(createShardCoordinatorStub(3), 3)
+		assert.True(t, check.IfNil(filter))
+		assert.True(t, errors.Is(err, errInvalidKeyFilter))
+	})
+	t.Run("metachain shard ID should work", func(t *testing.T) {
+		t.Parallel()
+
+		filter, err := NewShardKeyFilter(createShardCoordinatorStub(3), core.MetachainShardId)
+		assert.False(t, check.IfNil(filter))
+		assert.Nil(t, err)
+	})
+	t.Run("should work", func(t *testing.T) {
+		t.Parallel()
+
+		filter, err := NewShardKeyFilter(createShardCoordinatorStub(3), 1)
+		assert.False(t, check.IfNil(filter))
+		assert.Nil(t, err)
+
+		assert.True(t, filter.IsMatching([]byte{0, 1}))
+		assert.True(t, filter.IsMatching([]byte{0, 4}))
+		assert.False(t, filter.IsMatching([]byte{0, 2}))
+	})
+}
+
+func TestCreateKeyFilter(t *testing.T) {
+	t.Parallel()
+
+	t.Run("invalid descriptions should error", func(t *testing.T) {
+		t.Parallel()
+
+		descriptions := []string{
+			"",
+			"prefix",
+			"prefix:",
+			"prefix:zz",
+			"range:00",
+			"range:zz-",
+			"range:-zz",
+			"range:02-01",
+			"shard:",
+			"shard:-1",
+			"shard:3",
+			"unknown:00",
+		}
+		for _, description := range descriptions {
+			filter, err := CreateKeyFilter(description, createShardCoordinatorStub(3))
+			assert.True(t, check.IfNil(filter), description)
+			assert.True(t, errors.Is(err, errInvalidKeyFilter), description)
+		}
+	})
+	t.Run("should work", func(t *testing.T) {
+		t.Parallel()
+
+		filter, err := CreateKeyFilter("all", nil)
+		assert.Nil(t, err)
+		assert.True(t, filter.IsMatching([]byte("any")))
+
+		filter, err = CreateKeyFilter("prefix:0a0b", nil)
+		assert.Nil(t, err)
+		assert.True(t, filter.IsMatching([]byte{0x0a, 0x0b, 0x0c}))
+		assert.False(t, filter.IsMatching([]byte{0x0a, 0x0c}))
+
+		filter, err = CreateKeyFilter("range:10-20", nil)
+		assert.Nil(t, err)
+		assert.True(t, filter.IsMatching([]byte{0x10, 0xff}))
+		assert.False(t, filter.IsMatching([]byte{0x20}))
+
+		filter, err = CreateKeyFilter("range:10-", nil)
+		assert.Nil(t, err)
+		assert.True(t, filter.IsMatching([]byte{0xff}))
+
+		filter, err = CreateKeyFilter("shard:2", createShardCoordinatorStub(3))
+		assert.Nil(t, err)
+		assert.True(t, filter.IsMatching([]byte{5}))
+		assert.False(t, filter.IsMatching([]byte{4}))
+
+		filter, err = CreateKeyFilter("shard:metachain", createShardCoordinatorStub(3))
+		assert.Nil(t, err)
+		assert.False(t, check.IfNil(filter))
+	})
+}
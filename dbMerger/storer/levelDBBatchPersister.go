@@ -0,0 +1,205 @@
+package storer
+
+import (
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/multiversx/mx-chain-storage-go/common"
+	"github.com/syndtr/goleveldb/leveldb"
+	"github.com/syndtr/goleveldb/leveldb/opt"
+)
+
+const blockCacheCapacity = 32 * opt.MiB
+
+// levelDBBatchPersister is a LevelDB persister able to write several key-values with a single LevelDB batch, of at most
+// maxBatchSize key-values. It is used for the merge destinations, as the mx-chain-storage-go LevelDB persisters only
+// accept the key-values one by one. The database files are the same for both LevelDB backends, so it can be used for
+// both of them
+type levelDBBatchPersister struct {
+	mut          sync.RWMutex
+	db           *leveldb.DB
+	path         string
+	maxBatchSize int
+}
+
+func newLevelDBBatchPersister(path string, maxBatchSize int, maxOpenFiles int) (*levelDBBatchPersister, error) {
+	if maxBatchSize < 1 {
+		return nil, fmt.Errorf("%w, maxBatchSize %d", errInvalidValue, maxBatchSize)
+	}
+
+	err := os.MkdirAll(path, fileKVDirPermMode)
+	if err != nil {
+		return nil, err
+	}
+
+	options := &opt.Options{
+		// every merged key is read from the destination before being written, to detect the conflicts, so the block
+		// cache is kept, unlike for the node persisters
+		BlockCacheCapacity:     blockCacheCapacity,
+		OpenFilesCacheCapacity: maxOpenFiles,
+	}
+	db, err := leveldb.OpenFile(path, options)
+	if err != nil {
+		return nil, fmt.Errorf("%w for path %s", err, path)
+	}
+
+	return &levelDBBatchPersister{
+		db:           db,
+		path:         path,
+		maxBatchSize: maxBatchSize,
+	}, nil
+}
+
+// Put writes the value of the key
+func (persister *levelDBBatchPersister) Put(key, val []byte) error {
+	persister.mut.RLock()
+	defer persister.mut.RUnlock()
+
+	if persister.db == nil {
+		return common.ErrDBIsClosed
+	}
+
+	return persister.db.Put(key, val, nil)
+}
+
+// PutBatch writes all the provided key-values, with a single write for every maxBatchSize key-values
+func (persister *levelDBBatchPersister) PutBatch(keys [][]byte, values [][]byte) error {
+	if len(keys) != len(values) {
+		return fmt.Errorf("%w, %d keys and %d values", errInvalidValue, len(keys), len(values))
+	}
+
+	persister.mut.RLock()
+	defer persister.mut.RUnlock()
+
+	if persister.db == nil {
+		return common.ErrDBIsClosed
+	}
+
+	for start := 0; start < len(keys); start += persister.maxBatchSize {
+		end := start + persister.maxBatchSize
+		if end > len(keys) {
+			end = len(keys)
+		}
+
+		batch := new(leveldb.Batch)
+		for idx := start; idx < end; idx++ {
+			batch.Put(keys[idx], values[idx])
+		}
+
+		err := persister.db.Write(batch, nil)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Get returns the value associated to the key
+func (persister *levelDBBatchPersister) Get(key []byte) ([]byte, error) {
+	persister.mut.RLock()
+	defer persister.mut.RUnlock()
+
+	if persister.db == nil {
+		return nil, common.ErrDBIsClosed
+	}
+
+	val, err := persister.db.Get(key, nil)
+	if err == leveldb.ErrNotFound {
+		return nil, common.ErrKeyNotFound
+	}
+
+	return val, err
+}
+
+// Has returns nil if the given key is present
+func (persister *levelDBBatchPersister) Has(key []byte) error {
+	persister.mut.RLock()
+	defer persister.mut.RUnlock()
+
+	if persister.db == nil {
+		return common.ErrDBIsClosed
+	}
+
+	has, err := persister.db.Has(key, nil)
+	if err != nil {
+		return err
+	}
+	if !has {
+		return common.ErrKeyNotFound
+	}
+
+	return nil
+}
+
+// Remove removes the data associated to the given key
+func (persister *levelDBBatchPersister) Remove(key []byte) error {
+	persister.mut.RLock()
+	defer persister.mut.RUnlock()
+
+	if persister.db == nil {
+		return common.ErrDBIsClosed
+	}
+
+	return persister.db.Delete(key, nil)
+}
+
+// RangeKeys iterates over all the key-values, sorted by key, until the handler returns false
+func (persister *levelDBBatchPersister) RangeKeys(handler func(key []byte, val []byte) bool) {
+	if handler == nil {
+		return
+	}
+
+	persister.mut.RLock()
+	defer persister.mut.RUnlock()
+
+	if persister.db == nil {
+		return
+	}
+
+	iterator := persister.db.NewIterator(nil, nil)
+	defer iterator.Release()
+
+	for iterator.Next() {
+		shouldContinue := handler(copyBytes(iterator.Key()), copyBytes(iterator.Value()))
+		if !shouldContinue {
+			return
+		}
+	}
+}
+
+// Close closes the database
+func (persister *levelDBBatchPersister) Close() error {
+	persister.mut.Lock()
+	defer persister.mut.Unlock()
+
+	if persister.db == nil {
+		return nil
+	}
+
+	err := persister.db.Close()
+	persister.db = nil
+
+	return err
+}
+
+// Destroy closes the database and removes its directory
+func (persister *levelDBBatchPersister) Destroy() error {
+	err := persister.Close()
+	if err != nil {
+		return err
+	}
+
+	return persister.DestroyClosed()
+}
+
+// DestroyClosed removes the directory of the closed database
+func (persister *levelDBBatchPersister) DestroyClosed() error {
+	return os.RemoveAll(persister.path)
+}
+
+// IsInterfaceNil returns true if there is no value under the interface
+func (persister *levelDBBatchPersister) IsInterfaceNil() bool {
+	return persister == nil
+}
@@ -0,0 +1,79 @@
+package storer
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/multiversx/mx-chain-core-go/core/check"
+	"github.com/multiversx/mx-chain-storage-go/common"
+	"github.com/multiversx/mx-chain-storage-go/leveldb"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewLevelDBBatchPersister(t *testing.T) {
+	t.Parallel()
+
+	persister, err := newLevelDBBatchPersister(t.TempDir(), 0, 10)
+	assert.True(t, check.IfNil(persister))
+	assert.True(t, errors.Is(err, errInvalidValue))
+}
+
+func TestLevelDBBatchPersister_Operations(t *testing.T) {
+	t.Parallel()
+
+	persister, err := newLevelDBBatchPersister(t.TempDir(), 100, 10)
+	assert.Nil(t, err)
+
+	_, err = persister.Get([]byte("key"))
+	assert.True(t, errors.Is(err, common.ErrKeyNotFound))
+	assert.True(t, errors.Is(persister.Has([]byte("key")), common.ErrKeyNotFound))
+
+	assert.Nil(t, persister.Put([]byte("key"), []byte("value")))
+	recovered, err := persister.Get([]byte("key"))
+	assert.Nil(t, err)
+	assert.Equal(t, []byte("value"), recovered)
+
+	assert.True(t, errors.Is(persister.PutBatch([][]byte{[]byte("key")}, nil), errInvalidValue))
+
+	assert.Nil(t, persister.Remove([]byte("key")))
+	assert.True(t, errors.Is(persister.Has([]byte("key")), common.ErrKeyNotFound))
+
+	assert.Nil(t, persister.Close())
+	assert.True(t, errors.Is(persister.Put([]byte("key"), []byte("value")), common.ErrDBIsClosed))
+	assert.True(t, errors.Is(persister.PutBatch(nil, nil), common.ErrDBIsClosed))
+	assert.Nil(t, persister.Close())
+}
+
+func TestLevelDBBatchPersister_PutBatchShouldBeReadableByTheNodePersister(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	persister, _ := newLevelDBBatchPersister(dir, 2, 10)
+	err := persister.PutBatch(
+		[][]byte{[]byte("key2"), []byte("key1"), []byte("key3")},
+		[][]byte{[]byte("value2"), []byte("value1"), []byte("value3")},
+	)
+	assert.Nil(t, err)
+
+	numCalls := 0
+	persister.RangeKeys(func(key []byte, val []byte) bool {
+		numCalls++
+		return false
+	})
+	assert.Equal(t, 1, numCalls)
+	assert.Nil(t, persister.Close())
+
+	nodePersister, err := leveldb.NewDB(dir, 2, 100, 10)
+	assert.Nil(t, err)
+
+	keys := make([]string, 0)
+	values := make([]string, 0)
+	nodePersister.RangeKeys(func(key []byte, val []byte) bool {
+		keys = append(keys, string(key))
+		values = append(values, string(val))
+		return true
+	})
+	assert.Equal(t, []string{"key1", "key2", "key3"}, keys)
+	assert.Equal(t, []string{"value1", "value2", "value3"}, values)
+	assert.Nil(t, nodePersister.Close())
+}
@@ -0,0 +1,228 @@
+package storer
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/multiversx/mx-chain-core-go/core/check"
+	"github.com/multiversx/mx-chain-tools-go/dbmerger/common"
+	"github.com/multiversx/mx-chain-tools-go/dbmerger/path"
+)
+
+const minNumOfNodeDBs = 2
+
+// ArgsNodeDBMerger is the DTO used in the NewNodeDBMerger constructor function
+type ArgsNodeDBMerger struct {
+	FullDBMergerInstance FullDBMerger
+	OsOperationsHandler  OsOperationsHandler
+	DBType               common.DBType
+}
+
+type nodeDBMerger struct {
+	fullDBMergerInstance FullDBMerger
+	osOperationsHandler  OsOperationsHandler
+	dbType               common.DBType
+}
+
+// NewNodeDBMerger creates a new instance of type nodeDBMerger
+func NewNodeDBMerger(args ArgsNodeDBMerger) (*nodeDBMerger, error) {
+	if check.IfNil(args.FullDBMergerInstance) {
+		return nil, fmt.Errorf("%w, FullDBMergerInstance", errNilComponent)
+	}
+	if check.IfNil(args.OsOperationsHandler) {
+		return nil, fmt.Errorf("%w, OsOperationsHandler", errNilComponent)
+	}
+	err := checkDBType(args.DBType)
+	if err != nil {
+		return nil, err
+	}
+
+	return &nodeDBMerger{
+		fullDBMergerInstance: args.FullDBMergerInstance,
+		osOperationsHandler:  args.OsOperationsHandler,
+		dbType:               args.DBType,
+	}, nil
+}
+
+// MergeNodeDBs will assemble, in the destination root path, a node database holding the union of the epochs found in
+// the source node databases and their merged Static directory. Every storage unit found in a single source is copied at
+// the OS level, the storage units found in several sources are merged key by key: the epochs in the sources order and
+// the Static directory in the order of the sources highest epochs, so the newest Static state wins with the default
+// conflict policy
+func (ndm *nodeDBMerger) MergeNodeDBs(destinationRootPath string, sources ...NodeDBParser) error {
+	if len(sources) < minNumOfNodeDBs {
+		return fmt.Errorf("%w, provided %d node databases, minimum %d", errInvalidNumberOfPersisters, len(sources), minNumOfNodeDBs)
+	}
+
+	err := ndm.parseSources(sources)
+	if err != nil {
+		return err
+	}
+
+	err = ndm.osOperationsHandler.CheckIfDirectoryIsEmpty(destinationRootPath)
+	if err != nil {
+		return err
+	}
+
+	chainID, shardID := sources[0].ChainID(), sources[0].ShardID()
+	epochs, epochsSources := groupSourcesByEpoch(sources)
+	log.Info("merging node databases", "chain ID", chainID, "shard ID", shardID, "num epochs", len(epochs))
+
+	for _, epoch := range epochs {
+		log.Debug("merging epoch", "epoch", epoch, "num sources", len(epochsSources[epoch]))
+
+		err = ndm.mergeDirectory(destinationRootPath, path.EpochDirectory(chainID, epoch, shardID), epochsSources[epoch])
+		if err != nil {
+			return fmt.Errorf("%w for epoch %d", err, epoch)
+		}
+	}
+
+	err = ndm.mergeDirectory(destinationRootPath, path.StaticDirectory(chainID, shardID), sortSourcesByHighestEpoch(sources))
+	if err != nil {
+		return fmt.Errorf("%w for the Static directory", err)
+	}
+
+	logMissingEpochs(epochs)
+
+	return nil
+}
+
+func (ndm *nodeDBMerger) parseSources(sources []NodeDBParser) error {
+	for idx, source := range sources {
+		if check.IfNil(source) {
+			return fmt.Errorf("%w for the node database parser, index %d", errNilComponent, idx)
+		}
+
+		err := source.ParseDirectory()
+		if err != nil {
+			return fmt.Errorf("%w while parsing the node database %s", err, source.DBRootPath())
+		}
+
+		if source.ChainID() != sources[0].ChainID() {
+			return fmt.Errorf("%w, found %s in %s and %s in %s", errChainIDMismatch,
+				sources[0].ChainID(), sources[0].DBRootPath(), source.ChainID(), source.DBRootPath())
+		}
+		if source.ShardID() != sources[0].ShardID() {
+			return fmt.Errorf("%w, found %d in %s and %d in %s", errShardIDMismatch,
+				sources[0].ShardID(), sources[0].DBRootPath(), source.ShardID(), source.DBRootPath())
+		}
+	}
+
+	return nil
+}
+
+func groupSourcesByEpoch(sources []NodeDBParser) ([]uint64, map[uint64][]NodeDBParser) {
+	epochsSources := make(map[uint64][]NodeDBParser)
+	for _, source := range sources {
+		for _, epoch := range source.Epochs() {
+			epochsSources[epoch] = append(epochsSources[epoch], source)
+		}
+	}
+
+	epochs := make([]uint64, 0, len(epochsSources))
+	for epoch := range epochsSources {
+		epochs = append(epochs, epoch)
+	}
+	sort.Slice(epochs, func(i, j int) bool {
+		return epochs[i] < epochs[j]
+	})
+
+	return epochs, epochsSources
+}
+
+// sortSourcesByHighestEpoch returns the sources sorted by their highest epoch, the newest last. The sources with the same
+// highest epoch keep their order
+func sortSourcesByHighestEpoch(sources []NodeDBParser) []NodeDBParser {
+	sortedSources := make([]NodeDBParser, len(sources))
+	copy(sortedSources, sources)
+	sort.SliceStable(sortedSources, func(i, j int) bool {
+		return sortedSources[i].HighestEpoch() < sortedSources[j].HighestEpoch()
+	})
+
+	for i := 1; i < len(sortedSources); i++ {
+		if sortedSources[i].HighestEpoch() == sortedSources[i-1].HighestEpoch() {
+			log.Warn("node databases with the same highest epoch, their Static directories are merged in the sources order",
+				"highest epoch", sortedSources[i].HighestEpoch(),
+				"first", sortedSources[i-1].DBRootPath(), "second", sortedSources[i].DBRootPath())
+		}
+	}
+
+	return sortedSources
+}
+
+// mergeDirectory merges, storage unit by storage unit, the provided directory, relative to the db root path, of the
+// sources into the destination
+func (ndm *nodeDBMerger) mergeDirectory(destinationRootPath string, relativePath string, sources []NodeDBParser) error {
+	storageUnitsPaths := make(map[string][]string)
+	for _, source := range sources {
+		sourcePath := filepath.Join(source.DBRootPath(), relativePath)
+		storageUnits, err := ndm.osOperationsHandler.ListDirectories(sourcePath)
+		if errors.Is(err, os.ErrNotExist) {
+			log.Warn("missing directory, skipping", "path", sourcePath)
+			continue
+		}
+		if err != nil {
+			return err
+		}
+
+		for _, storageUnit := range storageUnits {
+			storageUnitsPaths[storageUnit] = append(storageUnitsPaths[storageUnit], filepath.Join(sourcePath, storageUnit))
+		}
+	}
+
+	storageUnits := make([]string, 0, len(storageUnitsPaths))
+	for storageUnit := range storageUnitsPaths {
+		storageUnits = append(storageUnits, storageUnit)
+	}
+	sort.Strings(storageUnits)
+
+	for _, storageUnit := range storageUnits {
+		destinationPath := filepath.Join(destinationRootPath, relativePath, storageUnit)
+		err := ndm.mergeStorageUnit(destinationPath, storageUnitsPaths[storageUnit])
+		if err != nil {
+			return fmt.Errorf("%w for storage unit %s", err, storageUnit)
+		}
+	}
+
+	return nil
+}
+
+func (ndm *nodeDBMerger) mergeStorageUnit(destinationPath string, sourcePaths []string) error {
+	err := ndm.osOperationsHandler.CreateDirectory(destinationPath)
+	if err != nil {
+		return err
+	}
+
+	if len(sourcePaths) < minNumOfPersisters {
+		return ndm.osOperationsHandler.CopyDirectory(destinationPath, sourcePaths[0])
+	}
+
+	sources := make([]common.DBPath, 0, len(sourcePaths))
+	for _, sourcePath := range sourcePaths {
+		sources = append(sources, common.DBPath{Path: sourcePath, Type: ndm.dbType})
+	}
+
+	destination := common.DBPath{Path: destinationPath, Type: ndm.dbType}
+	destPersister, err := ndm.fullDBMergerInstance.MergeDBs(destination, sources...)
+	if err != nil {
+		return err
+	}
+
+	return destPersister.Close()
+}
+
+func logMissingEpochs(epochs []uint64) {
+	for i := 1; i < len(epochs); i++ {
+		if epochs[i] != epochs[i-1]+1 {
+			log.Warn("epochs missing from all the node databases", "from", epochs[i-1]+1, "to", epochs[i]-1)
+		}
+	}
+}
+
+// IsInterfaceNil returns true if there is no value under the interface
+func (ndm *nodeDBMerger) IsInterfaceNil() bool {
+	return ndm == nil
+}
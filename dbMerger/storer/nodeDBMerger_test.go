@@ -0,0 +1,294 @@
+package storer
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/multiversx/mx-chain-core-go/core/check"
+	"github.com/multiversx/mx-chain-go/storage"
+	"github.com/multiversx/mx-chain-tools-go/dbmerger/common"
+	"github.com/multiversx/mx-chain-tools-go/dbmerger/mock"
+	"github.com/stretchr/testify/assert"
+)
+
+func createMockArgsNodeDBMerger() ArgsNodeDBMerger {
+	return ArgsNodeDBMerger{
+		FullDBMergerInstance: &mock.FullDBMergerStub{},
+		OsOperationsHandler:  &mock.OsOperationsHandlerStub{},
+		DBType:               common.LevelDB,
+	}
+}
+
+func createNodeDBParserStub(dbRootPath string, epochs ...uint64) *mock.NodeDBParserStub {
+	stub := &mock.NodeDBParserStub{
+		DBRootPathValue: dbRootPath,
+		ChainIDValue:    "1",
+		ShardIDValue:    0,
+		EpochsValue:     epochs,
+	}
+	if len(epochs) > 0 {
+		stub.HighestEpochValue = epochs[len(epochs)-1]
+	}
+
+	return stub
+}
+
+func TestNewNodeDBMerger(t *testing.T) {
+	t.Parallel()
+
+	t.Run("nil FullDBMergerInstance", func(t *testing.T) {
+		t.Parallel()
+
+		args := createMockArgsNodeDBMerger()
+		args.FullDBMergerInstance = nil
+		merger, err := NewNodeDBMerger(args)
+
+		assert.True(t, check.IfNil(merger))
+		assert.True(t, errors.Is(err, errNilComponent))
+		assert.True(t, strings.Contains(err.Error(), "FullDBMergerInstance"))
+	})
+	t.Run("nil OsOperationsHandler", func(t *testing.T) {
+		t.Parallel()
+
+		args := createMockArgsNodeDBMerger()
+		args.OsOperationsHandler = nil
+		merger, err := NewNodeDBMerger(args)
+
+		assert.True(t, check.IfNil(merger))
+		assert.True(t, errors.Is(err, errNilComponent))
+		assert.True(t, strings.Contains(err.Error(), "OsOperationsHandler"))
+	})
+	t.Run("unknown DBType", func(t *testing.T) {
+		t.Parallel()
+
+		args := createMockArgsNodeDBMerger()
+		args.DBType = "MemoryDB"
+		merger, err := NewNodeDBMerger(args)
+
+		assert.True(t, check.IfNil(merger))
+		assert.True(t, errors.Is(err, errUnknownDBType))
+	})
+	t.Run("should work", func(t *testing.T) {
+		t.Parallel()
+
+		merger, err := NewNodeDBMerger(createMockArgsNodeDBMerger())
+
+		assert.False(t, check.IfNil(merger))
+		assert.Nil(t, err)
+	})
+}
+
+func TestNodeDBMerger_MergeNodeDBs(t *testing.T) {
+	t.Parallel()
+
+	t.Run("invalid number of node databases", func(t *testing.T) {
+		t.Parallel()
+
+		merger, _ := NewNodeDBMerger(createMockArgsNodeDBMerger())
+
+		err := merger.MergeNodeDBs("dest", createNodeDBParserStub("src1", 0))
+		assert.True(t, errors.Is(err, errInvalidNumberOfPersisters))
+		assert.True(t, strings.Contains(err.Error(), "provided 1 node databases, minimum 2"))
+	})
+	t.Run("nil parser should error", func(t *testing.T) {
+		t.Parallel()
+
+		merger, _ := NewNodeDBMerger(createMockArgsNodeDBMerger())
+
+		err := merger.MergeNodeDBs("dest", createNodeDBParserStub("src1", 0), nil)
+		assert.True(t, errors.Is(err, errNilComponent))
+		assert.True(t, strings.Contains(err.Error(), "index 1"))
+	})
+	t.Run("parse errors should error", func(t *testing.T) {
+		t.Parallel()
+
+		expectedErr := errors.New("expected error")
+		src2 := createNodeDBParserStub("src2", 0)
+		src2.ParseDirectoryCalled = func() error {
+			return expectedErr
+		}
+		merger, _ := NewNodeDBMerger(createMockArgsNodeDBMerger())
+
+		err := merger.MergeNodeDBs("dest", createNodeDBParserStub("src1", 0), src2)
+		assert.True(t, errors.Is(err, expectedErr))
+		assert.True(t, strings.Contains(err.Error(), "src2"))
+	})
+	t.Run("different chain IDs should error", func(t *testing.T) {
+		t.Parallel()
+
+		src2 := createNodeDBParserStub("src2", 0)
+		src2.ChainIDValue = "D"
+		merger, _ := NewNodeDBMerger(createMockArgsNodeDBMerger())
+
+		err := merger.MergeNodeDBs("dest", createNodeDBParserStub("src1", 0), src2)
+		assert.True(t, errors.Is(err, errChainIDMismatch))
+	})
+	t.Run("different shard IDs should error", func(t *testing.T) {
+		t.Parallel()
+
+		src2 := createNodeDBParserStub("src2", 0)
+		src2.ShardIDValue = 1
+		merger, _ := NewNodeDBMerger(createMockArgsNodeDBMerger())
+
+		err := merger.MergeNodeDBs("dest", createNodeDBParserStub("src1", 0), src2)
+		assert.True(t, errors.Is(err, errShardIDMismatch))
+	})
+	t.Run("destination not empty should error", func(t *testing.T) {
+		t.Parallel()
+
+		expectedErr := errors.New("expected error")
+		args := createMockArgsNodeDBMerger()
+		args.OsOperationsHandler = &mock.OsOperationsHandlerStub{
+			CheckIfDirectoryIsEmptyCalled: func(directory string) error {
+				return expectedErr
+			},
+		}
+		merger, _ := NewNodeDBMerger(args)
+
+		err := merger.MergeNodeDBs("dest", createNodeDBParserStub("src1", 0), createNodeDBParserStub("src2", 0))
+		assert.Equal(t, expectedErr, err)
+	})
+	t.Run("storage unit merge errors should error", func(t *testing.T) {
+		t.Parallel()
+
+		expectedErr := errors.New("expected error")
+		args := createMockArgsNodeDBMerger()
+		args.OsOperationsHandler = &mock.OsOperationsHandlerStub{
+			ListDirectoriesCalled: func(directory string) ([]string, error) {
+				return []string{"BlockHeaders"}, nil
+			},
+		}
+		args.FullDBMergerInstance = &mock.FullDBMergerStub{
+			MergeDBsCalled: func(destination common.DBPath, sources ...common.DBPath) (storage.Persister, error) {
+				return nil, expectedErr
+			},
+		}
+		merger, _ := NewNodeDBMerger(args)
+
+		err := merger.MergeNodeDBs("dest", createNodeDBParserStub("src1", 3), createNodeDBParserStub("src2", 3))
+		assert.True(t, errors.Is(err, expectedErr))
+		assert.True(t, strings.Contains(err.Error(), "for storage unit BlockHeaders for epoch 3"))
+	})
+	t.Run("directory read errors should error", func(t *testing.T) {
+		t.Parallel()
+
+		expectedErr := errors.New("expected error")
+		args := createMockArgsNodeDBMerger()
+		args.OsOperationsHandler = &mock.OsOperationsHandlerStub{
+			ListDirectoriesCalled: func(directory string) ([]string, error) {
+				return nil, expectedErr
+			},
+		}
+		merger, _ := NewNodeDBMerger(args)
+
+		err := merger.MergeNodeDBs("dest", createNodeDBParserStub("src1", 3), createNodeDBParserStub("src2", 3))
+		assert.True(t, errors.Is(err, expectedErr))
+	})
+	t.Run("Static directory should be merged from the oldest to the newest node database", func(t *testing.T) {
+		t.Parallel()
+
+		var staticSources []string
+		args := createMockArgsNodeDBMerger()
+		args.OsOperationsHandler = &mock.OsOperationsHandlerStub{
+			ListDirectoriesCalled: func(directory string) ([]string, error) {
+				if strings.Contains(directory, "Static") {
+					return []string{"AccountsTrie"}, nil
+				}
+
+				return nil, nil
+			},
+		}
+		args.FullDBMergerInstance = &mock.FullDBMergerStub{
+			MergeDBsCalled: func(destination common.DBPath, sources ...common.DBPath) (storage.Persister, error) {
+				staticSources = getPaths(sources)
+				return mock.NewPersisterMock(), nil
+			},
+		}
+		merger, _ := NewNodeDBMerger(args)
+
+		err := merger.MergeNodeDBs("dest",
+			createNodeDBParserStub("newest", 8, 9),
+			createNodeDBParserStub("oldest", 1, 2),
+			createNodeDBParserStub("middle", 4, 5),
+		)
+		assert.Nil(t, err)
+		assert.Equal(t, []string{
+			filepath.Join("oldest", "1", "Static", "Shard_0", "AccountsTrie"),
+			filepath.Join("middle", "1", "Static", "Shard_0", "AccountsTrie"),
+			filepath.Join("newest", "1", "Static", "Shard_0", "AccountsTrie"),
+		}, staticSources)
+	})
+	t.Run("should work", func(t *testing.T) {
+		t.Parallel()
+
+		mut := sync.Mutex{}
+		copied := make(map[string]string)
+		merged := make(map[string][]string)
+		created := make([]string, 0)
+		args := createMockArgsNodeDBMerger()
+		args.OsOperationsHandler = &mock.OsOperationsHandlerStub{
+			ListDirectoriesCalled: func(directory string) ([]string, error) {
+				switch directory {
+				case filepath.Join("src1", "1", "Static", "Shard_0"):
+					return []string{"AccountsTrie", "Heartbeat"}, nil
+				case filepath.Join("src2", "1", "Static", "Shard_0"):
+					return []string{"AccountsTrie"}, nil
+				case filepath.Join("src2", "1", "Epoch_5", "Shard_0"):
+					return nil, fmt.Errorf("%w while reading the directory", os.ErrNotExist)
+				default:
+					return []string{"BlockHeaders"}, nil
+				}
+			},
+			CreateDirectoryCalled: func(directory string) error {
+				mut.Lock()
+				created = append(created, directory)
+				mut.Unlock()
+
+				return nil
+			},
+			CopyDirectoryCalled: func(destination string, source string) error {
+				mut.Lock()
+				copied[destination] = source
+				mut.Unlock()
+
+				return nil
+			},
+		}
+		args.FullDBMergerInstance = &mock.FullDBMergerStub{
+			MergeDBsCalled: func(destination common.DBPath, sources ...common.DBPath) (storage.Persister, error) {
+				mut.Lock()
+				merged[destination.Path] = getPaths(sources)
+				mut.Unlock()
+
+				return mock.NewPersisterMock(), nil
+			},
+		}
+		merger, _ := NewNodeDBMerger(args)
+
+		err := merger.MergeNodeDBs("dest",
+			createNodeDBParserStub("src1", 3, 4),
+			createNodeDBParserStub("src2", 4, 5),
+		)
+		assert.Nil(t, err)
+		assert.Equal(t, map[string]string{
+			filepath.Join("dest", "1", "Epoch_3", "Shard_0", "BlockHeaders"): filepath.Join("src1", "1", "Epoch_3", "Shard_0", "BlockHeaders"),
+			filepath.Join("dest", "1", "Static", "Shard_0", "Heartbeat"):     filepath.Join("src1", "1", "Static", "Shard_0", "Heartbeat"),
+		}, copied)
+		assert.Equal(t, map[string][]string{
+			filepath.Join("dest", "1", "Epoch_4", "Shard_0", "BlockHeaders"): {
+				filepath.Join("src1", "1", "Epoch_4", "Shard_0", "BlockHeaders"),
+				filepath.Join("src2", "1", "Epoch_4", "Shard_0", "BlockHeaders"),
+			},
+			filepath.Join("dest", "1", "Static", "Shard_0", "AccountsTrie"): {
+				filepath.Join("src1", "1", "Static", "Shard_0", "AccountsTrie"),
+				filepath.Join("src2", "1", "Static", "Shard_0", "AccountsTrie"),
+			},
+		}, merged)
+		assert.Equal(t, 4, len(created))
+	})
+}
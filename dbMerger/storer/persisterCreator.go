@@ -1,27 +1,97 @@
 package storer
 
 import (
+	"fmt"
+
 	"github.com/multiversx/mx-chain-storage-go/leveldb"
 	"github.com/multiversx/mx-chain-storage-go/types"
+	"github.com/multiversx/mx-chain-tools-go/dbmerger/common"
 )
 
-const (
-	batchDelaySeconds = 2
-	maxBatchSize      = 10000
-	maxOpenFiles      = 10
-)
+// DefaultBatchDelaySeconds is the interval at which the LevelDB persisters write their internal batch, used by the tools
+// only writing with batch persisters
+const DefaultBatchDelaySeconds = 2
+
+// ArgsPersisterCreator is the DTO used in the NewPersisterCreator constructor function. It holds the options the
+// LevelDB persisters are opened with: the internal batch is written every MaxBatchSize puts or every BatchDelaySeconds.
+// The batch persisters write every batch right away, in writes of at most MaxBatchSize key-values
+type ArgsPersisterCreator struct {
+	BatchDelaySeconds int
+	MaxBatchSize      int
+	MaxOpenFiles      int
+}
 
 type persisterCreator struct {
+	batchDelaySeconds int
+	maxBatchSize      int
+	maxOpenFiles      int
 }
 
 // NewPersisterCreator will create a new persister creator instance
-func NewPersisterCreator() *persisterCreator {
-	return &persisterCreator{}
+func NewPersisterCreator(args ArgsPersisterCreator) (*persisterCreator, error) {
+	if args.BatchDelaySeconds < 1 {
+		return nil, fmt.Errorf("%w, BatchDelaySeconds %d", errInvalidValue, args.BatchDelaySeconds)
+	}
+	if args.MaxBatchSize < 1 {
+		return nil, fmt.Errorf("%w, MaxBatchSize %d", errInvalidValue, args.MaxBatchSize)
+	}
+	if args.MaxOpenFiles < 1 {
+		return nil, fmt.Errorf("%w, MaxOpenFiles %d", errInvalidValue, args.MaxOpenFiles)
+	}
+
+	return &persisterCreator{
+		batchDelaySeconds: args.BatchDelaySeconds,
+		maxBatchSize:      args.MaxBatchSize,
+		maxOpenFiles:      args.MaxOpenFiles,
+	}, nil
+}
+
+// CreatePersister will try to create a new persister instance of the provided backend in the provided directory path
+func (creator *persisterCreator) CreatePersister(dbPath common.DBPath) (types.Persister, error) {
+	switch dbPath.Type {
+	case common.LevelDB:
+		return leveldb.NewDB(dbPath.Path, creator.batchDelaySeconds, creator.maxBatchSize, creator.maxOpenFiles)
+	case common.LevelDBSerial:
+		return leveldb.NewSerialDB(dbPath.Path, creator.batchDelaySeconds, creator.maxBatchSize, creator.maxOpenFiles)
+	case common.FileKV:
+		return newFileKVPersister(dbPath.Path)
+	default:
+		return nil, fmt.Errorf("%w %s", errUnknownDBType, dbPath.Type)
+	}
 }
 
-// CreatePersister will try to create a new persister instance provided the directory path
-func (creator *persisterCreator) CreatePersister(path string) (types.Persister, error) {
-	return leveldb.NewDB(path, batchDelaySeconds, maxBatchSize, maxOpenFiles)
+// CreateBatchPersister will create a new persister instance of the provided backend in the provided directory path,
+// able to write several key-values with a single write. It is used for the destinations of the merges
+func (creator *persisterCreator) CreateBatchPersister(dbPath common.DBPath) (types.Persister, error) {
+	switch dbPath.Type {
+	case common.LevelDB, common.LevelDBSerial:
+		return newLevelDBBatchPersister(dbPath.Path, creator.maxBatchSize, creator.maxOpenFiles)
+	case common.FileKV:
+		return newFileKVPersister(dbPath.Path)
+	default:
+		return nil, fmt.Errorf("%w %s", errUnknownDBType, dbPath.Type)
+	}
+}
+
+func checkDBType(dbType common.DBType) error {
+	for _, supportedType := range common.DBTypes() {
+		if dbType == supportedType {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("%w %s", errUnknownDBType, dbType)
+}
+
+func checkDBPathsTypes(dbPaths []common.DBPath) error {
+	for _, dbPath := range dbPaths {
+		err := checkDBType(dbPath.Type)
+		if err != nil {
+			return fmt.Errorf("%w for %s", err, dbPath.Path)
+		}
+	}
+
+	return nil
 }
 
 // IsInterfaceNil returns true if there is no value under the interface
@@ -1,29 +1,161 @@
 package storer
 
 import (
+	"errors"
 	"fmt"
+	"strings"
 	"testing"
 
 	"github.com/multiversx/mx-chain-core-go/core/check"
+	"github.com/multiversx/mx-chain-tools-go/dbmerger/common"
 	"github.com/stretchr/testify/assert"
 )
 
+func createMockArgsPersisterCreator() ArgsPersisterCreator {
+	return ArgsPersisterCreator{
+		BatchDelaySeconds: 2,
+		MaxBatchSize:      10000,
+		MaxOpenFiles:      10,
+	}
+}
+
+func createLevelDBPath(path string) common.DBPath {
+	return common.DBPath{
+		Path: path,
+		Type: common.LevelDB,
+	}
+}
+
+func createLevelDBPaths(paths ...string) []common.DBPath {
+	dbPaths := make([]common.DBPath, 0, len(paths))
+	for _, path := range paths {
+		dbPaths = append(dbPaths, createLevelDBPath(path))
+	}
+
+	return dbPaths
+}
+
+func getPaths(dbPaths []common.DBPath) []string {
+	paths := make([]string, 0, len(dbPaths))
+	for _, dbPath := range dbPaths {
+		paths = append(paths, dbPath.Path)
+	}
+
+	return paths
+}
+
 func TestNewPersisterCreator(t *testing.T) {
 	t.Parallel()
 
-	creator := NewPersisterCreator()
-	assert.False(t, check.IfNil(creator))
+	t.Run("invalid BatchDelaySeconds", func(t *testing.T) {
+		t.Parallel()
+
+		args := createMockArgsPersisterCreator()
+		args.BatchDelaySeconds = 0
+		creator, err := NewPersisterCreator(args)
+
+		assert.True(t, check.IfNil(creator))
+		assert.True(t, errors.Is(err, errInvalidValue))
+		assert.True(t, strings.Contains(err.Error(), "BatchDelaySeconds"))
+	})
+	t.Run("invalid MaxBatchSize", func(t *testing.T) {
+		t.Parallel()
+
+		args := createMockArgsPersisterCreator()
+		args.MaxBatchSize = 0
+		creator, err := NewPersisterCreator(args)
+
+		assert.True(t, check.IfNil(creator))
+		assert.True(t, errors.Is(err, errInvalidValue))
+		assert.True(t, strings.Contains(err.Error(), "MaxBatchSize"))
+	})
+	t.Run("invalid MaxOpenFiles", func(t *testing.T) {
+		t.Parallel()
+
+		args := createMockArgsPersisterCreator()
+		args.MaxOpenFiles = 0
+		creator, err := NewPersisterCreator(args)
+
+		assert.True(t, check.IfNil(creator))
+		assert.True(t, errors.Is(err, errInvalidValue))
+		assert.True(t, strings.Contains(err.Error(), "MaxOpenFiles"))
+	})
+	t.Run("should work", func(t *testing.T) {
+		t.Parallel()
+
+		creator, err := NewPersisterCreator(createMockArgsPersisterCreator())
+
+		assert.False(t, check.IfNil(creator))
+		assert.Nil(t, err)
+	})
 }
 
 func TestPersisterCreator_CreatePersister(t *testing.T) {
 	t.Parallel()
 
-	creator := NewPersisterCreator()
+	t.Run("unknown backend should error", func(t *testing.T) {
+		t.Parallel()
+
+		creator, _ := NewPersisterCreator(createMockArgsPersisterCreator())
+
+		persister, err := creator.CreatePersister(common.DBPath{Path: t.TempDir(), Type: "MemoryDB"})
+		assert.True(t, check.IfNil(persister))
+		assert.True(t, errors.Is(err, errUnknownDBType))
+		assert.True(t, strings.Contains(err.Error(), "MemoryDB"))
+	})
+	t.Run("should work", func(t *testing.T) {
+		t.Parallel()
+
+		creator, _ := NewPersisterCreator(createMockArgsPersisterCreator())
+
+		expectedTypes := map[common.DBType]string{
+			common.LevelDB:       "*leveldb.DB",
+			common.LevelDBSerial: "*leveldb.SerialDB",
+			common.FileKV:        "*storer.fileKVPersister",
+		}
+		for dbType, expectedType := range expectedTypes {
+			persister, err := creator.CreatePersister(common.DBPath{Path: t.TempDir(), Type: dbType})
+			assert.False(t, check.IfNil(persister))
+			assert.Nil(t, err)
+			assert.Equal(t, expectedType, fmt.Sprintf("%T", persister))
+
+			_ = persister.Destroy()
+		}
+	})
+}
+
+func TestPersisterCreator_CreateBatchPersister(t *testing.T) {
+	t.Parallel()
+
+	t.Run("unknown backend should error", func(t *testing.T) {
+		t.Parallel()
+
+		creator, _ := NewPersisterCreator(createMockArgsPersisterCreator())
+
+		persister, err := creator.CreateBatchPersister(common.DBPath{Path: t.TempDir(), Type: "MemoryDB"})
+		assert.True(t, check.IfNil(persister))
+		assert.True(t, errors.Is(err, errUnknownDBType))
+	})
+	t.Run("should work", func(t *testing.T) {
+		t.Parallel()
+
+		creator, _ := NewPersisterCreator(createMockArgsPersisterCreator())
+
+		expectedTypes := map[common.DBType]string{
+			common.LevelDB:       "*storer.levelDBBatchPersister",
+			common.LevelDBSerial: "*storer.levelDBBatchPersister",
+			common.FileKV:        "*storer.fileKVPersister",
+		}
+		for dbType, expectedType := range expectedTypes {
+			persister, err := creator.CreateBatchPersister(common.DBPath{Path: t.TempDir(), Type: dbType})
+			assert.False(t, check.IfNil(persister))
+			assert.Nil(t, err)
+			assert.Equal(t, expectedType, fmt.Sprintf("%T", persister))
 
-	persister, err := creator.CreatePersister("test")
-	assert.False(t, check.IfNil(persister))
-	assert.Nil(t, err)
-	assert.Equal(t, "*leveldb.DB", fmt.Sprintf("%T", persister))
+			_, canPutBatch := persister.(batchPutter)
+			assert.True(t, canPutBatch)
 
-	_ = persister.Destroy()
+			_ = persister.Destroy()
+		}
+	})
 }
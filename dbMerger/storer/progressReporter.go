@@ -0,0 +1,107 @@
+package storer
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	"github.com/multiversx/mx-chain-core-go/core"
+)
+
+// progressReporter periodically logs the number of copied keys and bytes, the copy speed and the estimated remaining
+// time. The remaining time is estimated from the on-disk size of the sources, which is compressed, while the copied
+// bytes are not, so it is logged as a lower bound of the remaining time
+type progressReporter struct {
+	numKeys       uint64
+	numBytes      uint64
+	expectedBytes uint64
+	startTime     time.Time
+	cancel        func()
+}
+
+// NewProgressReporter creates a progress reporter logging the progress at the provided interval. A zero interval
+// disables the periodic logs
+func NewProgressReporter(logInterval time.Duration) (*progressReporter, error) {
+	if logInterval < 0 {
+		return nil, errInvalidProgressInterval
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	reporter := &progressReporter{
+		startTime: time.Now(),
+		cancel:    cancel,
+	}
+	if logInterval > 0 {
+		go reporter.logProgressLoop(ctx, logInterval)
+	}
+
+	return reporter, nil
+}
+
+// AddExpectedBytes adds the on-disk size of a source that will be copied key by key
+func (reporter *progressReporter) AddExpectedBytes(numBytes uint64) {
+	atomic.AddUint64(&reporter.expectedBytes, numBytes)
+}
+
+// AddCopied adds the provided number of copied keys and bytes
+func (reporter *progressReporter) AddCopied(numKeys uint64, numBytes uint64) {
+	atomic.AddUint64(&reporter.numKeys, numKeys)
+	atomic.AddUint64(&reporter.numBytes, numBytes)
+}
+
+func (reporter *progressReporter) logProgressLoop(ctx context.Context, logInterval time.Duration) {
+	ticker := time.NewTicker(logInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			reporter.logProgress("copy in progress")
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (reporter *progressReporter) logProgress(message string) {
+	numKeys := atomic.LoadUint64(&reporter.numKeys)
+	numBytes := atomic.LoadUint64(&reporter.numBytes)
+	expectedBytes := atomic.LoadUint64(&reporter.expectedBytes)
+	elapsed := time.Since(reporter.startTime)
+
+	keysPerSecond := float64(0)
+	bytesPerSecond := float64(0)
+	if elapsed.Seconds() > 0 {
+		keysPerSecond = float64(numKeys) / elapsed.Seconds()
+		bytesPerSecond = float64(numBytes) / elapsed.Seconds()
+	}
+
+	eta := "unknown"
+	if bytesPerSecond > 0 && expectedBytes > numBytes {
+		remaining := time.Duration(float64(expectedBytes-numBytes) / bytesPerSecond * float64(time.Second))
+		eta = remaining.Truncate(time.Second).String()
+	}
+
+	log.Info(message,
+		"num keys", numKeys,
+		"keys/sec", int64(keysPerSecond),
+		"copied", core.ConvertBytes(numBytes),
+		"speed", core.ConvertBytes(uint64(bytesPerSecond))+"/s",
+		"expected (on disk)", core.ConvertBytes(expectedBytes),
+		"elapsed", elapsed.Truncate(time.Second).String(),
+		"ETA (lower bound)", eta,
+	)
+}
+
+// Close stops the periodic logs and logs the final progress
+func (reporter *progressReporter) Close() error {
+	reporter.cancel()
+	reporter.logProgress("copy finished")
+
+	return nil
+}
+
+// IsInterfaceNil returns true if there is no value under the interface
+func (reporter *progressReporter) IsInterfaceNil() bool {
+	return reporter == nil
+}
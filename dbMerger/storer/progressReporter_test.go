@@ -0,0 +1,38 @@
+package storer
+
+import (
+	"testing"
+	"time"
+
+	"github.com/multiversx/mx-chain-core-go/core/check"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewProgressReporter(t *testing.T) {
+	t.Parallel()
+
+	reporter, err := NewProgressReporter(-time.Second)
+	assert.True(t, check.IfNil(reporter))
+	assert.Equal(t, errInvalidProgressInterval, err)
+
+	reporter, err = NewProgressReporter(0)
+	assert.False(t, check.IfNil(reporter))
+	assert.Nil(t, err)
+	assert.Nil(t, reporter.Close())
+}
+
+func TestProgressReporter_AddCopied(t *testing.T) {
+	t.Parallel()
+
+	reporter, _ := NewProgressReporter(time.Millisecond * 5)
+	reporter.AddExpectedBytes(1000)
+	reporter.AddExpectedBytes(500)
+	reporter.AddCopied(2, 100)
+	reporter.AddCopied(3, 200)
+	time.Sleep(time.Millisecond * 20)
+
+	assert.Equal(t, uint64(1500), reporter.expectedBytes)
+	assert.Equal(t, uint64(5), reporter.numKeys)
+	assert.Equal(t, uint64(300), reporter.numBytes)
+	assert.Nil(t, reporter.Close())
+}
@@ -1,11 +1,18 @@
 package main
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"io/ioutil"
 	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
 	logger "github.com/multiversx/mx-chain-logger-go"
 	"github.com/multiversx/mx-chain-tools-go/elasticreindexer/config"
+	"github.com/multiversx/mx-chain-tools-go/elasticreindexer/metrics"
 	"github.com/multiversx/mx-chain-tools-go/elasticreindexer/process"
 	"github.com/pelletier/go-toml"
 	"github.com/urfave/cli"
@@ -26,6 +33,81 @@ var (
 		Name:  "skip-mappings",
 		Usage: "If set, the reindexing tool will skip the copying of the mappings",
 	}
+	// resumeFlag defines a bool flag for resuming a previous run based on the checkpoint file
+	resumeFlag = cli.BoolFlag{
+		Name:  "resume",
+		Usage: "If set, the reindexing tool will skip the indices and intervals already completed in a previous run and will restart the partial ones from their last checkpoint",
+	}
+	// followFlag defines a bool flag for keeping the destination in sync with the source
+	followFlag = cli.BoolFlag{
+		Name:  "follow",
+		Usage: "If set, the reindexing tool will run until stopped and will periodically copy the new documents of the indices with timestamp",
+	}
+	// checkpointFileFlag defines the path of the file used to persist the reindexing progress
+	checkpointFileFlag = cli.StringFlag{
+		Name:  "checkpoint-file",
+		Usage: "The path of the file where the reindexing progress is persisted",
+		Value: "./checkpoint.json",
+	}
+	// forceMappingsFlag defines a bool flag for reindexing even if the source and destination mappings are incompatible
+	forceMappingsFlag = cli.BoolFlag{
+		Name:  "force-mappings",
+		Usage: "If set, the reindexing tool will only log the differences between incompatible source and destination mappings instead of stopping",
+	}
+	// verifyFlag defines a bool flag for comparing the destination documents with the source ones instead of reindexing
+	verifyFlag = cli.BoolFlag{
+		Name:  "verify",
+		Usage: "If set, the tool will not reindex anything, it will compare the documents from the source and the destination by ID and content",
+	}
+	// verifyReportFlag defines the path of the file where the verification differences are written
+	verifyReportFlag = cli.StringFlag{
+		Name:  "verify-report",
+		Usage: "The path of the file where the missing, extra and differing document IDs found by the verification are written",
+		Value: "./verify-report.json",
+	}
+	// dryRunFlag defines a bool flag for printing what the reindexing would do without writing anything
+	dryRunFlag = cli.BoolFlag{
+		Name:  "dry-run",
+		Usage: "If set, the tool will not write anything, it will print for every index what would be created in the destination and how much data would be copied",
+	}
+	// exportDirFlag defines the directory where the source indices are exported instead of being reindexed
+	exportDirFlag = cli.StringFlag{
+		Name:  "export-dir",
+		Usage: "If set, the source indices will be exported as NDJSON files in the provided directory instead of being reindexed",
+	}
+	// exportGzipFlag defines a bool flag for compressing the exported files
+	exportGzipFlag = cli.BoolFlag{
+		Name:  "export-gzip",
+		Usage: "If set, the exported files will be gzip-compressed",
+	}
+	// importDirFlag defines the directory from where the indices are imported in the destination instead of being reindexed
+	importDirFlag = cli.StringFlag{
+		Name:  "import-dir",
+		Usage: "If set, the indices previously exported in the provided directory will be imported in the destination instead of being reindexed",
+	}
+	// swapAliasFlag defines a bool flag for reindexing into new generations of the indices and swapping the aliases at the end
+	swapAliasFlag = cli.BoolFlag{
+		Name: "swap-alias",
+		Usage: "If set, every index is reindexed into a new generation (<index>-000002, ...) created without alias. After the reindexing " +
+			"and the verification succeed, the aliases are atomically moved on the new generations",
+	}
+	// metricsAddressFlag defines the address where the reindexing metrics are served
+	metricsAddressFlag = cli.StringFlag{
+		Name: "metrics-address",
+		Usage: "If set (e.g. localhost:9100), the reindexing progress (documents read and written, bytes, bulk errors, retries, " +
+			"completed intervals and ETA) is served in the Prometheus text format on the /metrics path of this address",
+	}
+	// deadLetterFileFlag defines the path of the file where the documents rejected by the destination are written
+	deadLetterFileFlag = cli.StringFlag{
+		Name:  "dead-letter-file",
+		Usage: "The path of the NDJSON file where the documents permanently rejected by the destination are appended, with their _id, index and rejection reason",
+		Value: "./dead-letter.ndjson",
+	}
+	// rollbackAliasFlag defines a bool flag for moving the aliases back to the previous generations
+	rollbackAliasFlag = cli.BoolFlag{
+		Name:  "rollback-alias",
+		Usage: "If set, the tool will not reindex anything, it will atomically move the aliases back to the previous generation of the indices",
+	}
 )
 
 const helpTemplate = `NAME:
@@ -53,6 +135,20 @@ func main() {
 	app.Flags = []cli.Flag{
 		overwriteFlag,
 		skipMappingsFlag,
+		resumeFlag,
+		followFlag,
+		checkpointFileFlag,
+		forceMappingsFlag,
+		verifyFlag,
+		verifyReportFlag,
+		dryRunFlag,
+		exportDirFlag,
+		exportGzipFlag,
+		importDirFlag,
+		swapAliasFlag,
+		rollbackAliasFlag,
+		metricsAddressFlag,
+		deadLetterFileFlag,
 	}
 	app.Authors = []cli.Author{
 		{
@@ -70,37 +166,399 @@ func main() {
 	}
 }
 
-func startReindexing(ctx *cli.Context) {
+func startReindexing(ctx *cli.Context) error {
 	cfg, err := loadConfig()
 	if err != nil {
-		log.Error("cannot load configuration", "error", err)
-		return
+		return fmt.Errorf("%w while loading the configuration", err)
+	}
+	if ctx.Bool(forceMappingsFlag.Name) {
+		cfg.Indexers.IndicesConfig.Mappings.ForceIncompatible = true
+	}
+
+	if len(cfg.Indexers.Inputs) > 0 {
+		err = checkMultipleInputsMode(ctx)
+		if err != nil {
+			return err
+		}
+	}
+
+	if ctx.Bool(dryRunFlag.Name) {
+		return startDryRun(cfg, ctx.Bool(overwriteFlag.Name), ctx.Bool(skipMappingsFlag.Name))
+	}
+
+	if ctx.Bool(verifyFlag.Name) {
+		return startVerifying(cfg, ctx.String(verifyReportFlag.Name), time.Now().Unix())
+	}
+
+	if ctx.IsSet(exportDirFlag.Name) {
+		return startExporting(cfg, ctx.String(exportDirFlag.Name), ctx.Bool(exportGzipFlag.Name))
+	}
+
+	if ctx.Bool(rollbackAliasFlag.Name) {
+		return startRollingBack(cfg)
+	}
+
+	reindexingMetrics := metrics.NewReindexingMetrics()
+	if ctx.IsSet(metricsAddressFlag.Name) {
+		server, errServer := metrics.StartServer(ctx.String(metricsAddressFlag.Name), reindexingMetrics)
+		if errServer != nil {
+			return fmt.Errorf("%w while starting the metrics server", errServer)
+		}
+		defer func() {
+			_ = server.Close()
+		}()
+	}
+
+	// the follow mode continues from the progress saved in the checkpoint file
+	resume := ctx.Bool(resumeFlag.Name) || ctx.Bool(followFlag.Name)
+	deadLetter, err := process.NewFileDeadLetter(ctx.String(deadLetterFileFlag.Name), resume)
+	if err != nil {
+		return fmt.Errorf("%w while creating the dead-letter file handler", err)
+	}
+	defer func() {
+		_ = deadLetter.Close()
+		numRetried, numRejected := reindexingMetrics.GetItemsTotals()
+		if numRetried == 0 && numRejected == 0 {
+			return
+		}
+
+		log.Warn("some documents were not written at the first attempt",
+			"num retried documents", numRetried,
+			"num rejected documents", numRejected,
+			"dead-letter file", deadLetter.FilePath())
+	}()
+
+	if ctx.Bool(swapAliasFlag.Name) {
+		if resume {
+			return errors.New("the alias swap mode cannot be used together with the resume or the follow mode")
+		}
+
+		return startSwapping(cfg, reindexingMetrics, deadLetter, ctx.String(checkpointFileFlag.Name), ctx.String(verifyReportFlag.Name))
+	}
+
+	if ctx.IsSet(importDirFlag.Name) {
+		importer, errCreate := process.CreateImporter(cfg, reindexingMetrics, deadLetter)
+		if errCreate != nil {
+			return fmt.Errorf("%w while creating the importer", errCreate)
+		}
+
+		return startImporting(importer, cfg, ctx.String(importDirFlag.Name), ctx.Bool(overwriteFlag.Name), ctx.Bool(skipMappingsFlag.Name))
 	}
 
-	reindexer, err := process.CreateReindexer(cfg)
+	reindexer, err := createReindexerHandler(cfg, reindexingMetrics, deadLetter)
 	if err != nil {
-		log.Error("cannot create reindexer", "error", err)
-		return
+		return fmt.Errorf("%w while creating the reindexer", err)
 	}
 
-	multiWriteReindexer, err := process.NewReindexerMultiWrite(reindexer, cfg.Indexers.IndicesConfig)
+	checkpoint, err := process.NewFileCheckpoint(ctx.String(checkpointFileFlag.Name), resume)
 	if err != nil {
-		log.Error("cannot create multi-write reindexer", "error", err)
-		return
+		return fmt.Errorf("%w while creating the checkpoint", err)
+	}
+
+	if ctx.Bool(followFlag.Name) {
+		return startFollowing(reindexer, cfg, checkpoint, ctx.Bool(skipMappingsFlag.Name))
+	}
+
+	multiWriteReindexer, err := process.NewReindexerMultiWrite(reindexer, cfg.Indexers.IndicesConfig, checkpoint, reindexingMetrics)
+	if err != nil {
+		return fmt.Errorf("%w while creating the multi-write reindexer", err)
 	}
 
 	skipMappings := ctx.Bool(skipMappingsFlag.Name)
 	err = multiWriteReindexer.ProcessNoTimestamp(ctx.Bool(overwriteFlag.Name), skipMappings)
 	if err != nil {
-		log.Error(err.Error())
-		return
+		return err
 	}
 
-	err = multiWriteReindexer.ProcessWithTimestamp(ctx.Bool(overwriteFlag.Name), skipMappings)
+	results, err := multiWriteReindexer.ProcessWithTimestamp(ctx.Bool(overwriteFlag.Name), skipMappings)
 	if err != nil {
-		log.Error(err.Error())
-		return
+		return err
+	}
+
+	return checkResults(results)
+}
+
+// checkMultipleInputsMode returns an error if the selected mode reads from a single source
+func checkMultipleInputsMode(ctx *cli.Context) error {
+	singleSourceFlags := []string{dryRunFlag.Name, verifyFlag.Name, exportDirFlag.Name, rollbackAliasFlag.Name, swapAliasFlag.Name}
+	for _, flagName := range singleSourceFlags {
+		if ctx.IsSet(flagName) {
+			return fmt.Errorf("the --%s flag cannot be used when multiple inputs are configured", flagName)
+		}
+	}
+
+	return nil
+}
+
+func createReindexerHandler(
+	cfg *config.GeneralConfig,
+	reindexingMetrics process.MetricsHandler,
+	deadLetter process.DeadLetterHandler,
+) (process.ReindexerHandler, error) {
+	if len(cfg.Indexers.Inputs) > 0 {
+		return process.CreateMultiSourceReindexer(cfg, reindexingMetrics, deadLetter)
+	}
+
+	return process.CreateReindexer(cfg, reindexingMetrics, deadLetter)
+}
+
+func startFollowing(
+	reindexer process.ReindexerHandler,
+	cfg *config.GeneralConfig,
+	checkpoint process.CheckpointHandler,
+	skipMappings bool,
+) error {
+	follower, err := process.NewFollower(reindexer, cfg.Indexers.IndicesConfig, checkpoint)
+	if err != nil {
+		return fmt.Errorf("%w while creating the follower", err)
+	}
+
+	followCtx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	sigs := make(chan os.Signal, 1)
+	signal.Notify(sigs, syscall.SIGINT, syscall.SIGTERM)
+	go func() {
+		<-sigs
+		log.Info("stop signal received, the current round will be finished")
+		cancel()
+	}()
+
+	return follower.Follow(followCtx, skipMappings)
+}
+
+func startDryRun(cfg *config.GeneralConfig, overwrite bool, skipMappings bool) error {
+	planner, err := process.CreatePlanner(cfg)
+	if err != nil {
+		return fmt.Errorf("%w while creating the planner", err)
 	}
+
+	plans, err := planner.Plan(overwrite, skipMappings, time.Now().Unix())
+	if err != nil {
+		return err
+	}
+
+	for _, plan := range plans {
+		log.Info("index plan",
+			"index", plan.Index,
+			"alias exists", plan.AliasExists,
+			"index exists", plan.IndexExists,
+			"mapping", plan.MappingAction,
+			"source count", plan.SourceCount,
+			"estimated bytes", plan.EstimatedBytes)
+		for idx, intervalPlan := range plan.Intervals {
+			log.Info("\tinterval plan",
+				"index", plan.Index,
+				"interval nr", idx,
+				"start", intervalPlan.Start,
+				"stop", intervalPlan.Stop,
+				"source count", intervalPlan.SourceCount,
+				"estimated bytes", intervalPlan.EstimatedBytes)
+		}
+	}
+
+	return nil
+}
+
+func startExporting(cfg *config.GeneralConfig, directory string, compress bool) error {
+	exporter, err := process.CreateExporter(cfg, directory, compress)
+	if err != nil {
+		return fmt.Errorf("%w while creating the exporter", err)
+	}
+
+	return exporter.Export(time.Now().Unix())
+}
+
+func startImporting(
+	reindexer process.IndexImporter,
+	cfg *config.GeneralConfig,
+	directory string,
+	overwrite bool,
+	skipMappings bool,
+) error {
+	for _, index := range getConfiguredIndices(cfg) {
+		numDocuments, err := reindexer.ImportIndex(directory, index, overwrite, skipMappings)
+		if err != nil {
+			return fmt.Errorf("%w while importing index %s", err, index)
+		}
+
+		log.Info("index imported", "index", index, "num documents", numDocuments)
+	}
+
+	return nil
+}
+
+func startSwapping(
+	cfg *config.GeneralConfig,
+	reindexingMetrics process.MetricsHandler,
+	deadLetter process.DeadLetterHandler,
+	checkpointFile string,
+	reportPath string,
+) error {
+	swapper, err := process.CreateAliasSwapper(cfg)
+	if err != nil {
+		return fmt.Errorf("%w while creating the alias swapper", err)
+	}
+
+	generations := make(map[string]string)
+	for _, index := range getConfiguredIndices(cfg) {
+		generations[index], err = swapper.CreateGeneration(index)
+		if err != nil {
+			return fmt.Errorf("%w while creating the new generation of index %s", err, index)
+		}
+
+		log.Info("generation ready to be filled", "index", index, "generation", generations[index])
+	}
+
+	// all the components created from now on write in and read from the new generations
+	cfg.Indexers.IndicesConfig.DestinationIndices = generations
+
+	reindexer, err := process.CreateReindexer(cfg, reindexingMetrics, deadLetter)
+	if err != nil {
+		return fmt.Errorf("%w while creating the reindexer", err)
+	}
+
+	checkpoint, err := process.NewFileCheckpoint(checkpointFile, false)
+	if err != nil {
+		return fmt.Errorf("%w while creating the checkpoint", err)
+	}
+
+	multiWriteReindexer, err := process.NewReindexerMultiWrite(reindexer, cfg.Indexers.IndicesConfig, checkpoint, reindexingMetrics)
+	if err != nil {
+		return fmt.Errorf("%w while creating the multi-write reindexer", err)
+	}
+
+	// the new generations are already created, so the mappings are not copied
+	err = multiWriteReindexer.ProcessNoTimestamp(true, true)
+	if err != nil {
+		return err
+	}
+
+	results, err := multiWriteReindexer.ProcessWithTimestamp(true, true)
+	if err != nil {
+		return err
+	}
+
+	err = checkResults(results)
+	if err != nil {
+		return fmt.Errorf("%w, the aliases were not swapped", err)
+	}
+
+	// the documents written in the sources after the reindexing stop timestamp were not copied, so they are not verified
+	stopTimestamp := checkpoint.StopTimestamp()
+	if stopTimestamp == 0 {
+		stopTimestamp = time.Now().Unix()
+	}
+	err = startVerifying(cfg, reportPath, stopTimestamp)
+	if err != nil {
+		return fmt.Errorf("%w, the aliases were not swapped", err)
+	}
+
+	return swapper.Swap(generations)
+}
+
+func startRollingBack(cfg *config.GeneralConfig) error {
+	swapper, err := process.CreateAliasSwapper(cfg)
+	if err != nil {
+		return fmt.Errorf("%w while creating the alias swapper", err)
+	}
+
+	return swapper.Rollback(getConfiguredIndices(cfg))
+}
+
+// getConfiguredIndices returns all the configured indices, the ones without timestamp followed by the ones with timestamp
+func getConfiguredIndices(cfg *config.GeneralConfig) []string {
+	indices := append([]string{}, cfg.Indexers.IndicesConfig.Indices...)
+	if cfg.Indexers.IndicesConfig.WithTimestamp.Enabled {
+		indices = append(indices, cfg.Indexers.IndicesConfig.WithTimestamp.IndicesWithTimestamp...)
+	}
+
+	nonEmptyIndices := make([]string, 0, len(indices))
+	for _, index := range indices {
+		if index != "" {
+			nonEmptyIndices = append(nonEmptyIndices, index)
+		}
+	}
+
+	return nonEmptyIndices
+}
+
+func startVerifying(cfg *config.GeneralConfig, reportPath string, stopTimestamp int64) error {
+	reportFile, err := os.Create(reportPath)
+	if err != nil {
+		return fmt.Errorf("%w while creating the verification report", err)
+	}
+	defer func() {
+		_ = reportFile.Close()
+	}()
+
+	verifier, err := process.CreateVerifier(cfg, reportFile)
+	if err != nil {
+		return fmt.Errorf("%w while creating the verifier", err)
+	}
+
+	results, err := verifier.Verify(stopTimestamp)
+	if err != nil {
+		return err
+	}
+
+	numFailedIndices := 0
+	for _, result := range results {
+		if result.IsSuccessful() {
+			log.Info("index verified", "index", result.Index, "checked", result.NumChecked)
+			continue
+		}
+
+		numFailedIndices++
+		log.Error("index differs",
+			"index", result.Index,
+			"checked", result.NumChecked,
+			"missing", result.NumMissing,
+			"extra", result.NumExtra,
+			"differing", result.NumDiffering)
+		for _, errIndex := range result.Errors {
+			log.Error("\tverification error", "index", result.Index, "error", errIndex.Error())
+		}
+	}
+
+	if numFailedIndices > 0 {
+		return fmt.Errorf("verification found differences in %d indices out of %d, see %s", numFailedIndices, len(results), reportPath)
+	}
+
+	return nil
+}
+
+func checkResults(results []*process.IndexResult) error {
+	numFailedIndices := 0
+	for _, result := range results {
+		if result.IsSuccessful() {
+			log.Info("index reindexed",
+				"index", result.Index,
+				"intervals", result.IntervalsSucceeded,
+				"count source", result.SourceCount,
+				"count destination", result.DestinationCount,
+				"count rejected", result.RejectedCount)
+			continue
+		}
+
+		numFailedIndices++
+		log.Error("index not reindexed correctly",
+			"index", result.Index,
+			"intervals succeeded", result.IntervalsSucceeded,
+			"intervals failed", result.IntervalsFailed,
+			"count source", result.SourceCount,
+			"count destination", result.DestinationCount,
+			"count rejected", result.RejectedCount)
+		for _, errIndex := range result.Errors {
+			log.Error("\treindexing error", "index", result.Index, "error", errIndex.Error())
+		}
+	}
+
+	if numFailedIndices > 0 {
+		return fmt.Errorf("reindexing finished with %d failed indices out of %d", numFailedIndices, len(results))
+	}
+
+	return nil
 }
 
 func loadConfig() (*config.GeneralConfig, error) {
@@ -1,7 +1,6 @@
 package main
 
 import (
-	"bytes"
 	"fmt"
 	"io/ioutil"
 	"os"
@@ -9,6 +8,7 @@ import (
 
 	logger "github.com/multiversx/mx-chain-logger-go"
 	"github.com/multiversx/mx-chain-tools-go/elasticreindexer/config"
+	"github.com/multiversx/mx-chain-tools-go/elasticreindexer/creator"
 	"github.com/multiversx/mx-chain-tools-go/elasticreindexer/elastic"
 	"github.com/multiversx/mx-chain-tools-go/elasticreindexer/reader"
 	"github.com/pelletier/go-toml"
@@ -19,11 +19,21 @@ const configFileName = "cluster.toml"
 
 type Cfg struct {
 	ClusterConfig struct {
-		URL            string   `toml:"url"`
-		Username       string   `toml:"username"`
-		Password       string   `toml:"password"`
-		UseKibana      bool     `toml:"use-kibana"`
-		EnabledIndices []string `toml:"enabled-indices"`
+		Type           string           `toml:"type"`
+		URL            string           `toml:"url"`
+		Addresses      []string         `toml:"addresses"`
+		CloudID        string           `toml:"cloud-id"`
+		Username       string           `toml:"username"`
+		Password       string           `toml:"password"`
+		APIKey         string           `toml:"api-key"`
+		TLS            config.TLSConfig `toml:"tls"`
+		UseKibana      bool             `toml:"use-kibana"`
+		EnabledIndices []string         `toml:"enabled-indices"`
+		IndexPrefix    string           `toml:"index-prefix"`
+		Lifecycle      struct {
+			Type            string            `toml:"type"`
+			IndicesPolicies map[string]string `toml:"indices-policies"`
+		} `toml:"lifecycle"`
 	} `toml:"config"`
 }
 
@@ -36,16 +46,36 @@ var (
 		Usage: "The path to the config folder",
 		Value: "./config",
 	}
+	// prefixFlag defines the prefix of the templates and indices deleted by the destroy command
+	prefixFlag = cli.StringFlag{
+		Name:  "prefix",
+		Usage: "If set, all the templates and indices starting with this prefix are deleted instead of the configured ones",
+	}
+	// yesFlag defines a bool flag for confirming the deletion done by the destroy command
+	yesFlag = cli.BoolFlag{
+		Name:  "yes",
+		Usage: "Confirms the deletion, otherwise the destroy command only prints what would be deleted",
+	}
 )
 
+type indicesManager interface {
+	CreateIndices() error
+	Plan() ([]*creator.Change, error)
+	Apply(changes []*creator.Change) error
+	PlanDestroy(prefix string) ([]*creator.Change, error)
+}
+
 const helpTemplate = `NAME:
    {{.Name}} - {{.Usage}}
 USAGE:
-   {{.HelpName}} {{if .VisibleFlags}}[global options]{{end}}
+   {{.HelpName}} {{if .VisibleFlags}}[global options]{{end}}{{if .Commands}} [command]{{end}}
    {{if len .Authors}}
 AUTHOR:
    {{range .Authors}}{{ . }}{{end}}
    {{end}}{{if .Commands}}
+COMMANDS:
+   {{range .Commands}}{{join .Names ", "}}{{ "\t" }}{{.Usage}}
+   {{end}}
 GLOBAL OPTIONS:
    {{range .VisibleFlags}}{{.}}
    {{end}}
@@ -69,6 +99,24 @@ func main() {
 			Email: "contact@multiversx.com",
 		},
 	}
+	app.Commands = []cli.Command{
+		{
+			Name:   "plan",
+			Usage:  "Prints the differences between the configured index set and the cluster",
+			Action: planIndices,
+		},
+		{
+			Name:   "apply",
+			Usage:  "Applies the changes needed for the cluster to match the configured index set",
+			Action: applyIndices,
+		},
+		{
+			Name:   "destroy",
+			Usage:  "Deletes the templates and the indices of the configured index set or of the provided prefix",
+			Flags:  []cli.Flag{prefixFlag, yesFlag},
+			Action: destroyIndices,
+		},
+	}
 
 	_ = logger.SetLogLevel("*:DEBUG")
 
@@ -83,78 +131,136 @@ func main() {
 }
 
 func createIndexesAndMappings(ctx *cli.Context) {
-	cfgPath := ctx.String(configPath.Name)
-	cfg, err := loadConfigFile(cfgPath)
+	manager, err := createIndicesManager(ctx.String(configPath.Name))
 	if err != nil {
-		log.Error("cannot load config file", "error", err.Error())
+		log.Error("cannot create the indices creator", "error", err.Error())
 		return
 	}
 
-	pathToMappings := path.Join(cfgPath, "noKibana")
-	if cfg.ClusterConfig.UseKibana {
-		pathToMappings = path.Join(cfgPath, "withKibana")
+	err = manager.CreateIndices()
+	if err != nil {
+		log.Error("cannot create templates", "error", err.Error())
+		return
 	}
 
-	indexesMappings, _, err := reader.GetElasticTemplatesAndPolicies(pathToMappings, cfg.ClusterConfig.EnabledIndices)
+	log.Info("all indices were created")
+}
+
+func planIndices(ctx *cli.Context) error {
+	manager, err := createIndicesManager(ctx.GlobalString(configPath.Name))
 	if err != nil {
-		log.Error("cannot load templates", "error", err.Error())
-		return
+		return err
 	}
 
-	err = createIndies(cfg, indexesMappings)
+	changes, err := manager.Plan()
 	if err != nil {
-		log.Error("cannot create templates", "error", err.Error())
-		return
+		return err
 	}
 
-	log.Info("all indices were created")
+	printChanges(changes)
+
+	return nil
 }
 
-func createIndies(cfg *Cfg, indexesMappings map[string]*bytes.Buffer) error {
-	databaseClient, err := elastic.NewElasticClient(config.ElasticInstanceConfig{
-		URL:      cfg.ClusterConfig.URL,
-		Username: cfg.ClusterConfig.Username,
-		Password: cfg.ClusterConfig.Password,
-	})
+func applyIndices(ctx *cli.Context) error {
+	manager, err := createIndicesManager(ctx.GlobalString(configPath.Name))
 	if err != nil {
 		return err
 	}
 
-	for index, indexData := range indexesMappings {
-		doesTemplateExists := databaseClient.DoesTemplateExist(index)
-		if !doesTemplateExists {
-			errCheck := databaseClient.PutIndexTemplate(index, indexData)
-			if errCheck != nil {
-				return fmt.Errorf("databaseClient.CreateIndexWithMapping index: %s, error: %w", index, errCheck)
-			}
+	changes, err := manager.Plan()
+	if err != nil {
+		return err
+	}
 
-			log.Info("databaseClient.PutIndexTemplate", "index", index)
-		}
+	printChanges(changes)
 
-		indexWithSuffix := fmt.Sprintf("%s-%s", index, "000001")
-		alreadyExists := databaseClient.DoesIndexExist(index)
-		if !alreadyExists {
-			errCreate := databaseClient.CreateIndexWithMapping(indexWithSuffix, nil)
-			if errCreate != nil {
-				return fmt.Errorf("databaseClient.CreateIndexWithMapping index: %s, error: %w", index, errCreate)
-			}
+	return manager.Apply(changes)
+}
 
-			log.Info("databaseClient.CreateIndexWithMapping", "index", index)
-		}
+func destroyIndices(ctx *cli.Context) error {
+	manager, err := createIndicesManager(ctx.GlobalString(configPath.Name))
+	if err != nil {
+		return err
+	}
+
+	changes, err := manager.PlanDestroy(ctx.String(prefixFlag.Name))
+	if err != nil {
+		return err
+	}
+
+	printChanges(changes)
+	if !ctx.Bool(yesFlag.Name) {
+		log.Info("nothing was deleted, run the destroy command with the --yes flag to delete")
+		return nil
+	}
+
+	return manager.Apply(changes)
+}
 
-		aliasExists := databaseClient.DoesAliasExist(index)
-		if !aliasExists {
-			errAlias := databaseClient.PutAlias(indexWithSuffix, index)
-			if err != nil {
-				return fmt.Errorf("databaseClient.PutAlias index: %s, error: %w", index, errAlias)
-			}
+func printChanges(changes []*creator.Change) {
+	if len(changes) == 0 {
+		log.Info("the cluster matches the configuration, no changes")
+		return
+	}
 
-			log.Info("databaseClient.PutAlias", "index", index)
-		}
+	for _, change := range changes {
+		log.Info("planned change", "change", change.String())
+	}
+	log.Info("planned changes", "num changes", len(changes))
+}
 
+func createIndicesManager(cfgPath string) (indicesManager, error) {
+	cfg, err := loadConfigFile(cfgPath)
+	if err != nil {
+		return nil, fmt.Errorf("%w while loading the config file", err)
 	}
 
-	return nil
+	pathToMappings := path.Join(cfgPath, "noKibana")
+	if cfg.ClusterConfig.UseKibana {
+		pathToMappings = path.Join(cfgPath, "withKibana")
+	}
+
+	lifecycle := cfg.ClusterConfig.Lifecycle
+	indicesPolicies := lifecycle.IndicesPolicies
+	if lifecycle.Type == "" {
+		indicesPolicies = make(map[string]string)
+	}
+	pathToPolicies := path.Join(cfgPath, "policies", lifecycle.Type)
+
+	indexPrefix := cfg.ClusterConfig.IndexPrefix
+	indexesMappings, policies, err := reader.GetElasticTemplatesAndPolicies(pathToMappings, pathToPolicies, cfg.ClusterConfig.EnabledIndices, indicesPolicies, indexPrefix)
+	if err != nil {
+		return nil, fmt.Errorf("%w while loading the templates", err)
+	}
+
+	// the templates, the indices and the aliases are all named with the prefix
+	prefixedIndicesPolicies := make(map[string]string, len(indicesPolicies))
+	for index, policy := range indicesPolicies {
+		prefixedIndicesPolicies[indexPrefix+index] = policy
+	}
+
+	databaseClient, err := elastic.NewElasticClient(config.ElasticInstanceConfig{
+		Type:      cfg.ClusterConfig.Type,
+		URL:       cfg.ClusterConfig.URL,
+		Addresses: cfg.ClusterConfig.Addresses,
+		CloudID:   cfg.ClusterConfig.CloudID,
+		Username:  cfg.ClusterConfig.Username,
+		Password:  cfg.ClusterConfig.Password,
+		APIKey:    cfg.ClusterConfig.APIKey,
+		TLS:       cfg.ClusterConfig.TLS,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return creator.NewIndicesCreator(creator.ArgsIndicesCreator{
+		DatabaseClient:  databaseClient,
+		Templates:       indexesMappings,
+		Policies:        policies,
+		IndicesPolicies: prefixedIndicesPolicies,
+		PolicyType:      lifecycle.Type,
+	})
 }
 
 func loadConfigFile(pathStr string) (*Cfg, error) {
@@ -5,27 +5,119 @@ type GeneralConfig struct {
 	Indexers IndexersConfig `toml:"config"`
 }
 
-// IndexersConfig holds the configuration related to indexers
+// IndexersConfig holds the configuration related to indexers. The Inputs, if set, replace the Input and the documents
+// are read from several sources
 type IndexersConfig struct {
 	Input         ElasticInstanceConfig `toml:"input"`
+	Inputs        []InputConfig         `toml:"inputs"`
 	Output        ElasticInstanceConfig `toml:"output"`
 	IndicesConfig IndicesConfig         `toml:"indices"`
 }
 
+// InputConfig holds a source of a multi-source reindexing. Inside its time window (a zero stop time means no limit),
+// the source is used unless a source with a higher priority also covers the time
+type InputConfig struct {
+	ElasticInstanceConfig
+	Priority  int   `toml:"priority"`
+	StartTime int64 `toml:"start-time"`
+	StopTime  int64 `toml:"stop-time"`
+}
+
 // ElasticInstanceConfig holds the configuration needed for connecting to an Elasticsearch instance
 type ElasticInstanceConfig struct {
-	URL      string `toml:"url"`
-	Username string `toml:"username"`
-	Password string `toml:"password"`
+	Type          string    `toml:"type"`
+	Tag           string    `toml:"tag"`
+	URL           string    `toml:"url"`
+	Addresses     []string  `toml:"addresses"`
+	CloudID       string    `toml:"cloud-id"`
+	Username      string    `toml:"username"`
+	Password      string    `toml:"password"`
+	APIKey        string    `toml:"api-key"`
+	TLS           TLSConfig `toml:"tls"`
+	IterationMode string    `toml:"iteration-mode"`
+}
+
+// TLSConfig holds the certificates used when connecting to an Elasticsearch instance over HTTPS
+type TLSConfig struct {
+	CACertFile         string `toml:"ca-cert-file"`
+	ClientCertFile     string `toml:"client-cert-file"`
+	ClientKeyFile      string `toml:"client-key-file"`
+	InsecureSkipVerify bool   `toml:"insecure-skip-verify"`
 }
 
 // IndicesConfig holds the configuration for the indices
 type IndicesConfig struct {
-	Indices       []string `toml:"indices-no-timestamp"`
-	WithTimestamp struct {
-		Enabled              bool     `toml:"enabled"`
-		BlockchainStartTime  int64    `toml:"blockchain-start-time"`
-		NumParallelWrites    int      `toml:"num-parallel-writes"`
-		IndicesWithTimestamp []string `toml:"indices-with-timestamp"`
+	Indices                  []string                  `toml:"indices-no-timestamp"`
+	SourceIndexPrefix        string                    `toml:"source-index-prefix"`
+	DestinationIndexPrefix   string                    `toml:"destination-index-prefix"`
+	PageSize                 int                       `toml:"page-size"`
+	BulkSizeThresholdInBytes int                       `toml:"bulk-size-threshold-in-bytes"`
+	Throttling               ThrottlingConfig          `toml:"throttling"`
+	BulkItems                BulkItemsConfig           `toml:"bulk-items"`
+	Documents                DocumentsConfig           `toml:"documents"`
+	PerIndex                 map[string]PerIndexConfig `toml:"per-index"`
+	WithTimestamp            struct {
+		Enabled                        bool     `toml:"enabled"`
+		BlockchainStartTime            int64    `toml:"blockchain-start-time"`
+		NumParallelWrites              int      `toml:"num-parallel-writes"`
+		NumRetriesFailedIntervals      int      `toml:"num-retries-failed-intervals"`
+		DelayBetweenIntervalsStartInMs int      `toml:"delay-between-intervals-start-in-ms"`
+		IndicesWithTimestamp           []string `toml:"indices-with-timestamp"`
 	} `toml:"with-timestamp"`
+	Follow   FollowConfig   `toml:"follow"`
+	Mappings MappingsConfig `toml:"mappings"`
+	// DestinationIndices replaces the destination of some indices with concrete indices. It is not read from the
+	// config file, the alias swap mode sets it with the new generations of the indices
+	DestinationIndices map[string]string `toml:"-"`
+}
+
+// DocumentsConfig holds how the documents are written in the destination: the template of their ids (e.g.
+// "{tag}-{id}"), the bulk action ("index", "create" or "update") and whether the source routing is kept
+type DocumentsConfig struct {
+	IDTemplate      string `toml:"id-template"`
+	BulkAction      string `toml:"bulk-action"`
+	PreserveRouting bool   `toml:"preserve-routing"`
+}
+
+// MappingsConfig holds the settings used when the destination index is created or already exists
+type MappingsConfig struct {
+	Compare            bool   `toml:"compare"`
+	ForceIncompatible  bool   `toml:"force-incompatible"`
+	TemplatesDirectory string `toml:"templates-directory"`
+}
+
+// FollowConfig holds the configuration used when keeping the destination in sync with the source
+type FollowConfig struct {
+	PollIntervalInSeconds int   `toml:"poll-interval-in-seconds"`
+	OverlapInSeconds      int64 `toml:"overlap-in-seconds"`
+}
+
+// BulkItemsConfig holds the handling of the bulk items that failed. The items failed with a retryable status (429, 503)
+// are sent again, the other ones and the ones still failing after all the retries are written in the dead-letter file
+type BulkItemsConfig struct {
+	NumRetries              int `toml:"num-retries"`
+	DelayBetweenRetriesInMs int `toml:"delay-between-retries-in-ms"`
+}
+
+// ThrottlingConfig holds the limits applied on the bulk requests sent to the destination. A zero value disables the limit
+type ThrottlingConfig struct {
+	DocumentsPerSecond uint64 `toml:"documents-per-second"`
+	BytesPerSecond     uint64 `toml:"bytes-per-second"`
+}
+
+// PerIndexConfig holds the filter and the fields transformations applied when reindexing an index. The id template
+// and the bulk action, if set, replace the ones from the documents config
+type PerIndexConfig struct {
+	Query         string              `toml:"query"`
+	IncludeFields []string            `toml:"include-fields"`
+	ExcludeFields []string            `toml:"exclude-fields"`
+	RenameFields  []RenameFieldConfig `toml:"rename-fields"`
+	IDTemplate    string              `toml:"id-template"`
+	BulkAction    string              `toml:"bulk-action"`
+}
+
+// RenameFieldConfig holds the old and the new name of a renamed field
+type RenameFieldConfig struct {
+	From string `toml:"from"`
+	To   string `toml:"to"`
 }
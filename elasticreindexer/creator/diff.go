@@ -0,0 +1,213 @@
+package creator
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+const indexSettingsPrefix = "index."
+
+// comparedTemplateSections are the parts of a template that are compared with the template from the cluster
+var comparedTemplateSections = []string{"index_patterns", "mappings", "settings", "aliases"}
+
+// dynamicSettings are the index settings that can be updated on an existing index. The keys do not have the "index." prefix
+var dynamicSettings = map[string]struct{}{
+	"number_of_replicas":       {},
+	"refresh_interval":         {},
+	"max_result_window":        {},
+	"lifecycle.name":           {},
+	"lifecycle.rollover_alias": {},
+	"opendistro.index_state_management.rollover_alias": {},
+}
+
+// compareTemplates returns the differences between the desired template and the template from the cluster, one per
+// flattened key, e.g. "mappings.properties.nonce.type: long -> keyword"
+func compareTemplates(desired []byte, actual []byte) ([]string, error) {
+	desiredFields, err := flattenTemplate(desired)
+	if err != nil {
+		return nil, fmt.Errorf("%w while decoding the desired template", err)
+	}
+
+	actualFields, err := flattenTemplate(actual)
+	if err != nil {
+		return nil, fmt.Errorf("%w while decoding the cluster template", err)
+	}
+
+	return compareFlattened(desiredFields, actualFields), nil
+}
+
+// comparePolicies returns the differences between the desired policy and the policy from the cluster, one per
+// flattened key of the policy, e.g. "states.0.actions.0.rollover.min_size: 30gb -> 50gb". The arrays are compared
+// element by element and their lengths are compared as the "<array>.#" keys. The keys only set by the cluster (like the
+// versions, the timestamps or the default values) are not compared
+func comparePolicies(desired []byte, actual []byte) ([]string, error) {
+	desiredFields, err := flattenPolicy(desired)
+	if err != nil {
+		return nil, fmt.Errorf("%w while decoding the desired policy", err)
+	}
+
+	actualFields, err := flattenPolicy(actual)
+	if err != nil {
+		return nil, fmt.Errorf("%w while decoding the cluster policy", err)
+	}
+
+	differences := make([]string, 0)
+	for key, desiredValue := range desiredFields {
+		actualValue, found := actualFields[key]
+		if !found {
+			differences = append(differences, fmt.Sprintf("%s: missing -> %s", key, desiredValue))
+			continue
+		}
+		if actualValue != desiredValue {
+			differences = append(differences, fmt.Sprintf("%s: %s -> %s", key, actualValue, desiredValue))
+		}
+	}
+	sort.Strings(differences)
+
+	return differences, nil
+}
+
+func flattenPolicy(policy []byte) (map[string]string, error) {
+	body := struct {
+		Policy interface{} `json:"policy"`
+	}{}
+	err := json.Unmarshal(policy, &body)
+	if err != nil {
+		return nil, err
+	}
+
+	fields := make(map[string]string)
+	flattenJSONWithArrays("", body.Policy, fields)
+
+	return fields, nil
+}
+
+// flattenJSONWithArrays writes in the result all the leaves of the value, like flattenJSON, but the arrays are
+// flattened element by element, with the index in the key, and their length is written under the "<array>.#" key
+func flattenJSONWithArrays(prefix string, value interface{}, result map[string]string) {
+	joinKey := func(key string) string {
+		if prefix == "" {
+			return key
+		}
+
+		return prefix + "." + key
+	}
+
+	switch typedValue := value.(type) {
+	case map[string]interface{}:
+		for key, child := range typedValue {
+			flattenJSONWithArrays(joinKey(key), child, result)
+		}
+	case []interface{}:
+		result[joinKey("#")] = fmt.Sprint(len(typedValue))
+		for idx, element := range typedValue {
+			flattenJSONWithArrays(joinKey(fmt.Sprint(idx)), element, result)
+		}
+	default:
+		result[prefix] = stringifyValue(typedValue)
+	}
+}
+
+func flattenTemplate(template []byte) (map[string]string, error) {
+	sections := make(map[string]interface{})
+	err := json.Unmarshal(template, &sections)
+	if err != nil {
+		return nil, err
+	}
+
+	fields := make(map[string]string)
+	for _, section := range comparedTemplateSections {
+		value, found := sections[section]
+		if !found {
+			continue
+		}
+
+		if section == "settings" {
+			for key, settingValue := range flattenSettings(value) {
+				fields[section+"."+key] = settingValue
+			}
+			continue
+		}
+
+		flattenJSON(section, value, fields)
+	}
+
+	return fields, nil
+}
+
+// flattenSettings returns the settings with flat keys, without the "index." prefix, so the settings written as
+// {"index": {"number_of_shards": 5}}, {"number_of_shards": 5} or {"index.number_of_shards": "5"} are the same
+func flattenSettings(settings interface{}) map[string]string {
+	flattened := make(map[string]string)
+	flattenJSON("", settings, flattened)
+
+	normalized := make(map[string]string, len(flattened))
+	for key, value := range flattened {
+		normalized[strings.TrimPrefix(key, indexSettingsPrefix)] = value
+	}
+
+	return normalized
+}
+
+// flattenJSON writes in the result all the leaves of the value, with the dotted path as key and the value as string.
+// The arrays are kept as leaves and the empty objects are ignored
+func flattenJSON(prefix string, value interface{}, result map[string]string) {
+	switch typedValue := value.(type) {
+	case map[string]interface{}:
+		for key, child := range typedValue {
+			childPrefix := key
+			if prefix != "" {
+				childPrefix = prefix + "." + key
+			}
+
+			flattenJSON(childPrefix, child, result)
+		}
+	case []interface{}:
+		elements := make([]string, 0, len(typedValue))
+		for _, element := range typedValue {
+			elements = append(elements, stringifyValue(element))
+		}
+
+		result[prefix] = "[" + strings.Join(elements, ",") + "]"
+	default:
+		result[prefix] = stringifyValue(typedValue)
+	}
+}
+
+func stringifyValue(value interface{}) string {
+	switch typedValue := value.(type) {
+	case map[string]interface{}, []interface{}:
+		valueBytes, _ := json.Marshal(typedValue)
+		return string(valueBytes)
+	case nil:
+		return "null"
+	default:
+		return fmt.Sprint(typedValue)
+	}
+}
+
+func compareFlattened(desired map[string]string, actual map[string]string) []string {
+	differences := make([]string, 0)
+	for key, desiredValue := range desired {
+		actualValue, found := actual[key]
+		if !found {
+			differences = append(differences, fmt.Sprintf("%s: missing -> %s", key, desiredValue))
+			continue
+		}
+		if actualValue != desiredValue {
+			differences = append(differences, fmt.Sprintf("%s: %s -> %s", key, actualValue, desiredValue))
+		}
+	}
+	for key, actualValue := range actual {
+		_, found := desired[key]
+		if !found {
+			differences = append(differences, fmt.Sprintf("%s: %s -> removed", key, actualValue))
+		}
+	}
+
+	sort.Strings(differences)
+
+	return differences
+}
@@ -0,0 +1,210 @@
+package creator
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/multiversx/mx-chain-core-go/core/check"
+	logger "github.com/multiversx/mx-chain-logger-go"
+	"github.com/multiversx/mx-chain-tools-go/elasticreindexer/elastic"
+)
+
+const firstIndexSuffix = "-000001"
+
+var (
+	log = logger.GetOrCreate("creator")
+
+	errNilDatabaseClient = errors.New("nil database client")
+	errUnknownPolicyType = errors.New("unknown policy type")
+	errMissingPolicy     = errors.New("missing policy")
+)
+
+// ArgsIndicesCreator is the DTO used in the NewIndicesCreator constructor function
+type ArgsIndicesCreator struct {
+	DatabaseClient  DatabaseClientHandler
+	Templates       map[string]*bytes.Buffer
+	Policies        map[string]*bytes.Buffer
+	IndicesPolicies map[string]string
+	PolicyType      string
+}
+
+type indicesCreator struct {
+	databaseClient  DatabaseClientHandler
+	templates       map[string]*bytes.Buffer
+	policies        map[string]*bytes.Buffer
+	indicesPolicies map[string]string
+	policyType      string
+}
+
+// NewIndicesCreator creates a component able to create the templates, the lifecycle policies, the indices and their aliases
+func NewIndicesCreator(args ArgsIndicesCreator) (*indicesCreator, error) {
+	if check.IfNil(args.DatabaseClient) {
+		return nil, errNilDatabaseClient
+	}
+
+	indicesPolicies := args.IndicesPolicies
+	if args.PolicyType == "" {
+		indicesPolicies = make(map[string]string)
+	}
+	if len(indicesPolicies) > 0 {
+		_, err := getLifecycleSettings(args.PolicyType, "", "")
+		if err != nil {
+			return nil, err
+		}
+	}
+	for index, policy := range indicesPolicies {
+		_, found := args.Policies[policy]
+		if !found {
+			return nil, fmt.Errorf("%w %s for index %s", errMissingPolicy, policy, index)
+		}
+	}
+
+	return &indicesCreator{
+		databaseClient:  args.DatabaseClient,
+		templates:       args.Templates,
+		policies:        args.Policies,
+		indicesPolicies: indicesPolicies,
+		policyType:      args.PolicyType,
+	}, nil
+}
+
+// CreateIndices will create the lifecycle policies, then, for every index, its template, its first index and its alias.
+// The indices with a lifecycle policy get a rollover-ready write alias
+func (ic *indicesCreator) CreateIndices() error {
+	err := ic.createPolicies()
+	if err != nil {
+		return err
+	}
+
+	for _, index := range ic.sortedIndices() {
+		err = ic.createIndex(index)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (ic *indicesCreator) sortedIndices() []string {
+	indices := make([]string, 0, len(ic.templates))
+	for index := range ic.templates {
+		indices = append(indices, index)
+	}
+	sort.Strings(indices)
+
+	return indices
+}
+
+func (ic *indicesCreator) createPolicies() error {
+	for policyName := range ic.policies {
+		if !ic.isPolicyUsed(policyName) {
+			continue
+		}
+		if ic.databaseClient.DoesPolicyExist(ic.policyType, policyName) {
+			continue
+		}
+
+		policy, err := ic.getDesiredPolicy(policyName)
+		if err != nil {
+			return err
+		}
+
+		err = ic.databaseClient.PutPolicy(ic.policyType, policyName, policy)
+		if err != nil {
+			return fmt.Errorf("databaseClient.PutPolicy policy: %s, error: %w", policyName, err)
+		}
+
+		log.Info("databaseClient.PutPolicy", "policy", policyName, "type", ic.policyType)
+	}
+
+	return nil
+}
+
+func (ic *indicesCreator) isPolicyUsed(policyName string) bool {
+	for _, policy := range ic.indicesPolicies {
+		if policy == policyName {
+			return true
+		}
+	}
+
+	return false
+}
+
+// getPolicyIndices returns the sorted indices managed by the provided policy
+func (ic *indicesCreator) getPolicyIndices(policyName string) []string {
+	indices := make([]string, 0)
+	for index, policy := range ic.indicesPolicies {
+		if policy == policyName {
+			indices = append(indices, index)
+		}
+	}
+	sort.Strings(indices)
+
+	return indices
+}
+
+// getDesiredPolicy returns the body of the policy as it has to be created in the cluster: the ISM policies also hold
+// the patterns of the indices they manage
+func (ic *indicesCreator) getDesiredPolicy(policyName string) (*bytes.Buffer, error) {
+	policy := ic.policies[policyName]
+	if ic.policyType != elastic.PolicyTypeISM {
+		return policy, nil
+	}
+
+	return attachISMTemplate(policy, policyName, ic.getPolicyIndices(policyName))
+}
+
+func (ic *indicesCreator) createIndex(index string) error {
+	policyName, hasPolicy := ic.indicesPolicies[index]
+
+	doesTemplateExists := ic.databaseClient.DoesTemplateExist(index)
+	if !doesTemplateExists {
+		template := ic.templates[index]
+		if hasPolicy {
+			var err error
+			template, err = attachPolicy(template, index, ic.policyType, policyName)
+			if err != nil {
+				return err
+			}
+		}
+
+		err := ic.databaseClient.PutIndexTemplate(index, template)
+		if err != nil {
+			return fmt.Errorf("databaseClient.PutIndexTemplate index: %s, error: %w", index, err)
+		}
+
+		log.Info("databaseClient.PutIndexTemplate", "index", index, "policy", policyName)
+	}
+
+	// the alias also resolves to the current write index after a rollover
+	indexWithSuffix := index + firstIndexSuffix
+	alreadyExists := ic.databaseClient.DoesIndexExist(index)
+	if !alreadyExists {
+		var body *bytes.Buffer
+		if hasPolicy {
+			body = createWriteAliasBody(index)
+		}
+
+		err := ic.databaseClient.CreateIndexWithMapping(indexWithSuffix, body)
+		if err != nil {
+			return fmt.Errorf("databaseClient.CreateIndexWithMapping index: %s, error: %w", index, err)
+		}
+
+		log.Info("databaseClient.CreateIndexWithMapping", "index", index, "write alias", hasPolicy)
+	}
+
+	aliasExists := ic.databaseClient.DoesAliasExist(index)
+	if !aliasExists {
+		err := ic.databaseClient.PutAlias(indexWithSuffix, index)
+		if err != nil {
+			return fmt.Errorf("databaseClient.PutAlias index: %s, error: %w", index, err)
+		}
+
+		log.Info("databaseClient.PutAlias", "index", index)
+	}
+
+	return nil
+}
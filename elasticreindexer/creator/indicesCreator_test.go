@@ -0,0 +1,188 @@
+package creator
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/multiversx/mx-chain-tools-go/elasticreindexer/creator/mock"
+	"github.com/multiversx/mx-chain-tools-go/elasticreindexer/elastic"
+	"github.com/stretchr/testify/require"
+)
+
+const testTemplate = `{"index_patterns":["transactions-*"],"settings":{"number_of_shards":5}}`
+
+func createMockArgsIndicesCreator(client DatabaseClientHandler) ArgsIndicesCreator {
+	return ArgsIndicesCreator{
+		DatabaseClient: client,
+		Templates: map[string]*bytes.Buffer{
+			"transactions": bytes.NewBufferString(testTemplate),
+			"blocks":       bytes.NewBufferString(`{"index_patterns":["blocks-*"]}`),
+		},
+		Policies: map[string]*bytes.Buffer{
+			"rollover": bytes.NewBufferString(`{"policy":{}}`),
+		},
+		IndicesPolicies: map[string]string{
+			"transactions": "rollover",
+		},
+		PolicyType: elastic.PolicyTypeILM,
+	}
+}
+
+func TestNewIndicesCreator(t *testing.T) {
+	args := createMockArgsIndicesCreator(nil)
+	ic, err := NewIndicesCreator(args)
+	require.Nil(t, ic)
+	require.Equal(t, errNilDatabaseClient, err)
+
+	args = createMockArgsIndicesCreator(&mock.DatabaseClientStub{})
+	args.PolicyType = "unknown"
+	ic, err = NewIndicesCreator(args)
+	require.Nil(t, ic)
+	require.True(t, errors.Is(err, errUnknownPolicyType))
+
+	args = createMockArgsIndicesCreator(&mock.DatabaseClientStub{})
+	args.IndicesPolicies["blocks"] = "missing"
+	ic, err = NewIndicesCreator(args)
+	require.Nil(t, ic)
+	require.True(t, errors.Is(err, errMissingPolicy))
+
+	args = createMockArgsIndicesCreator(&mock.DatabaseClientStub{})
+	args.PolicyType = ""
+	args.IndicesPolicies["blocks"] = "missing"
+	ic, err = NewIndicesCreator(args)
+	require.NotNil(t, ic)
+	require.NoError(t, err)
+	require.Empty(t, ic.indicesPolicies)
+}
+
+func TestIndicesCreator_CreateIndicesWithPolicies(t *testing.T) {
+	putPolicies := make([]string, 0)
+	templates := make(map[string]string)
+	createdIndices := make(map[string]string)
+	aliases := make(map[string]string)
+	client := &mock.DatabaseClientStub{
+		PutPolicyCalled: func(policyType string, policyName string, _ *bytes.Buffer) error {
+			require.Equal(t, elastic.PolicyTypeILM, policyType)
+			putPolicies = append(putPolicies, policyName)
+			return nil
+		},
+		PutIndexTemplateCalled: func(templateName string, body *bytes.Buffer) error {
+			templates[templateName] = body.String()
+			return nil
+		},
+		CreateIndexWithMappingCalled: func(targetIndex string, body *bytes.Buffer) error {
+			createdIndices[targetIndex] = ""
+			if body != nil {
+				createdIndices[targetIndex] = body.String()
+			}
+			return nil
+		},
+		DoesAliasExistCalled: func(alias string) bool {
+			// the write alias is created together with the index
+			return alias == "transactions"
+		},
+		PutAliasCalled: func(index string, alias string) error {
+			aliases[alias] = index
+			return nil
+		},
+	}
+
+	ic, _ := NewIndicesCreator(createMockArgsIndicesCreator(client))
+	require.NoError(t, ic.CreateIndices())
+
+	require.Equal(t, []string{"rollover"}, putPolicies)
+
+	settings := struct {
+		Settings map[string]interface{} `json:"settings"`
+	}{}
+	require.NoError(t, json.Unmarshal([]byte(templates["transactions"]), &settings))
+	require.Equal(t, "rollover", settings.Settings[ilmPolicySetting])
+	require.Equal(t, "transactions", settings.Settings[ilmRolloverAliasSetting])
+	require.Equal(t, float64(5), settings.Settings["number_of_shards"])
+	require.Equal(t, `{"index_patterns":["blocks-*"]}`, templates["blocks"])
+
+	require.JSONEq(t, `{"aliases":{"transactions":{"is_write_index":true}}}`, createdIndices["transactions-000001"])
+	require.Equal(t, "", createdIndices["blocks-000001"])
+	require.Equal(t, map[string]string{"blocks": "blocks-000001"}, aliases)
+}
+
+func TestIndicesCreator_CreateIndicesShouldSkipExistingPolicies(t *testing.T) {
+	client := &mock.DatabaseClientStub{
+		DoesPolicyExistCalled: func(_ string, _ string) bool {
+			return true
+		},
+		PutPolicyCalled: func(_ string, _ string, _ *bytes.Buffer) error {
+			require.Fail(t, "should not put an existing policy")
+			return nil
+		},
+	}
+
+	ic, _ := NewIndicesCreator(createMockArgsIndicesCreator(client))
+	require.NoError(t, ic.CreateIndices())
+}
+
+func TestAttachPolicy_ISM(t *testing.T) {
+	template, err := attachPolicy(bytes.NewBufferString(`{"index_patterns":["logs-*"]}`), "logs", elastic.PolicyTypeISM, "rollover")
+	require.NoError(t, err)
+	require.JSONEq(t, `{
+		"index_patterns": ["logs-*"],
+		"settings": {
+			"opendistro.index_state_management.rollover_alias": "logs"
+		}
+	}`, template.String())
+}
+
+func TestAttachISMTemplate(t *testing.T) {
+	t.Run("policy without ism_template", func(t *testing.T) {
+		policy, err := attachISMTemplate(bytes.NewBufferString(`{"policy":{"default_state":"hot"}}`), "rollover", []string{"blocks", "logs"})
+		require.NoError(t, err)
+		require.JSONEq(t, `{
+			"policy": {
+				"default_state": "hot",
+				"ism_template": [{"index_patterns": ["blocks-*", "logs-*"], "priority": 100}]
+			}
+		}`, policy.String())
+	})
+	t.Run("the existing ism_template should be kept", func(t *testing.T) {
+		policy, err := attachISMTemplate(bytes.NewBufferString(`{"policy":{"ism_template":{"index_patterns":["other-*"]}}}`), "rollover", []string{"logs"})
+		require.NoError(t, err)
+		require.JSONEq(t, `{
+			"policy": {
+				"ism_template": [{"index_patterns": ["other-*"]}, {"index_patterns": ["logs-*"], "priority": 100}]
+			}
+		}`, policy.String())
+	})
+	t.Run("invalid policy should err", func(t *testing.T) {
+		_, err := attachISMTemplate(bytes.NewBufferString(`{"policy":`), "rollover", []string{"logs"})
+		require.Error(t, err)
+	})
+}
+
+func TestIndicesCreator_CreateIndicesShouldPutTheISMPolicyWithTheIndicesPatterns(t *testing.T) {
+	policies := make(map[string]string)
+	templates := make(map[string]string)
+	client := &mock.DatabaseClientStub{
+		PutPolicyCalled: func(policyType string, policyName string, body *bytes.Buffer) error {
+			require.Equal(t, elastic.PolicyTypeISM, policyType)
+			policies[policyName] = body.String()
+			return nil
+		},
+		PutIndexTemplateCalled: func(templateName string, body *bytes.Buffer) error {
+			templates[templateName] = body.String()
+			return nil
+		},
+	}
+
+	args := createMockArgsIndicesCreator(client)
+	args.PolicyType = elastic.PolicyTypeISM
+	ic, _ := NewIndicesCreator(args)
+	require.NoError(t, ic.CreateIndices())
+
+	require.JSONEq(t, `{"policy":{"ism_template":[{"index_patterns":["transactions-*"],"priority":100}]}}`, policies["rollover"])
+	require.JSONEq(t, `{
+		"index_patterns": ["transactions-*"],
+		"settings": {"number_of_shards": 5, "opendistro.index_state_management.rollover_alias": "transactions"}
+	}`, templates["transactions"])
+}
@@ -0,0 +1,25 @@
+package creator
+
+import "bytes"
+
+// DatabaseClientHandler defines the behaviour of the elastic client used to create the indices
+type DatabaseClientHandler interface {
+	DoesTemplateExist(index string) bool
+	PutIndexTemplate(templateName string, body *bytes.Buffer) error
+	DoesIndexExist(index string) bool
+	CreateIndexWithMapping(targetIndex string, body *bytes.Buffer) error
+	DoesAliasExist(alias string) bool
+	PutAlias(index string, alias string) error
+	DoesPolicyExist(policyType string, policyName string) bool
+	PutPolicy(policyType string, policyName string, body *bytes.Buffer) error
+	GetTemplate(name string) ([]byte, bool, error)
+	GetPolicy(policyType string, policyName string) ([]byte, bool, error)
+	GetAliasIndices(alias string) ([]string, error)
+	GetTemplatesNames(pattern string) ([]string, error)
+	GetIndicesNames(pattern string) ([]string, error)
+	GetIndexSettings(index string) ([]byte, error)
+	PutIndexSettings(index string, body *bytes.Buffer) error
+	DeleteTemplate(name string) error
+	DeleteIndex(index string) error
+	IsInterfaceNil() bool
+}
@@ -0,0 +1,152 @@
+package creator
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	"github.com/multiversx/mx-chain-tools-go/elasticreindexer/elastic"
+)
+
+const (
+	ilmPolicySetting        = "index.lifecycle.name"
+	ilmRolloverAliasSetting = "index.lifecycle.rollover_alias"
+	// OpenSearch reads this legacy Open Distro setting as the fallback of plugins.index_state_management.rollover_alias
+	ismRolloverAliasSetting = "opendistro.index_state_management.rollover_alias"
+	ismTemplatePriority     = 100
+)
+
+// attachPolicy returns a copy of the template with the settings needed for the indices matching it to be rolled over
+// behind the index alias and, for ILM, to be managed by the provided policy. The ISM policies select the indices they
+// manage through their ism_template, see attachISMTemplate
+func attachPolicy(template *bytes.Buffer, index string, policyType string, policyName string) (*bytes.Buffer, error) {
+	lifecycleSettings, err := getLifecycleSettings(policyType, policyName, index)
+	if err != nil {
+		return nil, err
+	}
+
+	fields := make(map[string]json.RawMessage)
+	err = json.Unmarshal(template.Bytes(), &fields)
+	if err != nil {
+		return nil, fmt.Errorf("%w while decoding the template of index %s", err, index)
+	}
+
+	settings := make(map[string]interface{})
+	rawSettings, found := fields["settings"]
+	if found {
+		err = json.Unmarshal(rawSettings, &settings)
+		if err != nil {
+			return nil, fmt.Errorf("%w while decoding the settings of index %s", err, index)
+		}
+	}
+
+	for setting, value := range lifecycleSettings {
+		settings[setting] = value
+	}
+
+	fields["settings"], err = json.Marshal(settings)
+	if err != nil {
+		return nil, err
+	}
+
+	templateBytes, err := json.Marshal(fields)
+	if err != nil {
+		return nil, err
+	}
+
+	return bytes.NewBuffer(templateBytes), nil
+}
+
+func getLifecycleSettings(policyType string, policyName string, index string) (map[string]string, error) {
+	switch policyType {
+	case elastic.PolicyTypeILM:
+		return map[string]string{
+			ilmPolicySetting:        policyName,
+			ilmRolloverAliasSetting: index,
+		}, nil
+	case elastic.PolicyTypeISM:
+		return map[string]string{
+			ismRolloverAliasSetting: index,
+		}, nil
+	default:
+		return nil, fmt.Errorf("%w %s", errUnknownPolicyType, policyType)
+	}
+}
+
+// attachISMTemplate returns a copy of the ISM policy with an ism_template matching the concrete indices of the provided
+// indices, so every index created behind their aliases (the first one and the rolled over ones) is managed by the policy.
+// The ism_template entries already defined in the policy are kept
+func attachISMTemplate(policy *bytes.Buffer, policyName string, indices []string) (*bytes.Buffer, error) {
+	fields := make(map[string]json.RawMessage)
+	err := json.Unmarshal(policy.Bytes(), &fields)
+	if err != nil {
+		return nil, fmt.Errorf("%w while decoding the policy %s", err, policyName)
+	}
+
+	policyFields := make(map[string]json.RawMessage)
+	rawPolicy, found := fields["policy"]
+	if found {
+		err = json.Unmarshal(rawPolicy, &policyFields)
+		if err != nil {
+			return nil, fmt.Errorf("%w while decoding the policy %s", err, policyName)
+		}
+	}
+
+	ismTemplates := make([]interface{}, 0, 1)
+	rawISMTemplates, found := policyFields["ism_template"]
+	if found {
+		ismTemplates, err = decodeISMTemplates(rawISMTemplates)
+		if err != nil {
+			return nil, fmt.Errorf("%w while decoding the ism_template of policy %s", err, policyName)
+		}
+	}
+
+	indexPatterns := make([]string, 0, len(indices))
+	for _, index := range indices {
+		indexPatterns = append(indexPatterns, index+"-*")
+	}
+	ismTemplates = append(ismTemplates, map[string]interface{}{
+		"index_patterns": indexPatterns,
+		"priority":       ismTemplatePriority,
+	})
+
+	policyFields["ism_template"], err = json.Marshal(ismTemplates)
+	if err != nil {
+		return nil, err
+	}
+
+	fields["policy"], err = json.Marshal(policyFields)
+	if err != nil {
+		return nil, err
+	}
+
+	policyBytes, err := json.Marshal(fields)
+	if err != nil {
+		return nil, err
+	}
+
+	return bytes.NewBuffer(policyBytes), nil
+}
+
+// decodeISMTemplates decodes the ism_template of a policy, which can be a single object or a list of objects
+func decodeISMTemplates(rawISMTemplates json.RawMessage) ([]interface{}, error) {
+	var ismTemplates interface{}
+	err := json.Unmarshal(rawISMTemplates, &ismTemplates)
+	if err != nil {
+		return nil, err
+	}
+
+	switch typedTemplates := ismTemplates.(type) {
+	case []interface{}:
+		return typedTemplates, nil
+	case nil:
+		return make([]interface{}, 0, 1), nil
+	default:
+		return []interface{}{typedTemplates}, nil
+	}
+}
+
+// createWriteAliasBody returns the body used to bootstrap the first index of a rollover-ready alias
+func createWriteAliasBody(alias string) *bytes.Buffer {
+	return bytes.NewBufferString(fmt.Sprintf(`{"aliases":{"%s":{"is_write_index":true}}}`, alias))
+}
@@ -0,0 +1,182 @@
+package mock
+
+import "bytes"
+
+// DatabaseClientStub -
+type DatabaseClientStub struct {
+	DoesTemplateExistCalled      func(index string) bool
+	PutIndexTemplateCalled       func(templateName string, body *bytes.Buffer) error
+	DoesIndexExistCalled         func(index string) bool
+	CreateIndexWithMappingCalled func(targetIndex string, body *bytes.Buffer) error
+	DoesAliasExistCalled         func(alias string) bool
+	PutAliasCalled               func(index string, alias string) error
+	DoesPolicyExistCalled        func(policyType string, policyName string) bool
+	PutPolicyCalled              func(policyType string, policyName string, body *bytes.Buffer) error
+	GetTemplateCalled            func(name string) ([]byte, bool, error)
+	GetPolicyCalled              func(policyType string, policyName string) ([]byte, bool, error)
+	GetAliasIndicesCalled        func(alias string) ([]string, error)
+	GetTemplatesNamesCalled      func(pattern string) ([]string, error)
+	GetIndicesNamesCalled        func(pattern string) ([]string, error)
+	GetIndexSettingsCalled       func(index string) ([]byte, error)
+	PutIndexSettingsCalled       func(index string, body *bytes.Buffer) error
+	DeleteTemplateCalled         func(name string) error
+	DeleteIndexCalled            func(index string) error
+}
+
+// DoesTemplateExist -
+func (stub *DatabaseClientStub) DoesTemplateExist(index string) bool {
+	if stub.DoesTemplateExistCalled != nil {
+		return stub.DoesTemplateExistCalled(index)
+	}
+
+	return false
+}
+
+// PutIndexTemplate -
+func (stub *DatabaseClientStub) PutIndexTemplate(templateName string, body *bytes.Buffer) error {
+	if stub.PutIndexTemplateCalled != nil {
+		return stub.PutIndexTemplateCalled(templateName, body)
+	}
+
+	return nil
+}
+
+// DoesIndexExist -
+func (stub *DatabaseClientStub) DoesIndexExist(index string) bool {
+	if stub.DoesIndexExistCalled != nil {
+		return stub.DoesIndexExistCalled(index)
+	}
+
+	return false
+}
+
+// CreateIndexWithMapping -
+func (stub *DatabaseClientStub) CreateIndexWithMapping(targetIndex string, body *bytes.Buffer) error {
+	if stub.CreateIndexWithMappingCalled != nil {
+		return stub.CreateIndexWithMappingCalled(targetIndex, body)
+	}
+
+	return nil
+}
+
+// DoesAliasExist -
+func (stub *DatabaseClientStub) DoesAliasExist(alias string) bool {
+	if stub.DoesAliasExistCalled != nil {
+		return stub.DoesAliasExistCalled(alias)
+	}
+
+	return false
+}
+
+// PutAlias -
+func (stub *DatabaseClientStub) PutAlias(index string, alias string) error {
+	if stub.PutAliasCalled != nil {
+		return stub.PutAliasCalled(index, alias)
+	}
+
+	return nil
+}
+
+// DoesPolicyExist -
+func (stub *DatabaseClientStub) DoesPolicyExist(policyType string, policyName string) bool {
+	if stub.DoesPolicyExistCalled != nil {
+		return stub.DoesPolicyExistCalled(policyType, policyName)
+	}
+
+	return false
+}
+
+// PutPolicy -
+func (stub *DatabaseClientStub) PutPolicy(policyType string, policyName string, body *bytes.Buffer) error {
+	if stub.PutPolicyCalled != nil {
+		return stub.PutPolicyCalled(policyType, policyName, body)
+	}
+
+	return nil
+}
+
+// GetTemplate -
+func (stub *DatabaseClientStub) GetTemplate(name string) ([]byte, bool, error) {
+	if stub.GetTemplateCalled != nil {
+		return stub.GetTemplateCalled(name)
+	}
+
+	return nil, false, nil
+}
+
+// GetPolicy -
+func (stub *DatabaseClientStub) GetPolicy(policyType string, policyName string) ([]byte, bool, error) {
+	if stub.GetPolicyCalled != nil {
+		return stub.GetPolicyCalled(policyType, policyName)
+	}
+
+	return nil, false, nil
+}
+
+// GetAliasIndices -
+func (stub *DatabaseClientStub) GetAliasIndices(alias string) ([]string, error) {
+	if stub.GetAliasIndicesCalled != nil {
+		return stub.GetAliasIndicesCalled(alias)
+	}
+
+	return nil, nil
+}
+
+// GetTemplatesNames -
+func (stub *DatabaseClientStub) GetTemplatesNames(pattern string) ([]string, error) {
+	if stub.GetTemplatesNamesCalled != nil {
+		return stub.GetTemplatesNamesCalled(pattern)
+	}
+
+	return nil, nil
+}
+
+// GetIndicesNames -
+func (stub *DatabaseClientStub) GetIndicesNames(pattern string) ([]string, error) {
+	if stub.GetIndicesNamesCalled != nil {
+		return stub.GetIndicesNamesCalled(pattern)
+	}
+
+	return nil, nil
+}
+
+// GetIndexSettings -
+func (stub *DatabaseClientStub) GetIndexSettings(index string) ([]byte, error) {
+	if stub.GetIndexSettingsCalled != nil {
+		return stub.GetIndexSettingsCalled(index)
+	}
+
+	return []byte("{}"), nil
+}
+
+// PutIndexSettings -
+func (stub *DatabaseClientStub) PutIndexSettings(index string, body *bytes.Buffer) error {
+	if stub.PutIndexSettingsCalled != nil {
+		return stub.PutIndexSettingsCalled(index, body)
+	}
+
+	return nil
+}
+
+// DeleteTemplate -
+func (stub *DatabaseClientStub) DeleteTemplate(name string) error {
+	if stub.DeleteTemplateCalled != nil {
+		return stub.DeleteTemplateCalled(name)
+	}
+
+	return nil
+}
+
+// DeleteIndex -
+func (stub *DatabaseClientStub) DeleteIndex(index string) error {
+	if stub.DeleteIndexCalled != nil {
+		return stub.DeleteIndexCalled(index)
+	}
+
+	return nil
+}
+
+// IsInterfaceNil -
+func (stub *DatabaseClientStub) IsInterfaceNil() bool {
+	return stub == nil
+}
@@ -0,0 +1,465 @@
+package creator
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+const (
+	// ActionCreate is used for the resources missing from the cluster
+	ActionCreate = "create"
+	// ActionUpdate is used for the resources that differ from the desired ones and can be updated
+	ActionUpdate = "update"
+	// ActionDelete is used for the resources removed from the cluster
+	ActionDelete = "delete"
+	// ActionManual is used for the differences that cannot be applied, like the static settings of an existing index
+	ActionManual = "manual"
+)
+
+const (
+	resourcePolicy   = "policy"
+	resourceTemplate = "template"
+	resourceIndex    = "index"
+	resourceAlias    = "alias"
+	resourceSettings = "settings"
+)
+
+// Change is a difference between the desired index set and the cluster, together with the operation that fixes it
+type Change struct {
+	Action   string
+	Resource string
+	Name     string
+	Details  []string
+	apply    func() error
+}
+
+// String returns a human-readable representation of the change
+func (c *Change) String() string {
+	if len(c.Details) == 0 {
+		return fmt.Sprintf("%s %s %s", c.Action, c.Resource, c.Name)
+	}
+
+	return fmt.Sprintf("%s %s %s: %s", c.Action, c.Resource, c.Name, strings.Join(c.Details, "; "))
+}
+
+// Plan compares the desired policies, templates, indices, aliases and index settings with the ones from the cluster
+// and returns the changes needed for the cluster to match the configuration
+func (ic *indicesCreator) Plan() ([]*Change, error) {
+	changes, err := ic.planPolicies()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, index := range ic.sortedIndices() {
+		indexChanges, err := ic.planIndex(index)
+		if err != nil {
+			return nil, err
+		}
+
+		changes = append(changes, indexChanges...)
+	}
+
+	return changes, nil
+}
+
+// Apply executes the provided changes, in order. The manual changes are only logged
+func (ic *indicesCreator) Apply(changes []*Change) error {
+	for _, change := range changes {
+		if change.apply == nil {
+			log.Warn("change has to be done manually", "change", change.String())
+			continue
+		}
+
+		err := change.apply()
+		if err != nil {
+			return fmt.Errorf("%w while applying change: %s", err, change.String())
+		}
+
+		log.Info("change applied", "change", change.String())
+	}
+
+	return nil
+}
+
+// PlanDestroy returns the changes that delete the templates and the concrete indices (together with their aliases) of
+// the index set. If a prefix is provided, all the templates and indices with that prefix are deleted, otherwise the
+// ones of the configured indices
+func (ic *indicesCreator) PlanDestroy(prefix string) ([]*Change, error) {
+	templates := make([]string, 0)
+	indices := make([]string, 0)
+
+	if prefix != "" {
+		var err error
+		templates, err = ic.databaseClient.GetTemplatesNames(prefix + "*")
+		if err != nil {
+			return nil, err
+		}
+
+		indices, err = ic.databaseClient.GetIndicesNames(prefix + "*")
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		for _, index := range ic.sortedIndices() {
+			if ic.databaseClient.DoesTemplateExist(index) {
+				templates = append(templates, index)
+			}
+
+			concreteIndices, err := ic.databaseClient.GetIndicesNames(index + "-*")
+			if err != nil {
+				return nil, err
+			}
+
+			indices = append(indices, concreteIndices...)
+		}
+	}
+
+	changes := make([]*Change, 0, len(templates)+len(indices))
+	for _, index := range indices {
+		indexName := index
+		changes = append(changes, &Change{
+			Action:   ActionDelete,
+			Resource: resourceIndex,
+			Name:     indexName,
+			apply: func() error {
+				return ic.databaseClient.DeleteIndex(indexName)
+			},
+		})
+	}
+	for _, template := range templates {
+		templateName := template
+		changes = append(changes, &Change{
+			Action:   ActionDelete,
+			Resource: resourceTemplate,
+			Name:     templateName,
+			apply: func() error {
+				return ic.databaseClient.DeleteTemplate(templateName)
+			},
+		})
+	}
+
+	return changes, nil
+}
+
+func (ic *indicesCreator) planPolicies() ([]*Change, error) {
+	policiesNames := make([]string, 0, len(ic.policies))
+	for policyName := range ic.policies {
+		if ic.isPolicyUsed(policyName) {
+			policiesNames = append(policiesNames, policyName)
+		}
+	}
+	sort.Strings(policiesNames)
+
+	changes := make([]*Change, 0)
+	for _, policyName := range policiesNames {
+		change, err := ic.planPolicy(policyName)
+		if err != nil {
+			return nil, err
+		}
+		if change != nil {
+			changes = append(changes, change)
+		}
+	}
+
+	return changes, nil
+}
+
+func (ic *indicesCreator) planPolicy(policyName string) (*Change, error) {
+	desiredPolicy, err := ic.getDesiredPolicy(policyName)
+	if err != nil {
+		return nil, err
+	}
+
+	putPolicy := func() error {
+		return ic.databaseClient.PutPolicy(ic.policyType, policyName, bytes.NewBuffer(desiredPolicy.Bytes()))
+	}
+
+	actualPolicy, found, err := ic.databaseClient.GetPolicy(ic.policyType, policyName)
+	if err != nil {
+		return nil, fmt.Errorf("%w while getting the policy %s", err, policyName)
+	}
+	if !found {
+		return &Change{
+			Action:   ActionCreate,
+			Resource: resourcePolicy,
+			Name:     policyName,
+			Details:  []string{ic.policyType},
+			apply:    putPolicy,
+		}, nil
+	}
+
+	differences, err := comparePolicies(desiredPolicy.Bytes(), actualPolicy)
+	if err != nil {
+		return nil, fmt.Errorf("%w for policy %s", err, policyName)
+	}
+	if len(differences) == 0 {
+		return nil, nil
+	}
+
+	return &Change{
+		Action:   ActionUpdate,
+		Resource: resourcePolicy,
+		Name:     policyName,
+		Details:  differences,
+		apply:    putPolicy,
+	}, nil
+}
+
+func (ic *indicesCreator) getDesiredTemplate(index string) (*bytes.Buffer, error) {
+	template := ic.templates[index]
+	policyName, hasPolicy := ic.indicesPolicies[index]
+	if !hasPolicy {
+		return template, nil
+	}
+
+	return attachPolicy(template, index, ic.policyType, policyName)
+}
+
+func (ic *indicesCreator) planIndex(index string) ([]*Change, error) {
+	changes := make([]*Change, 0)
+
+	templateChange, err := ic.planTemplate(index)
+	if err != nil {
+		return nil, err
+	}
+	if templateChange != nil {
+		changes = append(changes, templateChange)
+	}
+
+	_, hasPolicy := ic.indicesPolicies[index]
+	indexWithSuffix := index + firstIndexSuffix
+	aliasIndices, err := ic.databaseClient.GetAliasIndices(index)
+	if err != nil {
+		return nil, fmt.Errorf("%w while getting the indices of alias %s", err, index)
+	}
+	if len(aliasIndices) > 0 {
+		unexpectedIndices := getUnexpectedAliasIndices(index, aliasIndices)
+		if len(unexpectedIndices) > 0 {
+			// the settings are not compared, as the alias resolves to other indices
+			return append(changes, &Change{
+				Action:   ActionManual,
+				Resource: resourceAlias,
+				Name:     index,
+				Details: []string{fmt.Sprintf("points to %s instead of the %s-NNNNNN indices",
+					strings.Join(unexpectedIndices, ", "), index)},
+			}), nil
+		}
+
+		settingsChanges, errSettings := ic.planSettings(index)
+		if errSettings != nil {
+			return nil, errSettings
+		}
+
+		return append(changes, settingsChanges...), nil
+	}
+
+	if !ic.databaseClient.DoesIndexExist(indexWithSuffix) {
+		changes = append(changes, &Change{
+			Action:   ActionCreate,
+			Resource: resourceIndex,
+			Name:     indexWithSuffix,
+			apply: func() error {
+				var body *bytes.Buffer
+				if hasPolicy {
+					body = createWriteAliasBody(index)
+				}
+
+				return ic.databaseClient.CreateIndexWithMapping(indexWithSuffix, body)
+			},
+		})
+		if hasPolicy {
+			// the write alias is created together with the index
+			return changes, nil
+		}
+	}
+
+	changes = append(changes, &Change{
+		Action:   ActionCreate,
+		Resource: resourceAlias,
+		Name:     index,
+		Details:  []string{"on " + indexWithSuffix},
+		apply: func() error {
+			return ic.databaseClient.PutAlias(indexWithSuffix, index)
+		},
+	})
+
+	return changes, nil
+}
+
+// getUnexpectedAliasIndices returns the indices of the alias that are not one of its <alias>-NNNNNN indices (the first
+// index or the rolled over ones)
+func getUnexpectedAliasIndices(alias string, aliasIndices []string) []string {
+	unexpectedIndices := make([]string, 0)
+	for _, aliasIndex := range aliasIndices {
+		suffix := strings.TrimPrefix(aliasIndex, alias+"-")
+		if suffix != aliasIndex && len(suffix) == len(firstIndexSuffix)-1 && isNumeric(suffix) {
+			continue
+		}
+
+		unexpectedIndices = append(unexpectedIndices, aliasIndex)
+	}
+
+	return unexpectedIndices
+}
+
+func isNumeric(value string) bool {
+	for _, character := range value {
+		if character < '0' || character > '9' {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (ic *indicesCreator) planTemplate(index string) (*Change, error) {
+	desiredTemplate, err := ic.getDesiredTemplate(index)
+	if err != nil {
+		return nil, err
+	}
+
+	putTemplate := func() error {
+		return ic.databaseClient.PutIndexTemplate(index, bytes.NewBuffer(desiredTemplate.Bytes()))
+	}
+
+	actualTemplate, found, err := ic.databaseClient.GetTemplate(index)
+	if err != nil {
+		return nil, fmt.Errorf("%w while getting the template of index %s", err, index)
+	}
+	if !found {
+		return &Change{
+			Action:   ActionCreate,
+			Resource: resourceTemplate,
+			Name:     index,
+			apply:    putTemplate,
+		}, nil
+	}
+
+	differences, err := compareTemplates(desiredTemplate.Bytes(), actualTemplate)
+	if err != nil {
+		return nil, fmt.Errorf("%w for index %s", err, index)
+	}
+	if len(differences) == 0 {
+		return nil, nil
+	}
+
+	return &Change{
+		Action:   ActionUpdate,
+		Resource: resourceTemplate,
+		Name:     index,
+		Details:  differences,
+		apply:    putTemplate,
+	}, nil
+}
+
+// planSettings compares the settings of the desired template with the settings of the concrete indices behind the alias
+func (ic *indicesCreator) planSettings(index string) ([]*Change, error) {
+	desiredTemplate, err := ic.getDesiredTemplate(index)
+	if err != nil {
+		return nil, err
+	}
+
+	template := struct {
+		Settings interface{} `json:"settings"`
+	}{}
+	err = json.Unmarshal(desiredTemplate.Bytes(), &template)
+	if err != nil {
+		return nil, fmt.Errorf("%w while decoding the template of index %s", err, index)
+	}
+	desiredSettings := flattenSettings(template.Settings)
+
+	settingsBytes, err := ic.databaseClient.GetIndexSettings(index)
+	if err != nil {
+		return nil, fmt.Errorf("%w while getting the settings of index %s", err, index)
+	}
+
+	indicesSettings := make(map[string]struct {
+		Settings map[string]interface{} `json:"settings"`
+	})
+	err = json.Unmarshal(settingsBytes, &indicesSettings)
+	if err != nil {
+		return nil, fmt.Errorf("%w while decoding the settings of index %s", err, index)
+	}
+
+	concreteIndices := make([]string, 0, len(indicesSettings))
+	for concreteIndex := range indicesSettings {
+		concreteIndices = append(concreteIndices, concreteIndex)
+	}
+	sort.Strings(concreteIndices)
+
+	changes := make([]*Change, 0)
+	for _, concreteIndex := range concreteIndices {
+		actualSettings := flattenSettings(indicesSettings[concreteIndex].Settings)
+		changes = append(changes, createSettingsChanges(ic.databaseClient, concreteIndex, desiredSettings, actualSettings)...)
+	}
+
+	return changes, nil
+}
+
+func createSettingsChanges(
+	databaseClient DatabaseClientHandler,
+	concreteIndex string,
+	desiredSettings map[string]string,
+	actualSettings map[string]string,
+) []*Change {
+	keys := make([]string, 0, len(desiredSettings))
+	for key := range desiredSettings {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	dynamicDetails := make([]string, 0)
+	staticDetails := make([]string, 0)
+	updatedSettings := make(map[string]string)
+	for _, key := range keys {
+		desiredValue := desiredSettings[key]
+		actualValue, found := actualSettings[key]
+		if found && actualValue == desiredValue {
+			continue
+		}
+		if !found {
+			actualValue = "missing"
+		}
+
+		detail := fmt.Sprintf("%s: %s -> %s", key, actualValue, desiredValue)
+		_, isDynamic := dynamicSettings[key]
+		if !isDynamic {
+			staticDetails = append(staticDetails, detail+" (static setting, the index has to be recreated)")
+			continue
+		}
+
+		dynamicDetails = append(dynamicDetails, detail)
+		updatedSettings[indexSettingsPrefix+key] = desiredValue
+	}
+
+	changes := make([]*Change, 0, 2)
+	if len(dynamicDetails) > 0 {
+		changes = append(changes, &Change{
+			Action:   ActionUpdate,
+			Resource: resourceSettings,
+			Name:     concreteIndex,
+			Details:  dynamicDetails,
+			apply: func() error {
+				body, err := json.Marshal(updatedSettings)
+				if err != nil {
+					return err
+				}
+
+				return databaseClient.PutIndexSettings(concreteIndex, bytes.NewBuffer(body))
+			},
+		})
+	}
+	if len(staticDetails) > 0 {
+		changes = append(changes, &Change{
+			Action:   ActionManual,
+			Resource: resourceSettings,
+			Name:     concreteIndex,
+			Details:  staticDetails,
+		})
+	}
+
+	return changes
+}
@@ -0,0 +1,263 @@
+package creator
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/multiversx/mx-chain-tools-go/elasticreindexer/creator/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompareTemplates(t *testing.T) {
+	desired := []byte(`{
+		"index_patterns": ["blocks-*"],
+		"settings": {"number_of_shards": 3, "index": {"sort.field": ["timestamp"]}},
+		"mappings": {"properties": {"nonce": {"type": "long"}, "hash": {"type": "keyword", "index": "false"}}}
+	}`)
+
+	t.Run("same template with cluster formatting should not differ", func(t *testing.T) {
+		actual := []byte(`{
+			"order": 0,
+			"index_patterns": ["blocks-*"],
+			"settings": {"index.number_of_shards": "3", "index.sort.field": ["timestamp"]},
+			"mappings": {"properties": {"hash": {"index": false, "type": "keyword"}, "nonce": {"type": "long"}}},
+			"aliases": {}
+		}`)
+
+		differences, err := compareTemplates(desired, actual)
+		require.NoError(t, err)
+		require.Empty(t, differences)
+	})
+	t.Run("drifted template should differ", func(t *testing.T) {
+		actual := []byte(`{
+			"index_patterns": ["blocks-*"],
+			"settings": {"index.number_of_shards": "1", "index.sort.field": ["timestamp"]},
+			"mappings": {"properties": {"nonce": {"type": "keyword"}, "extra": {"type": "long"}}}
+		}`)
+
+		differences, err := compareTemplates(desired, actual)
+		require.NoError(t, err)
+		require.Equal(t, []string{
+			"mappings.properties.extra.type: long -> removed",
+			"mappings.properties.hash.index: missing -> false",
+			"mappings.properties.hash.type: missing -> keyword",
+			"mappings.properties.nonce.type: keyword -> long",
+			"settings.number_of_shards: 1 -> 3",
+		}, differences)
+	})
+}
+
+func TestIndicesCreator_Plan(t *testing.T) {
+	args := createMockArgsIndicesCreator(nil)
+	client := &mock.DatabaseClientStub{
+		GetPolicyCalled: func(_ string, _ string) ([]byte, bool, error) {
+			return []byte(`{"version":1,"policy":{}}`), true, nil
+		},
+		GetTemplateCalled: func(name string) ([]byte, bool, error) {
+			if name == "blocks" {
+				return []byte(`{"index_patterns":["blocks-*"]}`), true, nil
+			}
+
+			return []byte(`{"index_patterns":["transactions-*"],"settings":{"index.number_of_shards":"5"}}`), true, nil
+		},
+		GetAliasIndicesCalled: func(alias string) ([]string, error) {
+			if alias == "transactions" {
+				return []string{"transactions-000001", "transactions-000002"}, nil
+			}
+
+			return nil, nil
+		},
+		DoesIndexExistCalled: func(index string) bool {
+			return false
+		},
+		GetIndexSettingsCalled: func(index string) ([]byte, error) {
+			require.Equal(t, "transactions", index)
+			return []byte(`{"transactions-000002":{"settings":{
+				"index.number_of_shards":"3",
+				"index.lifecycle.name":"old",
+				"index.lifecycle.rollover_alias":"transactions"
+			}}}`), nil
+		},
+	}
+	args.DatabaseClient = client
+	ic, _ := NewIndicesCreator(args)
+
+	changes, err := ic.Plan()
+	require.NoError(t, err)
+
+	descriptions := make([]string, 0, len(changes))
+	for _, change := range changes {
+		descriptions = append(descriptions, change.String())
+	}
+	require.Equal(t, []string{
+		"create index blocks-000001",
+		"create alias blocks: on blocks-000001",
+		"update template transactions: settings.lifecycle.name: missing -> rollover; settings.lifecycle.rollover_alias: missing -> transactions",
+		"update settings transactions-000002: lifecycle.name: old -> rollover",
+		"manual settings transactions-000002: number_of_shards: 3 -> 5 (static setting, the index has to be recreated)",
+	}, descriptions)
+
+	updatedSettings := ""
+	client.PutIndexSettingsCalled = func(index string, body *bytes.Buffer) error {
+		require.Equal(t, "transactions-000002", index)
+		updatedSettings = body.String()
+		return nil
+	}
+	require.NoError(t, ic.Apply(changes[3:]))
+	require.JSONEq(t, `{"index.lifecycle.name":"rollover"}`, updatedSettings)
+}
+
+func TestComparePolicies(t *testing.T) {
+	desired := []byte(`{"policy": {
+		"default_state": "hot",
+		"states": [{"name": "hot", "actions": [{"rollover": {"min_size": "50gb"}}], "transitions": []}],
+		"ism_template": [{"index_patterns": ["logs-*"], "priority": 100}]
+	}}`)
+
+	t.Run("same policy with the fields set by the cluster should not differ", func(t *testing.T) {
+		actual := []byte(`{"_id": "rollover", "_seq_no": 3, "_primary_term": 1, "policy": {
+			"policy_id": "rollover",
+			"last_updated_time": 1700000000000,
+			"schema_version": 17,
+			"default_state": "hot",
+			"states": [{"name": "hot", "actions": [{"retry": {"count": 3}, "rollover": {"min_size": "50gb"}}], "transitions": []}],
+			"ism_template": [{"index_patterns": ["logs-*"], "priority": 100, "last_updated_time": 1700000000000}]
+		}}`)
+
+		differences, err := comparePolicies(desired, actual)
+		require.NoError(t, err)
+		require.Empty(t, differences)
+	})
+	t.Run("drifted policy should differ", func(t *testing.T) {
+		actual := []byte(`{"policy": {
+			"default_state": "hot",
+			"states": [
+				{"name": "hot", "actions": [{"rollover": {"min_size": "30gb"}}], "transitions": []},
+				{"name": "delete", "actions": [{"delete": {}}]}
+			]
+		}}`)
+
+		differences, err := comparePolicies(desired, actual)
+		require.NoError(t, err)
+		require.Equal(t, []string{
+			"ism_template.#: missing -> 1",
+			"ism_template.0.index_patterns.#: missing -> 1",
+			"ism_template.0.index_patterns.0: missing -> logs-*",
+			"ism_template.0.priority: missing -> 100",
+			"states.#: 2 -> 1",
+			"states.0.actions.0.rollover.min_size: 30gb -> 50gb",
+		}, differences)
+	})
+}
+
+func TestIndicesCreator_PlanShouldCheckThePoliciesAndTheAliasesTargets(t *testing.T) {
+	args := createMockArgsIndicesCreator(nil)
+	args.Policies["rollover"] = bytes.NewBufferString(`{"policy":{"phases":{"hot":{"actions":{"rollover":{"max_size":"50gb"}}}}}}`)
+	putPolicy := ""
+	client := &mock.DatabaseClientStub{
+		GetPolicyCalled: func(policyType string, policyName string) ([]byte, bool, error) {
+			require.Equal(t, "rollover", policyName)
+			return []byte(`{"version":2,"policy":{"phases":{"hot":{"min_age":"0ms","actions":{"rollover":{"max_size":"30gb"}}}}}}`), true, nil
+		},
+		PutPolicyCalled: func(_ string, _ string, body *bytes.Buffer) error {
+			putPolicy = body.String()
+			return nil
+		},
+		GetTemplateCalled: func(name string) ([]byte, bool, error) {
+			return nil, false, nil
+		},
+		GetAliasIndicesCalled: func(alias string) ([]string, error) {
+			if alias == "blocks" {
+				return []string{"blocks-000001", "blocks-old"}, nil
+			}
+
+			return []string{"transactions-000002"}, nil
+		},
+		GetIndexSettingsCalled: func(index string) ([]byte, error) {
+			require.Equal(t, "transactions", index)
+			return []byte(`{"transactions-000002":{"settings":{
+				"index.lifecycle.name":"rollover",
+				"index.lifecycle.rollover_alias":"transactions",
+				"index.number_of_shards":"5"
+			}}}`), nil
+		},
+	}
+	args.DatabaseClient = client
+	ic, _ := NewIndicesCreator(args)
+
+	changes, err := ic.Plan()
+	require.NoError(t, err)
+
+	descriptions := make([]string, 0, len(changes))
+	for _, change := range changes {
+		descriptions = append(descriptions, change.String())
+	}
+	require.Equal(t, []string{
+		"update policy rollover: phases.hot.actions.rollover.max_size: 30gb -> 50gb",
+		"create template blocks",
+		"manual alias blocks: points to blocks-old instead of the blocks-NNNNNN indices",
+		"create template transactions",
+	}, descriptions)
+
+	require.NoError(t, ic.Apply(changes[:1]))
+	require.JSONEq(t, `{"policy":{"phases":{"hot":{"actions":{"rollover":{"max_size":"50gb"}}}}}}`, putPolicy)
+}
+
+func TestIndicesCreator_PlanDestroy(t *testing.T) {
+	t.Run("with prefix", func(t *testing.T) {
+		deleted := make([]string, 0)
+		client := &mock.DatabaseClientStub{
+			GetTemplatesNamesCalled: func(pattern string) ([]string, error) {
+				require.Equal(t, "staging-*", pattern)
+				return []string{"staging-blocks"}, nil
+			},
+			GetIndicesNamesCalled: func(pattern string) ([]string, error) {
+				require.Equal(t, "staging-*", pattern)
+				return []string{"staging-blocks-000001"}, nil
+			},
+			DeleteIndexCalled: func(index string) error {
+				deleted = append(deleted, index)
+				return nil
+			},
+			DeleteTemplateCalled: func(name string) error {
+				deleted = append(deleted, name)
+				return nil
+			},
+		}
+		ic, _ := NewIndicesCreator(createMockArgsIndicesCreator(client))
+
+		changes, err := ic.PlanDestroy("staging-")
+		require.NoError(t, err)
+		require.Len(t, changes, 2)
+		require.NoError(t, ic.Apply(changes))
+		require.Equal(t, []string{"staging-blocks-000001", "staging-blocks"}, deleted)
+	})
+	t.Run("without prefix should only delete the configured indices", func(t *testing.T) {
+		client := &mock.DatabaseClientStub{
+			DoesTemplateExistCalled: func(index string) bool {
+				return index == "blocks"
+			},
+			GetIndicesNamesCalled: func(pattern string) ([]string, error) {
+				if pattern == "transactions-*" {
+					return []string{"transactions-000001", "transactions-000002"}, nil
+				}
+
+				return nil, nil
+			},
+		}
+		ic, _ := NewIndicesCreator(createMockArgsIndicesCreator(client))
+
+		changes, err := ic.PlanDestroy("")
+		require.NoError(t, err)
+
+		descriptions := make([]string, 0, len(changes))
+		for _, change := range changes {
+			descriptions = append(descriptions, change.String())
+		}
+		require.Equal(t, []string{
+			"delete index transactions-000001",
+			"delete index transactions-000002",
+			"delete template blocks",
+		}, descriptions)
+	})
+}
@@ -0,0 +1,82 @@
+package elastic
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+)
+
+type aliasAction struct {
+	Index string `json:"index"`
+	Alias string `json:"alias"`
+}
+
+// GetAliasIndices returns the names of the concrete indices the provided alias points to
+func (esc *esClient) GetAliasIndices(alias string) ([]string, error) {
+	res, err := esc.client.Indices.GetAlias(esc.client.Indices.GetAlias.WithName(alias))
+	if err != nil {
+		return nil, err
+	}
+	if res.StatusCode == http.StatusNotFound {
+		closeBody(res)
+		return make([]string, 0), nil
+	}
+
+	respBytes, err := getBytesFromResponse(res)
+	if err != nil {
+		return nil, err
+	}
+
+	indices := make(map[string]json.RawMessage)
+	err = json.Unmarshal(respBytes, &indices)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(indices))
+	for name := range indices {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names, nil
+}
+
+// AliasSwap holds the concrete indices an alias is moved from and the index it is moved to
+type AliasSwap struct {
+	Alias             string
+	RemoveFromIndices []string
+	AddToIndex        string
+}
+
+// SwapAliases moves all the provided aliases with a single _aliases request, which is applied atomically, so the
+// readers of the aliases see either all the old indices or all the new ones
+func (esc *esClient) SwapAliases(swaps []AliasSwap) error {
+	actions := make([]map[string]aliasAction, 0)
+	for _, swap := range swaps {
+		for _, index := range swap.RemoveFromIndices {
+			actions = append(actions, map[string]aliasAction{"remove": {Index: index, Alias: swap.Alias}})
+		}
+		actions = append(actions, map[string]aliasAction{"add": {Index: swap.AddToIndex, Alias: swap.Alias}})
+	}
+
+	body, err := json.Marshal(map[string]interface{}{"actions": actions})
+	if err != nil {
+		return err
+	}
+
+	res, err := esc.client.Indices.UpdateAliases(bytes.NewBuffer(body))
+	if err != nil {
+		return err
+	}
+
+	defer closeBody(res)
+
+	if res.IsError() {
+		return fmt.Errorf("%s", res.String())
+	}
+
+	return nil
+}
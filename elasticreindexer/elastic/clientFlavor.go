@@ -0,0 +1,173 @@
+package elastic
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/elastic/go-elasticsearch/v7"
+	"github.com/elastic/go-elasticsearch/v7/esapi"
+	"github.com/tidwall/gjson"
+)
+
+const (
+	// ClientTypeES7 is the client for the Elasticsearch 7 and Open Distro clusters, used when no type is configured
+	ClientTypeES7 = "es7"
+	// ClientTypeES8 is the client for the Elasticsearch 8 clusters
+	ClientTypeES8 = "es8"
+	// ClientTypeOpenSearch is the client for the OpenSearch clusters
+	ClientTypeOpenSearch = "opensearch"
+)
+
+const openDistroISMPoliciesPath = "/_opendistro/_ism/policies/"
+
+// clientFlavor holds the requests that are different between the supported cluster types. All the other requests are
+// understood by all of them, so they are sent in the same way by the esClient
+type clientFlavor interface {
+	wrapTransport(transport http.RoundTripper) http.RoundTripper
+	openPointInTime(client *elasticsearch.Client, index string) (string, error)
+	closePointInTime(client *elasticsearch.Client, pitID string) error
+	putTemplate(client *elasticsearch.Client, name string, body *bytes.Buffer) (*esapi.Response, error)
+	getTemplates(client *elasticsearch.Client, pattern string) (map[string]json.RawMessage, error)
+	existsTemplate(client *elasticsearch.Client, name string) (*esapi.Response, error)
+	deleteTemplate(client *elasticsearch.Client, name string) (*esapi.Response, error)
+	checkPolicyType(policyType string) error
+	ismPoliciesPath() string
+}
+
+func newClientFlavor(clientType string) (clientFlavor, error) {
+	switch clientType {
+	case "", ClientTypeES7:
+		return &es7Flavor{}, nil
+	case ClientTypeES8:
+		return &es8Flavor{}, nil
+	case ClientTypeOpenSearch:
+		return &openSearchFlavor{}, nil
+	default:
+		return nil, fmt.Errorf("unknown client type %s", clientType)
+	}
+}
+
+// es7Flavor sends the requests as understood by Elasticsearch 7: legacy index templates and the point in time API of
+// x-pack. The Open Distro clusters identify themselves as Elasticsearch 7.10, so both lifecycle policy types are allowed
+type es7Flavor struct{}
+
+func (flavor *es7Flavor) wrapTransport(transport http.RoundTripper) http.RoundTripper {
+	return transport
+}
+
+func (flavor *es7Flavor) openPointInTime(client *elasticsearch.Client, index string) (string, error) {
+	res, err := client.OpenPointInTime(
+		client.OpenPointInTime.WithIndex(index),
+		client.OpenPointInTime.WithKeepAlive(pointInTimeKeepAlive),
+		client.OpenPointInTime.WithContext(context.Background()),
+	)
+	if err != nil {
+		return "", err
+	}
+
+	bodyBytes, err := getBytesFromResponse(res)
+	if err != nil {
+		return "", err
+	}
+
+	return gjson.GetBytes(bodyBytes, "id").String(), nil
+}
+
+func (flavor *es7Flavor) closePointInTime(client *elasticsearch.Client, pitID string) error {
+	body := fmt.Sprintf(`{"id":"%s"}`, pitID)
+	res, err := client.ClosePointInTime(
+		client.ClosePointInTime.WithBody(strings.NewReader(body)),
+	)
+	if err != nil {
+		return err
+	}
+	defer closeBody(res)
+
+	if res.IsError() && res.StatusCode != http.StatusNotFound {
+		return fmt.Errorf("error response: %s", res)
+	}
+
+	return nil
+}
+
+func (flavor *es7Flavor) putTemplate(client *elasticsearch.Client, name string, body *bytes.Buffer) (*esapi.Response, error) {
+	return client.Indices.PutTemplate(name, body)
+}
+
+func (flavor *es7Flavor) getTemplates(client *elasticsearch.Client, pattern string) (map[string]json.RawMessage, error) {
+	res, err := client.Indices.GetTemplate(
+		client.Indices.GetTemplate.WithName(pattern),
+		client.Indices.GetTemplate.WithFlatSettings(true),
+	)
+	if err != nil {
+		return nil, err
+	}
+	if res.StatusCode == http.StatusNotFound {
+		closeBody(res)
+		return make(map[string]json.RawMessage), nil
+	}
+
+	respBytes, err := getBytesFromResponse(res)
+	if err != nil {
+		return nil, err
+	}
+
+	templates := make(map[string]json.RawMessage)
+	err = json.Unmarshal(respBytes, &templates)
+	if err != nil {
+		return nil, err
+	}
+
+	return templates, nil
+}
+
+func (flavor *es7Flavor) existsTemplate(client *elasticsearch.Client, name string) (*esapi.Response, error) {
+	return client.Indices.ExistsTemplate([]string{name})
+}
+
+func (flavor *es7Flavor) deleteTemplate(client *elasticsearch.Client, name string) (*esapi.Response, error) {
+	return client.Indices.DeleteTemplate(name)
+}
+
+func (flavor *es7Flavor) checkPolicyType(policyType string) error {
+	switch policyType {
+	case PolicyTypeILM, PolicyTypeISM:
+		return nil
+	default:
+		return fmt.Errorf("unknown policy type %s", policyType)
+	}
+}
+
+func (flavor *es7Flavor) ismPoliciesPath() string {
+	return openDistroISMPoliciesPath
+}
+
+// doRawRequest performs a request that is not covered by the elasticsearch client
+func doRawRequest(client *elasticsearch.Client, method string, path string, body *bytes.Buffer) (*esapi.Response, error) {
+	var req *http.Request
+	var err error
+	if body != nil {
+		req, err = http.NewRequest(method, path, body)
+	} else {
+		req, err = http.NewRequest(method, path, nil)
+	}
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	res, err := client.Perform(req)
+	if err != nil {
+		return nil, err
+	}
+
+	return &esapi.Response{
+		StatusCode: res.StatusCode,
+		Body:       res.Body,
+		Header:     res.Header,
+	}, nil
+}
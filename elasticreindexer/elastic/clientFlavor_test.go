@@ -0,0 +1,112 @@
+package elastic
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/multiversx/mx-chain-tools-go/elasticreindexer/config"
+	"github.com/stretchr/testify/require"
+	"github.com/tidwall/gjson"
+)
+
+func TestNewElasticClient_UnknownTypeShouldErr(t *testing.T) {
+	esc, err := NewElasticClient(config.ElasticInstanceConfig{
+		Type: "solr",
+		URL:  "http://localhost:9200",
+	})
+	require.Nil(t, esc)
+	require.EqualError(t, err, "unknown client type solr")
+}
+
+func TestNewElasticClient_ES8ShouldSendCompatibilityHeaders(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, compatibleWithV7MediaType, r.Header.Get(headerAccept))
+		require.Equal(t, compatibleWithV7MediaType, r.Header.Get(headerContentType))
+		_, _ = w.Write([]byte(`{"count":7}`))
+	}))
+	defer server.Close()
+
+	esc, err := NewElasticClient(config.ElasticInstanceConfig{
+		Type: ClientTypeES8,
+		URL:  server.URL,
+	})
+	require.NoError(t, err)
+
+	count, err := esc.GetCountWithBody("blocks", []byte(`{"query":{"match_all":{}}}`))
+	require.NoError(t, err)
+	require.Equal(t, uint64(7), count)
+}
+
+func TestComposableTemplatesConversion(t *testing.T) {
+	legacyTemplate := `{"index_patterns":["blocks-*"],"order":2,"settings":{"index.number_of_shards":"3"},"mappings":{"properties":{"nonce":{"type":"long"}}}}`
+
+	composableTemplate, err := toComposableTemplate([]byte(legacyTemplate))
+	require.NoError(t, err)
+	require.JSONEq(t, `{"index_patterns":["blocks-*"],"priority":2,"template":{"settings":{"index.number_of_shards":"3"},"mappings":{"properties":{"nonce":{"type":"long"}}}}}`, string(composableTemplate))
+
+	response := `{"index_templates":[{"name":"blocks","index_template":` + string(composableTemplate) + `}]}`
+	templates, err := fromComposableTemplates([]byte(response))
+	require.NoError(t, err)
+	require.Len(t, templates, 1)
+	require.JSONEq(t, legacyTemplate, string(templates["blocks"]))
+}
+
+func TestEsClient_OpenSearchPointInTime(t *testing.T) {
+	mut := sync.Mutex{}
+	requests := make([]string, 0)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		mut.Lock()
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		numRequests := len(requests)
+		mut.Unlock()
+
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/blocks/_search/point_in_time":
+			require.Equal(t, pointInTimeKeepAlive, r.URL.Query().Get("keep_alive"))
+			_, _ = w.Write([]byte(`{"pit_id":"pit-1"}`))
+		case r.Method == http.MethodDelete && r.URL.Path == "/_search/point_in_time":
+			require.JSONEq(t, `{"pit_id":["pit-1"]}`, string(body))
+			_, _ = w.Write([]byte(`{"pits":[{"successful":true,"pit_id":"pit-1"}]}`))
+		case r.URL.Path == "/_search" && numRequests == 2:
+			require.Equal(t, "pit-1", gjson.GetBytes(body, "pit.id").String())
+			_, _ = w.Write([]byte(`{"pit_id":"pit-1","hits":{"hits":[{"_id":"a","sort":[1,"a"]}]}}`))
+		default:
+			_, _ = w.Write([]byte(`{"pit_id":"pit-1","hits":{"hits":[]}}`))
+		}
+	}))
+	defer server.Close()
+
+	esc, err := NewElasticClient(config.ElasticInstanceConfig{
+		Type:          ClientTypeOpenSearch,
+		URL:           server.URL,
+		IterationMode: IterationModePointInTime,
+	})
+	require.NoError(t, err)
+
+	numPages := 0
+	err = esc.DoScrollRequestAllDocuments("blocks", nil, func(_ []byte) error {
+		numPages++
+		return nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, 1, numPages)
+	require.Equal(t, []string{
+		"POST /blocks/_search/point_in_time",
+		"GET /_search",
+		"GET /_search",
+		"DELETE /_search/point_in_time",
+	}, requests)
+}
+
+func TestEsClient_PolicyTypesByClientType(t *testing.T) {
+	es8Client, _ := NewElasticClient(config.ElasticInstanceConfig{Type: ClientTypeES8, URL: "http://localhost:9200"})
+	require.Error(t, es8Client.PutPolicy(PolicyTypeISM, "rollover", nil))
+
+	openSearchClient, _ := NewElasticClient(config.ElasticInstanceConfig{Type: ClientTypeOpenSearch, URL: "http://localhost:9200"})
+	require.Error(t, openSearchClient.PutPolicy(PolicyTypeILM, "rollover", nil))
+	require.Equal(t, openSearchISMPoliciesPath, openSearchClient.flavor.ismPoliciesPath())
+}
@@ -3,39 +3,80 @@ package elastic
 import (
 	"fmt"
 	"net/http"
+	"strings"
 )
 
 const numOfErrorsToExtractBulkResponse = 5
 
-// bulkRequestResponse defines the structure of a bulk request response
+// bulkItemResponse defines the structure of the response of one bulk request item, regardless of its action
+type bulkItemResponse struct {
+	Index  string `json:"_index"`
+	ID     string `json:"_id"`
+	Status int    `json:"status"`
+	Error  struct {
+		Type   string `json:"type"`
+		Reason string `json:"reason"`
+	} `json:"error"`
+}
+
+// bulkRequestResponse defines the structure of a bulk request response. Every item is keyed by its action
 type bulkRequestResponse struct {
-	Errors bool `json:"errors"`
-	Items  []struct {
-		Index struct {
-			Status int `json:"status"`
-			Error  struct {
-				Type   string `json:"type"`
-				Reason string `json:"reason"`
-			} `json:"error"`
-		} `json:"index"`
-	} `json:"items"`
+	Errors bool                          `json:"errors"`
+	Items  []map[string]bulkItemResponse `json:"items"`
 }
 
-func extractErrorFromBulkResponse(response *bulkRequestResponse) error {
-	count := 0
-	errorsString := ""
-	for _, item := range response.Items {
-		if item.Index.Status < http.StatusBadRequest {
-			continue
-		}
+// BulkItemError holds the failure of one item of a bulk request
+type BulkItemError struct {
+	// Position is the position of the item in the bulk request
+	Position int
+	Index    string
+	ID       string
+	Status   int
+	Type     string
+	Reason   string
+}
 
-		count++
-		errorsString += fmt.Sprintf("{ status code: %d, error type: %s, reason: %s }\n", item.Index.Status, item.Index.Error.Type, item.Index.Error.Reason)
+// BulkError is returned when some of the items of a bulk request failed. The other items were written
+type BulkError struct {
+	NumItems    int
+	FailedItems []*BulkItemError
+}
 
-		if count == numOfErrorsToExtractBulkResponse {
+// Error returns the first failures of the bulk request
+func (be *BulkError) Error() string {
+	builder := &strings.Builder{}
+	for idx, item := range be.FailedItems {
+		if idx == numOfErrorsToExtractBulkResponse {
 			break
 		}
+
+		_, _ = fmt.Fprintf(builder, "{ status code: %d, error type: %s, reason: %s }\n", item.Status, item.Type, item.Reason)
+	}
+
+	return builder.String()
+}
+
+func extractErrorFromBulkResponse(response *bulkRequestResponse) *BulkError {
+	bulkError := &BulkError{
+		NumItems:    len(response.Items),
+		FailedItems: make([]*BulkItemError, 0),
+	}
+	for position, item := range response.Items {
+		for _, itemResponse := range item {
+			if itemResponse.Status < http.StatusBadRequest {
+				continue
+			}
+
+			bulkError.FailedItems = append(bulkError.FailedItems, &BulkItemError{
+				Position: position,
+				Index:    itemResponse.Index,
+				ID:       itemResponse.ID,
+				Status:   itemResponse.Status,
+				Type:     itemResponse.Error.Type,
+				Reason:   itemResponse.Error.Reason,
+			})
+		}
 	}
 
-	return fmt.Errorf("%s", errorsString)
+	return bulkError
 }
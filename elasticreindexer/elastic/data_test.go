@@ -0,0 +1,26 @@
+package elastic
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestExtractErrorFromBulkResponse(t *testing.T) {
+	responseBytes := []byte(`{"errors":true,"items":[
+		{"index":{"_index":"blocks-000001","_id":"a","status":201}},
+		{"create":{"_index":"blocks-000001","_id":"b","status":429,"error":{"type":"es_rejected_execution_exception","reason":"queue full"}}},
+		{"index":{"_index":"blocks-000001","_id":"c","status":400,"error":{"type":"mapper_parsing_exception","reason":"failed to parse"}}}
+	]}`)
+	response := &bulkRequestResponse{}
+	require.NoError(t, json.Unmarshal(responseBytes, response))
+
+	bulkErr := extractErrorFromBulkResponse(response)
+	require.Equal(t, 3, bulkErr.NumItems)
+	require.Equal(t, []*BulkItemError{
+		{Position: 1, Index: "blocks-000001", ID: "b", Status: 429, Type: "es_rejected_execution_exception", Reason: "queue full"},
+		{Position: 2, Index: "blocks-000001", ID: "c", Status: 400, Type: "mapper_parsing_exception", Reason: "failed to parse"},
+	}, bulkErr.FailedItems)
+	require.Contains(t, bulkErr.Error(), "{ status code: 400, error type: mapper_parsing_exception, reason: failed to parse }")
+}
@@ -25,39 +25,89 @@ var (
 const (
 	stepDelayBetweenRequests = 500 * time.Millisecond
 	numRetriesBackOff        = 10
+	defaultPageSize          = 9000
+)
+
+const (
+	// IterationModeScroll will iterate over all the documents using the scroll API
+	IterationModeScroll = "scroll"
+	// IterationModePointInTime will iterate over all the documents using a point in time and search_after
+	IterationModePointInTime = "point-in-time"
 )
 
 type esClient struct {
-	client *elasticsearch.Client
+	client        *elasticsearch.Client
+	flavor        clientFlavor
+	iterationMode string
 
 	// countScroll is used to be incremented after each scroll so the scroll duration is different each time,
 	// bypassing any possible caching based on the same request
 	countScroll int
+
+	// retryHandler, if set, is called every time a request is retried
+	retryHandler func()
 }
 
 // NewElasticClient will create a new instance of an esClient
 func NewElasticClient(cfg config.ElasticInstanceConfig) (*esClient, error) {
+	flavor, err := newClientFlavor(cfg.Type)
+	if err != nil {
+		return nil, err
+	}
+
+	addresses, err := getAddresses(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	transport, err := createTransport(cfg.TLS)
+	if err != nil {
+		return nil, err
+	}
+
+	esc := &esClient{}
 	elasticClient, err := elasticsearch.NewClient(elasticsearch.Config{
-		Addresses:     []string{cfg.URL},
+		Addresses:     addresses,
+		CloudID:       cfg.CloudID,
 		Username:      cfg.Username,
 		Password:      cfg.Password,
+		APIKey:        cfg.APIKey,
 		RetryOnStatus: httpStatusesForRetry,
 		RetryBackoff: func(i int) time.Duration {
 			// A simple exponential delay
 			d := time.Duration(math.Exp2(float64(i))) * time.Second
 			log.Info("elastic: retry backoff", "attempt", i, "sleep duration", d)
+			if esc.retryHandler != nil {
+				esc.retryHandler()
+			}
 			return d
 		},
 		MaxRetries: numRetriesBackOff,
+		Transport:  flavor.wrapTransport(transport),
 	})
 	if err != nil {
 		return nil, err
 	}
 
-	return &esClient{
-		client:      elasticClient,
-		countScroll: 0,
-	}, nil
+	iterationMode := cfg.IterationMode
+	switch iterationMode {
+	case "":
+		iterationMode = IterationModeScroll
+	case IterationModeScroll, IterationModePointInTime:
+	default:
+		return nil, fmt.Errorf("unknown iteration mode %s", iterationMode)
+	}
+
+	esc.client = elasticClient
+	esc.flavor = flavor
+	esc.iterationMode = iterationMode
+
+	return esc, nil
+}
+
+// SetRetryHandler sets the function called every time a request is retried. It has to be called before sending requests
+func (esc *esClient) SetRetryHandler(handler func()) {
+	esc.retryHandler = handler
 }
 
 // GetMultiple queries a multi search and returns the responses
@@ -111,6 +161,25 @@ func (esc *esClient) getCount(index string, body []byte) (uint64, error) {
 	return count.Uint(), nil
 }
 
+// GetStoreSize returns the size in bytes of the primary shards of the provided index
+func (esc *esClient) GetStoreSize(index string) (uint64, error) {
+	res, err := esc.client.Indices.Stats(
+		esc.client.Indices.Stats.WithIndex(index),
+		esc.client.Indices.Stats.WithMetric("store"),
+	)
+	if err != nil {
+		return 0, err
+	}
+
+	respBytes, err := getBytesFromResponse(res)
+	if err != nil {
+		return 0, err
+	}
+
+	size := gjson.Get(string(respBytes), "_all.primaries.store.size_in_bytes")
+	return size.Uint(), nil
+}
+
 // GetMapping will return the mapping of the specified index
 func (esc *esClient) GetMapping(index string) (*bytes.Buffer, error) {
 	res, err := esc.client.Indices.GetMapping(
@@ -125,9 +194,31 @@ func (esc *esClient) GetMapping(index string) (*bytes.Buffer, error) {
 		return nil, err
 	}
 
-	propertiesRes := gjson.Get(string(respBytes), fmt.Sprintf("%s-000001", index))
+	mappings := make(map[string]json.RawMessage)
+	err = json.Unmarshal(respBytes, &mappings)
+	if err != nil {
+		return nil, err
+	}
+
+	return bytes.NewBuffer(selectIndexMapping(mappings, index)), nil
+}
+
+// selectIndexMapping returns the mapping of the requested concrete index or, if an alias was requested, the mapping of
+// its latest generation (the generations are zero-padded, so the latest one is the last in lexicographic order)
+func selectIndexMapping(mappings map[string]json.RawMessage, index string) []byte {
+	mapping, found := mappings[index]
+	if found {
+		return mapping
+	}
+
+	latestIndex := ""
+	for concreteIndex := range mappings {
+		if concreteIndex > latestIndex {
+			latestIndex = concreteIndex
+		}
+	}
 
-	return bytes.NewBufferString(propertiesRes.Raw), nil
+	return mappings[latestIndex]
 }
 
 // CreateIndexWithMapping will create an index with the provided
@@ -156,7 +247,7 @@ func (esc *esClient) CreateIndexWithMapping(targetIndex string, body *bytes.Buff
 
 // PutIndexTemplate creates an elasticsearch index template
 func (esc *esClient) PutIndexTemplate(templateName string, body *bytes.Buffer) error {
-	res, err := esc.client.Indices.PutTemplate(templateName, body)
+	res, err := esc.flavor.putTemplate(esc.client, templateName, body)
 	if err != nil {
 		return err
 	}
@@ -182,7 +273,7 @@ func (esc *esClient) DoesIndexExist(index string) bool {
 
 // DoesTemplateExist checks whether a template is already created
 func (esc *esClient) DoesTemplateExist(index string) bool {
-	res, err := esc.client.Indices.ExistsTemplate([]string{index})
+	res, err := esc.flavor.existsTemplate(esc.client, index)
 
 	return exists(res, err)
 }
@@ -222,20 +313,29 @@ func exists(res *esapi.Response, err error) bool {
 	}
 }
 
-// DoScrollRequestAllDocuments will perform a documents request using scroll api
+// DoScrollRequestAllDocuments will perform a documents request using scroll api or, if configured, using a point in time
 func (esc *esClient) DoScrollRequestAllDocuments(
 	index string,
 	body []byte,
 	handlerFunc func(responseBytes []byte) error,
 ) error {
+	if esc.iterationMode == IterationModePointInTime {
+		return esc.doPointInTimeRequestAllDocuments(index, body, handlerFunc)
+	}
+
 	esc.countScroll++
-	res, err := esc.client.Search(
-		esc.client.Search.WithSize(9000),
-		esc.client.Search.WithScroll(10*time.Minute+time.Duration(esc.countScroll)*time.Millisecond),
+	options := []func(*esapi.SearchRequest){
+		esc.client.Search.WithScroll(10*time.Minute + time.Duration(esc.countScroll)*time.Millisecond),
 		esc.client.Search.WithContext(context.Background()),
 		esc.client.Search.WithIndex(index),
 		esc.client.Search.WithBody(bytes.NewBuffer(body)),
-	)
+	}
+	// the size from the URL would override the one provided in the body
+	if !gjson.GetBytes(body, "size").Exists() {
+		options = append(options, esc.client.Search.WithSize(defaultPageSize))
+	}
+
+	res, err := esc.client.Search(options...)
 	if err != nil {
 		return err
 	}
@@ -367,6 +467,23 @@ func (esc *esClient) PutAlias(index string, alias string) error {
 	return nil
 }
 
+// RefreshIndex makes the documents written in the provided index, or in the indices behind the provided alias, visible
+// to the searches and the counts
+func (esc *esClient) RefreshIndex(index string) error {
+	res, err := esc.client.Indices.Refresh(esc.client.Indices.Refresh.WithIndex(index))
+	if err != nil {
+		return err
+	}
+
+	defer closeBody(res)
+
+	if res.IsError() {
+		return fmt.Errorf("%s", res.String())
+	}
+
+	return nil
+}
+
 // IsInterfaceNil returns true if there is no value under the interface
 func (esc *esClient) IsInterfaceNil() bool {
 	return esc == nil
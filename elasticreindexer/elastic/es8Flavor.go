@@ -0,0 +1,169 @@
+package elastic
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/elastic/go-elasticsearch/v7"
+	"github.com/elastic/go-elasticsearch/v7/esapi"
+)
+
+const (
+	compatibleWithV7MediaType = "application/vnd.elasticsearch+json;compatible-with=7"
+	headerAccept              = "Accept"
+	headerContentType         = "Content-Type"
+)
+
+var composableTemplateSections = []string{"settings", "mappings", "aliases"}
+
+// es8Flavor sends the requests with the REST API compatibility headers, so Elasticsearch 8 accepts the requests of the
+// v7 client and answers in the v7 format. The templates are managed as composable index templates, because the legacy
+// ones are deprecated and are ignored for the indices matched by a composable template. Open Distro ISM is not available
+type es8Flavor struct {
+	es7Flavor
+}
+
+func (flavor *es8Flavor) wrapTransport(transport http.RoundTripper) http.RoundTripper {
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+
+	return &compatibilityTransport{
+		transport: transport,
+	}
+}
+
+func (flavor *es8Flavor) putTemplate(client *elasticsearch.Client, name string, body *bytes.Buffer) (*esapi.Response, error) {
+	composableTemplate, err := toComposableTemplate(body.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("%w while converting the template %s", err, name)
+	}
+
+	return client.Indices.PutIndexTemplate(name, bytes.NewBuffer(composableTemplate))
+}
+
+func (flavor *es8Flavor) getTemplates(client *elasticsearch.Client, pattern string) (map[string]json.RawMessage, error) {
+	res, err := client.Indices.GetIndexTemplate(
+		client.Indices.GetIndexTemplate.WithName(pattern),
+		client.Indices.GetIndexTemplate.WithFlatSettings(true),
+	)
+	if err != nil {
+		return nil, err
+	}
+	if res.StatusCode == http.StatusNotFound {
+		closeBody(res)
+		return make(map[string]json.RawMessage), nil
+	}
+
+	respBytes, err := getBytesFromResponse(res)
+	if err != nil {
+		return nil, err
+	}
+
+	return fromComposableTemplates(respBytes)
+}
+
+func (flavor *es8Flavor) existsTemplate(client *elasticsearch.Client, name string) (*esapi.Response, error) {
+	return client.Indices.ExistsIndexTemplate(name)
+}
+
+func (flavor *es8Flavor) deleteTemplate(client *elasticsearch.Client, name string) (*esapi.Response, error) {
+	return client.Indices.DeleteIndexTemplate(name)
+}
+
+func (flavor *es8Flavor) checkPolicyType(policyType string) error {
+	if policyType != PolicyTypeILM {
+		return fmt.Errorf("policy type %s is not supported by %s clusters", policyType, ClientTypeES8)
+	}
+
+	return nil
+}
+
+// compatibilityTransport asks Elasticsearch 8 to handle every request as a v7 request
+type compatibilityTransport struct {
+	transport http.RoundTripper
+}
+
+// RoundTrip sets the compatibility headers and sends the request
+func (ct *compatibilityTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	compatibleRequest := req.Clone(req.Context())
+	compatibleRequest.Header.Set(headerAccept, compatibleWithV7MediaType)
+	if compatibleRequest.Header.Get(headerContentType) != "" {
+		compatibleRequest.Header.Set(headerContentType, compatibleWithV7MediaType)
+	}
+
+	return ct.transport.RoundTrip(compatibleRequest)
+}
+
+// toComposableTemplate moves the settings, the mappings and the aliases of a legacy template in the template section
+// of a composable template. The order of the legacy template becomes the priority of the composable one
+func toComposableTemplate(legacyTemplate []byte) ([]byte, error) {
+	template := make(object)
+	err := json.Unmarshal(legacyTemplate, &template)
+	if err != nil {
+		return nil, err
+	}
+
+	templateSection := make(object)
+	for _, section := range composableTemplateSections {
+		value, found := template[section]
+		if found {
+			templateSection[section] = value
+			delete(template, section)
+		}
+	}
+	template["template"] = templateSection
+
+	order, found := template["order"]
+	if found {
+		template["priority"] = order
+		delete(template, "order")
+	}
+
+	return json.Marshal(template)
+}
+
+// fromComposableTemplates converts the response of the get index template API to the templates by name, in the
+// format of the legacy templates, so they can be compared with the configured ones
+func fromComposableTemplates(responseBytes []byte) (map[string]json.RawMessage, error) {
+	response := struct {
+		IndexTemplates []struct {
+			Name          string `json:"name"`
+			IndexTemplate object `json:"index_template"`
+		} `json:"index_templates"`
+	}{}
+	err := json.Unmarshal(responseBytes, &response)
+	if err != nil {
+		return nil, err
+	}
+
+	templates := make(map[string]json.RawMessage, len(response.IndexTemplates))
+	for _, indexTemplate := range response.IndexTemplates {
+		template := indexTemplate.IndexTemplate
+		templateSection, ok := template["template"].(map[string]interface{})
+		if ok {
+			for _, section := range composableTemplateSections {
+				value, found := templateSection[section]
+				if found {
+					template[section] = value
+				}
+			}
+		}
+		delete(template, "template")
+
+		priority, found := template["priority"]
+		if found {
+			template["order"] = priority
+			delete(template, "priority")
+		}
+
+		templates[indexTemplate.Name], err = json.Marshal(template)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return templates, nil
+}
@@ -0,0 +1,135 @@
+package elastic
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+)
+
+// GetTemplate returns the template with the provided name, with flat settings, and false if it does not exist
+func (esc *esClient) GetTemplate(name string) ([]byte, bool, error) {
+	templates, err := esc.flavor.getTemplates(esc.client, name)
+	if err != nil {
+		return nil, false, err
+	}
+
+	template, found := templates[name]
+
+	return template, found, nil
+}
+
+// GetTemplatesNames returns the names of the templates matching the provided pattern
+func (esc *esClient) GetTemplatesNames(pattern string) ([]string, error) {
+	templates, err := esc.flavor.getTemplates(esc.client, pattern)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(templates))
+	for name := range templates {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names, nil
+}
+
+// GetIndicesNames returns the names of the concrete indices matching the provided pattern
+func (esc *esClient) GetIndicesNames(pattern string) ([]string, error) {
+	res, err := esc.client.Cat.Indices(
+		esc.client.Cat.Indices.WithIndex(pattern),
+		esc.client.Cat.Indices.WithH("index"),
+		esc.client.Cat.Indices.WithFormat("json"),
+	)
+	if err != nil {
+		return nil, err
+	}
+	if res.StatusCode == http.StatusNotFound {
+		closeBody(res)
+		return make([]string, 0), nil
+	}
+
+	respBytes, err := getBytesFromResponse(res)
+	if err != nil {
+		return nil, err
+	}
+
+	indices := make([]struct {
+		Index string `json:"index"`
+	}, 0)
+	err = json.Unmarshal(respBytes, &indices)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(indices))
+	for _, index := range indices {
+		names = append(names, index.Index)
+	}
+	sort.Strings(names)
+
+	return names, nil
+}
+
+// GetIndexSettings returns the flat settings of the concrete indices behind the provided index or alias
+func (esc *esClient) GetIndexSettings(index string) ([]byte, error) {
+	res, err := esc.client.Indices.GetSettings(
+		esc.client.Indices.GetSettings.WithIndex(index),
+		esc.client.Indices.GetSettings.WithFlatSettings(true),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return getBytesFromResponse(res)
+}
+
+// PutIndexSettings updates the dynamic settings of the provided index
+func (esc *esClient) PutIndexSettings(index string, body *bytes.Buffer) error {
+	res, err := esc.client.Indices.PutSettings(body, esc.client.Indices.PutSettings.WithIndex(index))
+	if err != nil {
+		return err
+	}
+
+	defer closeBody(res)
+
+	if res.IsError() {
+		return fmt.Errorf("%s", res.String())
+	}
+
+	return nil
+}
+
+// DeleteTemplate deletes the template with the provided name
+func (esc *esClient) DeleteTemplate(name string) error {
+	res, err := esc.flavor.deleteTemplate(esc.client, name)
+	if err != nil {
+		return err
+	}
+
+	defer closeBody(res)
+
+	if res.IsError() {
+		return fmt.Errorf("%s", res.String())
+	}
+
+	return nil
+}
+
+// DeleteIndex deletes the provided concrete index, together with its aliases
+func (esc *esClient) DeleteIndex(index string) error {
+	res, err := esc.client.Indices.Delete([]string{index})
+	if err != nil {
+		return err
+	}
+
+	defer closeBody(res)
+
+	if res.IsError() {
+		return fmt.Errorf("%s", res.String())
+	}
+
+	return nil
+}
@@ -0,0 +1,69 @@
+package elastic
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/elastic/go-elasticsearch/v7"
+	"github.com/tidwall/gjson"
+)
+
+const (
+	openSearchISMPoliciesPath    = "/_plugins/_ism/policies/"
+	openSearchPointInTimePath    = "/_search/point_in_time"
+	openSearchPointInTimeIDField = "pit_id"
+)
+
+// openSearchFlavor sends the requests as understood by OpenSearch 2: the point in time API and the ISM policies are
+// served by OpenSearch specific endpoints and the Elasticsearch ILM is not available
+type openSearchFlavor struct {
+	es7Flavor
+}
+
+func (flavor *openSearchFlavor) openPointInTime(client *elasticsearch.Client, index string) (string, error) {
+	path := fmt.Sprintf("/%s%s?keep_alive=%s", index, openSearchPointInTimePath, pointInTimeKeepAlive)
+	res, err := doRawRequest(client, http.MethodPost, path, nil)
+	if err != nil {
+		return "", err
+	}
+
+	bodyBytes, err := getBytesFromResponse(res)
+	if err != nil {
+		return "", err
+	}
+
+	return gjson.GetBytes(bodyBytes, openSearchPointInTimeIDField).String(), nil
+}
+
+func (flavor *openSearchFlavor) closePointInTime(client *elasticsearch.Client, pitID string) error {
+	body, err := json.Marshal(map[string][]string{openSearchPointInTimeIDField: {pitID}})
+	if err != nil {
+		return err
+	}
+
+	res, err := doRawRequest(client, http.MethodDelete, openSearchPointInTimePath, bytes.NewBuffer(body))
+	if err != nil {
+		return err
+	}
+	defer closeBody(res)
+
+	if res.IsError() && res.StatusCode != http.StatusNotFound {
+		return fmt.Errorf("error response: %s", res)
+	}
+
+	return nil
+}
+
+func (flavor *openSearchFlavor) checkPolicyType(policyType string) error {
+	if policyType != PolicyTypeISM {
+		return fmt.Errorf("policy type %s is not supported by %s clusters", policyType, ClientTypeOpenSearch)
+	}
+
+	return nil
+}
+
+func (flavor *openSearchFlavor) ismPoliciesPath() string {
+	return openSearchISMPoliciesPath
+}
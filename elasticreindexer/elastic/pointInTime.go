@@ -0,0 +1,126 @@
+package elastic
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/tidwall/gjson"
+)
+
+const pointInTimeKeepAlive = "10m"
+
+type object = map[string]interface{}
+
+// doPointInTimeRequestAllDocuments will perform a documents request using a point in time and search_after pagination.
+// The hits are sorted by timestamp, with the document ID as tie-breaker, and every page is passed to the handler
+// function exactly as a scroll page would be
+func (esc *esClient) doPointInTimeRequestAllDocuments(
+	index string,
+	body []byte,
+	handlerFunc func(responseBytes []byte) error,
+) error {
+	query := make(object)
+	if len(body) > 0 {
+		err := json.Unmarshal(body, &query)
+		if err != nil {
+			return fmt.Errorf("%w while decoding the query body", err)
+		}
+	}
+
+	pitID, err := esc.openPointInTime(index)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		errClose := esc.closePointInTime(pitID)
+		if errClose != nil {
+			log.Warn("cannot close point in time", "error", errClose)
+		}
+	}()
+
+	if _, found := query["size"]; !found {
+		query["size"] = defaultPageSize
+	}
+	query["sort"] = []interface{}{
+		object{
+			"timestamp": object{
+				"order":         "asc",
+				"unmapped_type": "long",
+			},
+		},
+		object{
+			"_id": object{
+				"order": "asc",
+			},
+		},
+	}
+
+	for {
+		query["pit"] = object{
+			"id":         pitID,
+			"keep_alive": pointInTimeKeepAlive,
+		}
+
+		responseBytes, errSearch := esc.doPointInTimeSearch(query)
+		if errSearch != nil {
+			return errSearch
+		}
+
+		response := gjson.ParseBytes(responseBytes)
+		hits := response.Get("hits.hits").Array()
+		if len(hits) == 0 {
+			return nil
+		}
+
+		err = handlerFunc(responseBytes)
+		if err != nil {
+			return err
+		}
+
+		// the point in time ID can change between requests, so the most recent one has to be used
+		newPitID := response.Get("pit_id").String()
+		if newPitID != "" {
+			pitID = newPitID
+		}
+		query["search_after"] = json.RawMessage(hits[len(hits)-1].Get("sort").Raw)
+
+		time.Sleep(stepDelayBetweenRequests)
+	}
+}
+
+func (esc *esClient) openPointInTime(index string) (string, error) {
+	pitID, err := esc.flavor.openPointInTime(esc.client, index)
+	if err != nil {
+		return "", err
+	}
+	if pitID == "" {
+		return "", fmt.Errorf("empty point in time ID for index %s", index)
+	}
+
+	return pitID, nil
+}
+
+func (esc *esClient) doPointInTimeSearch(query object) ([]byte, error) {
+	queryBytes, err := json.Marshal(query)
+	if err != nil {
+		return nil, err
+	}
+
+	// a search with a point in time must not specify the index
+	res, err := esc.client.Search(
+		esc.client.Search.WithContext(context.Background()),
+		esc.client.Search.WithBody(bytes.NewBuffer(queryBytes)),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return getBytesFromResponse(res)
+}
+
+func (esc *esClient) closePointInTime(pitID string) error {
+	return esc.flavor.closePointInTime(esc.client, pitID)
+}
@@ -0,0 +1,151 @@
+package elastic
+
+import (
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/multiversx/mx-chain-tools-go/elasticreindexer/config"
+	"github.com/stretchr/testify/require"
+	"github.com/tidwall/gjson"
+)
+
+// pointInTimeServer simulates an Elasticsearch 7 cluster serving the provided search responses, one per search request,
+// and records the search bodies and the closed point in time IDs
+type pointInTimeServer struct {
+	*httptest.Server
+	mut             sync.Mutex
+	searchResponses []string
+	searchBodies    []string
+	closedPitIDs    []string
+}
+
+func newPointInTimeServer(searchResponses ...string) *pointInTimeServer {
+	pitServer := &pointInTimeServer{
+		searchResponses: searchResponses,
+	}
+	pitServer.Server = httptest.NewServer(http.HandlerFunc(pitServer.handle))
+
+	return pitServer
+}
+
+func (pitServer *pointInTimeServer) handle(w http.ResponseWriter, r *http.Request) {
+	body, _ := ioutil.ReadAll(r.Body)
+
+	pitServer.mut.Lock()
+	defer pitServer.mut.Unlock()
+
+	switch {
+	case r.Method == http.MethodPost && r.URL.Path == "/blocks/_pit":
+		_, _ = w.Write([]byte(`{"id":"pit-1"}`))
+	case r.Method == http.MethodDelete && r.URL.Path == "/_pit":
+		pitServer.closedPitIDs = append(pitServer.closedPitIDs, gjson.GetBytes(body, "id").String())
+		_, _ = w.Write([]byte(`{"succeeded":true}`))
+	case r.URL.Path == "/_search":
+		pitServer.searchBodies = append(pitServer.searchBodies, string(body))
+		if len(pitServer.searchResponses) == 0 {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"error":"no more responses"}`))
+			return
+		}
+
+		_, _ = w.Write([]byte(pitServer.searchResponses[0]))
+		pitServer.searchResponses = pitServer.searchResponses[1:]
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func createPointInTimeClient(t *testing.T, url string) *esClient {
+	esc, err := NewElasticClient(config.ElasticInstanceConfig{
+		URL:           url,
+		IterationMode: IterationModePointInTime,
+	})
+	require.NoError(t, err)
+
+	return esc
+}
+
+func TestEsClient_PointInTimeShouldPageWithSearchAfterAndTheRefreshedPitID(t *testing.T) {
+	server := newPointInTimeServer(
+		`{"pit_id":"pit-2","hits":{"hits":[{"_id":"a","sort":[10,"a"]},{"_id":"b","sort":[20,"b"]}]}}`,
+		`{"pit_id":"pit-3","hits":{"hits":[{"_id":"c","sort":[30,"c"]}]}}`,
+		`{"pit_id":"pit-3","hits":{"hits":[]}}`,
+	)
+	defer server.Close()
+
+	esc := createPointInTimeClient(t, server.URL)
+
+	pages := make([]string, 0)
+	err := esc.DoScrollRequestAllDocuments("blocks", []byte(`{"query":{"match_all":{}},"size":2}`), func(responseBytes []byte) error {
+		pages = append(pages, gjson.GetBytes(responseBytes, "hits.hits.#._id").Raw)
+		return nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{`["a","b"]`, `["c"]`}, pages)
+
+	require.Len(t, server.searchBodies, 3)
+	for _, body := range server.searchBodies {
+		require.Equal(t, int64(2), gjson.Get(body, "size").Int())
+		require.JSONEq(t, `{"match_all":{}}`, gjson.Get(body, "query").Raw)
+		require.JSONEq(t, `[{"timestamp":{"order":"asc","unmapped_type":"long"}},{"_id":{"order":"asc"}}]`, gjson.Get(body, "sort").Raw)
+		require.Equal(t, pointInTimeKeepAlive, gjson.Get(body, "pit.keep_alive").String())
+	}
+
+	require.Equal(t, "pit-1", gjson.Get(server.searchBodies[0], "pit.id").String())
+	require.False(t, gjson.Get(server.searchBodies[0], "search_after").Exists())
+	require.Equal(t, "pit-2", gjson.Get(server.searchBodies[1], "pit.id").String())
+	require.JSONEq(t, `[20,"b"]`, gjson.Get(server.searchBodies[1], "search_after").Raw)
+	require.Equal(t, "pit-3", gjson.Get(server.searchBodies[2], "pit.id").String())
+	require.JSONEq(t, `[30,"c"]`, gjson.Get(server.searchBodies[2], "search_after").Raw)
+
+	require.Equal(t, []string{"pit-3"}, server.closedPitIDs)
+}
+
+func TestEsClient_PointInTimeShouldCloseThePitOnErrors(t *testing.T) {
+	t.Run("handler error", func(t *testing.T) {
+		server := newPointInTimeServer(`{"pit_id":"pit-2","hits":{"hits":[{"_id":"a","sort":[10,"a"]}]}}`)
+		defer server.Close()
+
+		esc := createPointInTimeClient(t, server.URL)
+
+		expectedErr := errors.New("expected error")
+		err := esc.DoScrollRequestAllDocuments("blocks", nil, func(_ []byte) error {
+			return expectedErr
+		})
+		require.Equal(t, expectedErr, err)
+		require.Equal(t, []string{"pit-1"}, server.closedPitIDs)
+	})
+	t.Run("search error", func(t *testing.T) {
+		server := newPointInTimeServer(`{"pit_id":"pit-2","hits":{"hits":[{"_id":"a","sort":[10,"a"]}]}}`)
+		defer server.Close()
+
+		esc := createPointInTimeClient(t, server.URL)
+
+		numPages := 0
+		err := esc.DoScrollRequestAllDocuments("blocks", nil, func(_ []byte) error {
+			numPages++
+			return nil
+		})
+		require.Error(t, err)
+		require.Equal(t, 1, numPages)
+		require.Equal(t, []string{"pit-2"}, server.closedPitIDs)
+		require.Equal(t, int64(defaultPageSize), gjson.Get(server.searchBodies[0], "size").Int())
+	})
+	t.Run("invalid query should not open a pit", func(t *testing.T) {
+		server := newPointInTimeServer()
+		defer server.Close()
+
+		esc := createPointInTimeClient(t, server.URL)
+
+		err := esc.DoScrollRequestAllDocuments("blocks", []byte(`{"query":`), func(_ []byte) error {
+			return nil
+		})
+		require.Error(t, err)
+		require.Empty(t, server.searchBodies)
+		require.Empty(t, server.closedPitIDs)
+	})
+}
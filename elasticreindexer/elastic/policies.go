@@ -0,0 +1,146 @@
+package elastic
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/elastic/go-elasticsearch/v7/esapi"
+)
+
+const (
+	// PolicyTypeILM is the Elasticsearch index lifecycle management
+	PolicyTypeILM = "ilm"
+	// PolicyTypeISM is the Open Distro (and OpenSearch) index state management
+	PolicyTypeISM = "ism"
+)
+
+// PutPolicy creates or updates the lifecycle policy with the provided name
+func (esc *esClient) PutPolicy(policyType string, policyName string, body *bytes.Buffer) error {
+	err := esc.flavor.checkPolicyType(policyType)
+	if err != nil {
+		return err
+	}
+
+	var res *esapi.Response
+	switch policyType {
+	case PolicyTypeILM:
+		res, err = esc.client.ILM.PutLifecycle(policyName, esc.client.ILM.PutLifecycle.WithBody(body))
+	case PolicyTypeISM:
+		res, err = esc.putISMPolicy(policyName, body)
+	}
+	if err != nil {
+		return err
+	}
+
+	defer closeBody(res)
+
+	if res.IsError() {
+		return fmt.Errorf("%s", res.String())
+	}
+
+	return nil
+}
+
+// DoesPolicyExist checks whether the lifecycle policy with the provided name is already created
+func (esc *esClient) DoesPolicyExist(policyType string, policyName string) bool {
+	err := esc.flavor.checkPolicyType(policyType)
+	if err != nil {
+		log.Warn("esClient.DoesPolicyExist", "error", err.Error())
+		return false
+	}
+
+	if policyType == PolicyTypeILM {
+		res, errGet := esc.client.ILM.GetLifecycle(esc.client.ILM.GetLifecycle.WithPolicy(policyName))
+		return exists(res, errGet)
+	}
+
+	res, err := esc.doISMRequest(http.MethodGet, policyName, nil)
+	return exists(res, err)
+}
+
+// putISMPolicy creates the ISM policy or, if it already exists, updates it, as the ISM API only accepts the update of a
+// policy together with the sequence number and the primary term of its current version
+func (esc *esClient) putISMPolicy(policyName string, body *bytes.Buffer) (*esapi.Response, error) {
+	currentPolicy, found, err := esc.getISMPolicy(policyName)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return esc.doISMRequest(http.MethodPut, policyName, body)
+	}
+
+	version := struct {
+		SeqNo       uint64 `json:"_seq_no"`
+		PrimaryTerm uint64 `json:"_primary_term"`
+	}{}
+	err = json.Unmarshal(currentPolicy, &version)
+	if err != nil {
+		return nil, fmt.Errorf("%w while decoding the version of policy %s", err, policyName)
+	}
+
+	path := fmt.Sprintf("%s?if_seq_no=%d&if_primary_term=%d", policyName, version.SeqNo, version.PrimaryTerm)
+
+	return esc.doISMRequest(http.MethodPut, path, body)
+}
+
+// GetPolicy returns the lifecycle policy with the provided name, as an object holding the policy under the "policy"
+// key, and whether it exists
+func (esc *esClient) GetPolicy(policyType string, policyName string) ([]byte, bool, error) {
+	err := esc.flavor.checkPolicyType(policyType)
+	if err != nil {
+		return nil, false, err
+	}
+
+	if policyType == PolicyTypeISM {
+		return esc.getISMPolicy(policyName)
+	}
+
+	res, err := esc.client.ILM.GetLifecycle(esc.client.ILM.GetLifecycle.WithPolicy(policyName))
+	if err != nil {
+		return nil, false, err
+	}
+	if res.StatusCode == http.StatusNotFound {
+		closeBody(res)
+		return nil, false, nil
+	}
+
+	responseBytes, err := getBytesFromResponse(res)
+	if err != nil {
+		return nil, false, err
+	}
+
+	policies := make(map[string]json.RawMessage)
+	err = json.Unmarshal(responseBytes, &policies)
+	if err != nil {
+		return nil, false, err
+	}
+
+	policy, found := policies[policyName]
+
+	return policy, found, nil
+}
+
+func (esc *esClient) getISMPolicy(policyName string) ([]byte, bool, error) {
+	res, err := esc.doISMRequest(http.MethodGet, policyName, nil)
+	if err != nil {
+		return nil, false, err
+	}
+	if res.StatusCode == http.StatusNotFound {
+		closeBody(res)
+		return nil, false, nil
+	}
+
+	responseBytes, err := getBytesFromResponse(res)
+	if err != nil {
+		return nil, false, err
+	}
+
+	return responseBytes, true, nil
+}
+
+// doISMRequest performs a request on the ISM policies API, which is not covered by the elasticsearch client
+func (esc *esClient) doISMRequest(method string, policyName string, body *bytes.Buffer) (*esapi.Response, error) {
+	return doRawRequest(esc.client, method, esc.flavor.ismPoliciesPath()+policyName, body)
+}
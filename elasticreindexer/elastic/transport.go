@@ -0,0 +1,75 @@
+package elastic
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/multiversx/mx-chain-tools-go/elasticreindexer/config"
+)
+
+var errNoCertificatesInCAFile = errors.New("no PEM certificates found in the CA certificate file")
+
+// getAddresses returns the configured URL followed by the additional node addresses. The client spreads the requests
+// on all of them and retries a failed request on the next one
+func getAddresses(cfg config.ElasticInstanceConfig) ([]string, error) {
+	addresses := make([]string, 0, len(cfg.Addresses)+1)
+	if cfg.URL != "" {
+		addresses = append(addresses, cfg.URL)
+	}
+	for _, address := range cfg.Addresses {
+		if address != "" {
+			addresses = append(addresses, address)
+		}
+	}
+
+	if len(addresses) > 0 && cfg.CloudID != "" {
+		return nil, errors.New("the cloud ID cannot be used together with the URL or the addresses")
+	}
+
+	return addresses, nil
+}
+
+// createTransport returns the HTTP transport configured with the TLS options, or nil if no option is set, so the
+// default transport of the client is used
+func createTransport(cfg config.TLSConfig) (http.RoundTripper, error) {
+	if cfg.CACertFile == "" && cfg.ClientCertFile == "" && cfg.ClientKeyFile == "" && !cfg.InsecureSkipVerify {
+		return nil, nil
+	}
+
+	tlsConfig := &tls.Config{
+		InsecureSkipVerify: cfg.InsecureSkipVerify,
+	}
+
+	if cfg.CACertFile != "" {
+		caCert, err := ioutil.ReadFile(cfg.CACertFile)
+		if err != nil {
+			return nil, fmt.Errorf("%w while reading the CA certificate file", err)
+		}
+
+		tlsConfig.RootCAs = x509.NewCertPool()
+		if !tlsConfig.RootCAs.AppendCertsFromPEM(caCert) {
+			return nil, fmt.Errorf("%w %s", errNoCertificatesInCAFile, cfg.CACertFile)
+		}
+	}
+
+	if cfg.ClientCertFile != "" || cfg.ClientKeyFile != "" {
+		if cfg.ClientCertFile == "" || cfg.ClientKeyFile == "" {
+			return nil, errors.New("both the client certificate file and the client key file have to be set")
+		}
+
+		clientCert, err := tls.LoadX509KeyPair(cfg.ClientCertFile, cfg.ClientKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("%w while loading the client certificate", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{clientCert}
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = tlsConfig
+
+	return transport, nil
+}
@@ -0,0 +1,72 @@
+package elastic
+
+import (
+	"encoding/pem"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/multiversx/mx-chain-tools-go/elasticreindexer/config"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetAddresses(t *testing.T) {
+	addresses, err := getAddresses(config.ElasticInstanceConfig{
+		URL:       "https://node-1:9200",
+		Addresses: []string{"https://node-2:9200", "", "https://node-3:9200"},
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{"https://node-1:9200", "https://node-2:9200", "https://node-3:9200"}, addresses)
+
+	addresses, err = getAddresses(config.ElasticInstanceConfig{CloudID: "cluster:ZXhhbXBsZS5jb20kaWQk"})
+	require.NoError(t, err)
+	require.Empty(t, addresses)
+
+	_, err = getAddresses(config.ElasticInstanceConfig{URL: "https://node-1:9200", CloudID: "cluster:ZXhhbXBsZS5jb20kaWQk"})
+	require.Error(t, err)
+}
+
+func TestCreateTransport(t *testing.T) {
+	transport, err := createTransport(config.TLSConfig{})
+	require.NoError(t, err)
+	require.Nil(t, transport)
+
+	_, err = createTransport(config.TLSConfig{ClientCertFile: "client.crt"})
+	require.Error(t, err)
+
+	invalidCAFile := filepath.Join(t.TempDir(), "ca.pem")
+	require.NoError(t, ioutil.WriteFile(invalidCAFile, []byte("not a certificate"), 0644))
+	_, err = createTransport(config.TLSConfig{CACertFile: invalidCAFile})
+	require.ErrorIs(t, err, errNoCertificatesInCAFile)
+}
+
+func TestNewElasticClient_CustomCAAndAPIKey(t *testing.T) {
+	authorization := ""
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorization = r.Header.Get("Authorization")
+		_, _ = w.Write([]byte(`{"count":3}`))
+	}))
+	defer server.Close()
+
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	caCert := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	require.NoError(t, ioutil.WriteFile(caFile, caCert, 0644))
+
+	esc, err := NewElasticClient(config.ElasticInstanceConfig{
+		URL:      server.URL,
+		Username: "user",
+		Password: "password",
+		APIKey:   "a2V5OnNlY3JldA==",
+		TLS: config.TLSConfig{
+			CACertFile: caFile,
+		},
+	})
+	require.NoError(t, err)
+
+	count, err := esc.GetCount("blocks")
+	require.NoError(t, err)
+	require.Equal(t, uint64(3), count)
+	require.Equal(t, "APIKey a2V5OnNlY3JldA==", authorization)
+}
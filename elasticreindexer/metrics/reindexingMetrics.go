@@ -0,0 +1,304 @@
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const metricsPrefix = "elasticreindexer_"
+
+var labelValueReplacer = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+type indexMetrics struct {
+	documentsRead      uint64
+	documentsWritten   uint64
+	bytesWritten       uint64
+	bulkErrors         uint64
+	retriedItems       uint64
+	rejectedItems      uint64
+	intervalRetries    uint64
+	expectedDocuments  uint64
+	numIntervals       uint64
+	intervalsCompleted uint64
+	startTime          time.Time
+}
+
+// reindexingMetrics holds the progress of the reindexing, for every index, and writes it in the Prometheus text format
+type reindexingMetrics struct {
+	mut            sync.RWMutex
+	indices        map[string]*indexMetrics
+	requestRetries map[string]uint64
+	startTime      time.Time
+	getTime        func() time.Time
+}
+
+// NewReindexingMetrics creates a new reindexing metrics holder
+func NewReindexingMetrics() *reindexingMetrics {
+	return &reindexingMetrics{
+		indices:        make(map[string]*indexMetrics),
+		requestRetries: make(map[string]uint64),
+		startTime:      time.Now(),
+		getTime:        time.Now,
+	}
+}
+
+// getIndex returns the metrics of the index, creating them if necessary. It has to be called under the write lock
+func (rm *reindexingMetrics) getIndex(index string) *indexMetrics {
+	im, found := rm.indices[index]
+	if !found {
+		im = &indexMetrics{
+			startTime: rm.getTime(),
+		}
+		rm.indices[index] = im
+	}
+
+	return im
+}
+
+// AddDocumentsRead increases the number of documents read from the source for the provided index
+func (rm *reindexingMetrics) AddDocumentsRead(index string, numDocuments uint64) {
+	rm.mut.Lock()
+	rm.getIndex(index).documentsRead += numDocuments
+	rm.mut.Unlock()
+}
+
+// AddDocumentsWritten increases the number of documents and bytes written in the destination for the provided index
+func (rm *reindexingMetrics) AddDocumentsWritten(index string, numDocuments uint64, numBytes uint64) {
+	rm.mut.Lock()
+	im := rm.getIndex(index)
+	im.documentsWritten += numDocuments
+	im.bytesWritten += numBytes
+	rm.mut.Unlock()
+}
+
+// AddBulkError increases the number of failed bulk requests for the provided index
+func (rm *reindexingMetrics) AddBulkError(index string) {
+	rm.mut.Lock()
+	rm.getIndex(index).bulkErrors++
+	rm.mut.Unlock()
+}
+
+// AddRetriedItems increases the number of bulk items of the provided index sent again after a retryable failure
+func (rm *reindexingMetrics) AddRetriedItems(index string, numItems uint64) {
+	rm.mut.Lock()
+	rm.getIndex(index).retriedItems += numItems
+	rm.mut.Unlock()
+}
+
+// AddRejectedItems increases the number of bulk items of the provided index permanently rejected by the destination
+func (rm *reindexingMetrics) AddRejectedItems(index string, numItems uint64) {
+	rm.mut.Lock()
+	rm.getIndex(index).rejectedItems += numItems
+	rm.mut.Unlock()
+}
+
+// GetItemsTotals returns the total number of retried and rejected bulk items, for all the indices
+func (rm *reindexingMetrics) GetItemsTotals() (uint64, uint64) {
+	rm.mut.RLock()
+	defer rm.mut.RUnlock()
+
+	retried, rejected := uint64(0), uint64(0)
+	for _, im := range rm.indices {
+		retried += im.retriedItems
+		rejected += im.rejectedItems
+	}
+
+	return retried, rejected
+}
+
+// AddIntervalRetry increases the number of retried intervals for the provided index
+func (rm *reindexingMetrics) AddIntervalRetry(index string) {
+	rm.mut.Lock()
+	rm.getIndex(index).intervalRetries++
+	rm.mut.Unlock()
+}
+
+// AddRequestRetry increases the number of requests retried by the client of the provided cluster
+func (rm *reindexingMetrics) AddRequestRetry(cluster string) {
+	rm.mut.Lock()
+	rm.requestRetries[cluster]++
+	rm.mut.Unlock()
+}
+
+// SetExpectedDocuments sets the number of documents that have to be written for the provided index. It also restarts
+// the time used to compute the ETA of the index
+func (rm *reindexingMetrics) SetExpectedDocuments(index string, numDocuments uint64) {
+	rm.mut.Lock()
+	im := rm.getIndex(index)
+	im.expectedDocuments = numDocuments
+	im.startTime = rm.getTime()
+	rm.mut.Unlock()
+}
+
+// SetIntervals sets the number of intervals of the provided index and how many of them are already completed
+func (rm *reindexingMetrics) SetIntervals(index string, numIntervals int, numCompleted int) {
+	rm.mut.Lock()
+	im := rm.getIndex(index)
+	im.numIntervals = uint64(numIntervals)
+	im.intervalsCompleted = uint64(numCompleted)
+	rm.mut.Unlock()
+}
+
+// MarkIntervalCompleted increases the number of completed intervals of the provided index
+func (rm *reindexingMetrics) MarkIntervalCompleted(index string) {
+	rm.mut.Lock()
+	rm.getIndex(index).intervalsCompleted++
+	rm.mut.Unlock()
+}
+
+type metricDescription struct {
+	name       string
+	help       string
+	metricType string
+	value      func(im *indexMetrics) float64
+}
+
+var indexMetricsDescriptions = []*metricDescription{
+	{
+		name:       "documents_read_total",
+		help:       "Number of documents read from the source",
+		metricType: "counter",
+		value:      func(im *indexMetrics) float64 { return float64(im.documentsRead) },
+	},
+	{
+		name:       "documents_written_total",
+		help:       "Number of documents written in the destination",
+		metricType: "counter",
+		value:      func(im *indexMetrics) float64 { return float64(im.documentsWritten) },
+	},
+	{
+		name:       "bytes_written_total",
+		help:       "Number of bytes sent to the destination with bulk requests",
+		metricType: "counter",
+		value:      func(im *indexMetrics) float64 { return float64(im.bytesWritten) },
+	},
+	{
+		name:       "bulk_errors_total",
+		help:       "Number of failed bulk requests",
+		metricType: "counter",
+		value:      func(im *indexMetrics) float64 { return float64(im.bulkErrors) },
+	},
+	{
+		name:       "bulk_items_retried_total",
+		help:       "Number of bulk items sent again after failing with a retryable status",
+		metricType: "counter",
+		value:      func(im *indexMetrics) float64 { return float64(im.retriedItems) },
+	},
+	{
+		name:       "bulk_items_rejected_total",
+		help:       "Number of bulk items permanently rejected and written in the dead-letter file",
+		metricType: "counter",
+		value:      func(im *indexMetrics) float64 { return float64(im.rejectedItems) },
+	},
+	{
+		name:       "interval_retries_total",
+		help:       "Number of intervals retried after a failure",
+		metricType: "counter",
+		value:      func(im *indexMetrics) float64 { return float64(im.intervalRetries) },
+	},
+	{
+		name:       "expected_documents",
+		help:       "Number of documents that have to be copied",
+		metricType: "gauge",
+		value:      func(im *indexMetrics) float64 { return float64(im.expectedDocuments) },
+	},
+	{
+		name:       "intervals",
+		help:       "Number of intervals the index is split in",
+		metricType: "gauge",
+		value:      func(im *indexMetrics) float64 { return float64(im.numIntervals) },
+	},
+	{
+		name:       "intervals_completed",
+		help:       "Number of completed intervals",
+		metricType: "gauge",
+		value:      func(im *indexMetrics) float64 { return float64(im.intervalsCompleted) },
+	},
+}
+
+// WriteTo writes all the metrics in the Prometheus text exposition format
+func (rm *reindexingMetrics) WriteTo(w io.Writer) (int64, error) {
+	rm.mut.RLock()
+	defer rm.mut.RUnlock()
+
+	indices := make([]string, 0, len(rm.indices))
+	for index := range rm.indices {
+		indices = append(indices, index)
+	}
+	sort.Strings(indices)
+
+	builder := &strings.Builder{}
+	for _, description := range indexMetricsDescriptions {
+		writeHeader(builder, description.name, description.help, description.metricType)
+		for _, index := range indices {
+			writeSample(builder, description.name, "index", index, description.value(rm.indices[index]))
+		}
+	}
+
+	now := rm.getTime()
+	writeHeader(builder, "index_eta_seconds", "Estimated number of seconds until all the expected documents of the index are written", "gauge")
+	for _, index := range indices {
+		im := rm.indices[index]
+		writeSample(builder, "index_eta_seconds", "index", index, computeETA(im.expectedDocuments, im.documentsWritten, now.Sub(im.startTime)))
+	}
+
+	totalExpected, totalWritten := uint64(0), uint64(0)
+	for _, im := range rm.indices {
+		totalExpected += im.expectedDocuments
+		totalWritten += im.documentsWritten
+	}
+	writeHeader(builder, "eta_seconds", "Estimated number of seconds until all the expected documents are written", "gauge")
+	writeSample(builder, "eta_seconds", "", "", computeETA(totalExpected, totalWritten, now.Sub(rm.startTime)))
+
+	clusters := make([]string, 0, len(rm.requestRetries))
+	for cluster := range rm.requestRetries {
+		clusters = append(clusters, cluster)
+	}
+	sort.Strings(clusters)
+
+	writeHeader(builder, "request_retries_total", "Number of requests retried by the clients", "counter")
+	for _, cluster := range clusters {
+		writeSample(builder, "request_retries_total", "cluster", cluster, float64(rm.requestRetries[cluster]))
+	}
+
+	numBytes, err := io.WriteString(w, builder.String())
+
+	return int64(numBytes), err
+}
+
+// computeETA estimates the remaining seconds based on the average writing speed. It returns -1 if it cannot be estimated
+func computeETA(expected uint64, written uint64, elapsed time.Duration) float64 {
+	if written >= expected {
+		return 0
+	}
+	if written == 0 || elapsed <= 0 {
+		return -1
+	}
+
+	documentsPerSecond := float64(written) / elapsed.Seconds()
+
+	return float64(expected-written) / documentsPerSecond
+}
+
+func writeHeader(builder *strings.Builder, name string, help string, metricType string) {
+	_, _ = fmt.Fprintf(builder, "# HELP %s%s %s\n", metricsPrefix, name, help)
+	_, _ = fmt.Fprintf(builder, "# TYPE %s%s %s\n", metricsPrefix, name, metricType)
+}
+
+func writeSample(builder *strings.Builder, name string, labelName string, labelValue string, value float64) {
+	if labelName == "" {
+		_, _ = fmt.Fprintf(builder, "%s%s %g\n", metricsPrefix, name, value)
+		return
+	}
+
+	_, _ = fmt.Fprintf(builder, "%s%s{%s=\"%s\"} %g\n", metricsPrefix, name, labelName, labelValueReplacer.Replace(labelValue), value)
+}
+
+// IsInterfaceNil returns true if there is no value under the interface
+func (rm *reindexingMetrics) IsInterfaceNil() bool {
+	return rm == nil
+}
@@ -0,0 +1,72 @@
+package metrics
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestReindexingMetrics_WriteTo(t *testing.T) {
+	now := time.Unix(1000, 0)
+	rm := NewReindexingMetrics()
+	rm.getTime = func() time.Time {
+		return now
+	}
+	rm.startTime = now
+
+	rm.SetExpectedDocuments("transactions", 100)
+	rm.SetIntervals("transactions", 4, 1)
+	rm.AddDocumentsRead("transactions", 30)
+	rm.AddDocumentsWritten("transactions", 25, 2048)
+	rm.AddBulkError("transactions")
+	rm.AddIntervalRetry("transactions")
+	rm.MarkIntervalCompleted("transactions")
+	rm.AddRequestRetry("output")
+	now = now.Add(10 * time.Second)
+
+	buff := &bytes.Buffer{}
+	_, err := rm.WriteTo(buff)
+	require.NoError(t, err)
+
+	output := buff.String()
+	for _, line := range []string{
+		"# TYPE elasticreindexer_documents_read_total counter",
+		`elasticreindexer_documents_read_total{index="transactions"} 30`,
+		`elasticreindexer_documents_written_total{index="transactions"} 25`,
+		`elasticreindexer_bytes_written_total{index="transactions"} 2048`,
+		`elasticreindexer_bulk_errors_total{index="transactions"} 1`,
+		`elasticreindexer_interval_retries_total{index="transactions"} 1`,
+		`elasticreindexer_intervals{index="transactions"} 4`,
+		`elasticreindexer_intervals_completed{index="transactions"} 2`,
+		`elasticreindexer_index_eta_seconds{index="transactions"} 30`,
+		"elasticreindexer_eta_seconds 30",
+		`elasticreindexer_request_retries_total{cluster="output"} 1`,
+	} {
+		require.Contains(t, output, line+"\n")
+	}
+}
+
+func TestComputeETA(t *testing.T) {
+	require.Equal(t, float64(0), computeETA(10, 10, time.Second))
+	require.Equal(t, float64(-1), computeETA(10, 0, time.Second))
+	require.Equal(t, float64(9), computeETA(10, 1, time.Second))
+}
+
+func TestMetricsHandler(t *testing.T) {
+	rm := NewReindexingMetrics()
+	rm.AddDocumentsRead("blocks", 7)
+
+	recorder := httptest.NewRecorder()
+	createHandler(rm).ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, metricsPath, nil))
+
+	require.Equal(t, http.StatusOK, recorder.Code)
+	require.Contains(t, recorder.Body.String(), `elasticreindexer_documents_read_total{index="blocks"} 7`)
+
+	recorder = httptest.NewRecorder()
+	createHandler(rm).ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/other", nil))
+	require.Equal(t, http.StatusNotFound, recorder.Code)
+}
@@ -0,0 +1,48 @@
+package metrics
+
+import (
+	"errors"
+	"io"
+	"net"
+	"net/http"
+
+	logger "github.com/multiversx/mx-chain-logger-go"
+)
+
+const metricsPath = "/metrics"
+
+var log = logger.GetOrCreate("metrics")
+
+// StartServer starts serving the provided metrics, in the Prometheus text format, on the /metrics path of the
+// provided address. The returned server has to be closed by the caller
+func StartServer(address string, writer io.WriterTo) (*http.Server, error) {
+	listener, err := net.Listen("tcp", address)
+	if err != nil {
+		return nil, err
+	}
+
+	server := &http.Server{Handler: createHandler(writer)}
+	go func() {
+		errServe := server.Serve(listener)
+		if errServe != nil && !errors.Is(errServe, http.ErrServerClosed) {
+			log.Error("metrics server stopped", "error", errServe.Error())
+		}
+	}()
+
+	log.Info("serving metrics", "address", listener.Addr().String(), "path", metricsPath)
+
+	return server, nil
+}
+
+func createHandler(writer io.WriterTo) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc(metricsPath, func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+		_, err := writer.WriteTo(w)
+		if err != nil {
+			log.Debug("cannot write the metrics", "error", err.Error())
+		}
+	})
+
+	return mux
+}
@@ -0,0 +1,244 @@
+package process
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/multiversx/mx-chain-core-go/core/check"
+	"github.com/multiversx/mx-chain-tools-go/elasticreindexer/config"
+	"github.com/multiversx/mx-chain-tools-go/elasticreindexer/elastic"
+)
+
+const generationDigits = 6
+
+var (
+	errNilAliasClient         = errors.New("nil alias client handler")
+	errNoPreviousGeneration   = errors.New("no previous generation")
+	errUnexpectedAliasIndices = errors.New("unexpected number of indices behind the alias")
+)
+
+// ArgsAliasSwapper is the DTO used in the NewAliasSwapper constructor function
+type ArgsAliasSwapper struct {
+	SourceElastic      ElasticClientHandler
+	DestinationElastic AliasClientHandler
+	IndicesConfig      config.IndicesConfig
+}
+
+// aliasSwapper reindexes into new generations of the destination indices (<alias>-000002, <alias>-000003, ...) and
+// moves the aliases on them only after the data was copied, so the readers never see a half-filled index
+type aliasSwapper struct {
+	sourceElastic      ElasticClientHandler
+	destinationElastic AliasClientHandler
+	templatesDirectory string
+	names              indexNames
+}
+
+// NewAliasSwapper creates a new alias swapper instance
+func NewAliasSwapper(args ArgsAliasSwapper) (*aliasSwapper, error) {
+	if check.IfNil(args.SourceElastic) {
+		return nil, fmt.Errorf("%w for source", errNilElasticHandler)
+	}
+	if check.IfNil(args.DestinationElastic) {
+		return nil, fmt.Errorf("%w for destination", errNilAliasClient)
+	}
+
+	return &aliasSwapper{
+		sourceElastic:      args.SourceElastic,
+		destinationElastic: args.DestinationElastic,
+		templatesDirectory: args.IndicesConfig.Mappings.TemplatesDirectory,
+		names:              newIndexNames(args.IndicesConfig),
+	}, nil
+}
+
+// CreateGeneration creates, without any alias, the next generation of the destination index, using the template of
+// the index or the source mapping. If the newest generation is not behind the alias, it was left unfinished by a
+// previous run (or rolled back) and it is reused instead. It returns the name of the concrete index
+func (as *aliasSwapper) CreateGeneration(index string) (string, error) {
+	alias := as.names.alias(index)
+	generations, err := as.getGenerations(alias)
+	if err != nil {
+		return "", err
+	}
+
+	nextGeneration := uint64(1)
+	if len(generations) > 0 {
+		lastGenerationIndex := formatGeneration(alias, generations[len(generations)-1])
+		isUnfinished, errCheck := as.isOutsideAlias(alias, lastGenerationIndex)
+		if errCheck != nil {
+			return "", errCheck
+		}
+		if isUnfinished {
+			log.Info("reusing the unfinished generation", "index", index, "generation", lastGenerationIndex)
+			return lastGenerationIndex, nil
+		}
+
+		nextGeneration = generations[len(generations)-1] + 1
+	}
+	generationIndex := formatGeneration(alias, nextGeneration)
+
+	getMapping := func() (*bytes.Buffer, error) {
+		return as.sourceElastic.GetMapping(as.names.source(index))
+	}
+	body, _, err := getIndexBody(as.templatesDirectory, index, getMapping)
+	if err != nil {
+		return "", err
+	}
+
+	err = as.destinationElastic.CreateIndexWithMapping(generationIndex, body)
+	if err != nil {
+		return "", fmt.Errorf("%w while creating the index %s", err, generationIndex)
+	}
+
+	return generationIndex, nil
+}
+
+func (as *aliasSwapper) isOutsideAlias(alias string, generationIndex string) (bool, error) {
+	currentIndices, err := as.destinationElastic.GetAliasIndices(alias)
+	if err != nil {
+		return false, fmt.Errorf("%w while getting the indices of alias %s", err, alias)
+	}
+
+	for _, currentIndex := range currentIndices {
+		if currentIndex == generationIndex {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+// Swap moves the aliases of the provided indices from their current concrete indices to the provided generations
+// (keyed by index) with a single request, so either all the aliases are moved or none of them
+func (as *aliasSwapper) Swap(generations map[string]string) error {
+	indices := make([]string, 0, len(generations))
+	for index := range generations {
+		indices = append(indices, index)
+	}
+	sort.Strings(indices)
+
+	swaps := make([]elastic.AliasSwap, 0, len(indices))
+	for _, index := range indices {
+		alias := as.names.alias(index)
+		currentIndices, err := as.destinationElastic.GetAliasIndices(alias)
+		if err != nil {
+			return fmt.Errorf("%w while getting the indices of alias %s", err, alias)
+		}
+
+		removeFromIndices := make([]string, 0, len(currentIndices))
+		for _, currentIndex := range currentIndices {
+			if currentIndex != generations[index] {
+				removeFromIndices = append(removeFromIndices, currentIndex)
+			}
+		}
+
+		swaps = append(swaps, elastic.AliasSwap{
+			Alias:             alias,
+			RemoveFromIndices: removeFromIndices,
+			AddToIndex:        generations[index],
+		})
+	}
+
+	err := as.destinationElastic.SwapAliases(swaps)
+	if err != nil {
+		return fmt.Errorf("%w while moving the aliases", err)
+	}
+
+	for _, swap := range swaps {
+		log.Info("alias swapped", "alias", swap.Alias, "from", strings.Join(swap.RemoveFromIndices, ","), "to", swap.AddToIndex)
+	}
+
+	return nil
+}
+
+// Rollback moves the aliases of the provided indices back to the generations preceding the ones they currently point
+// to, with a single request. The newer generations are kept, so they can be inspected or swapped in again
+func (as *aliasSwapper) Rollback(indices []string) error {
+	previousGenerations := make(map[string]string, len(indices))
+	for _, index := range indices {
+		previousGeneration, err := as.getPreviousGeneration(index)
+		if err != nil {
+			return err
+		}
+
+		previousGenerations[index] = previousGeneration
+	}
+
+	return as.Swap(previousGenerations)
+}
+
+// getPreviousGeneration returns the generation preceding the one the alias of the index currently points to
+func (as *aliasSwapper) getPreviousGeneration(index string) (string, error) {
+	alias := as.names.alias(index)
+	currentIndices, err := as.destinationElastic.GetAliasIndices(alias)
+	if err != nil {
+		return "", fmt.Errorf("%w while getting the indices of alias %s", err, alias)
+	}
+	if len(currentIndices) != 1 {
+		return "", fmt.Errorf("%w %s: %d", errUnexpectedAliasIndices, alias, len(currentIndices))
+	}
+
+	currentGeneration, ok := parseGeneration(alias, currentIndices[0])
+	if !ok {
+		return "", fmt.Errorf("%w for alias %s, %s is not a generation of the alias", errNoPreviousGeneration, alias, currentIndices[0])
+	}
+
+	generations, err := as.getGenerations(alias)
+	if err != nil {
+		return "", err
+	}
+
+	previousGeneration := uint64(0)
+	for _, generation := range generations {
+		if generation < currentGeneration {
+			previousGeneration = generation
+		}
+	}
+	if previousGeneration == 0 {
+		return "", fmt.Errorf("%w for alias %s, it points to %s", errNoPreviousGeneration, alias, currentIndices[0])
+	}
+
+	return formatGeneration(alias, previousGeneration), nil
+}
+
+// getGenerations returns the sorted generations of the concrete indices of the alias
+func (as *aliasSwapper) getGenerations(alias string) ([]uint64, error) {
+	indices, err := as.destinationElastic.GetIndicesNames(alias + "-*")
+	if err != nil {
+		return nil, fmt.Errorf("%w while getting the indices of alias %s", err, alias)
+	}
+
+	generations := make([]uint64, 0, len(indices))
+	for _, index := range indices {
+		generation, ok := parseGeneration(alias, index)
+		if ok {
+			generations = append(generations, generation)
+		}
+	}
+	sort.Slice(generations, func(i, j int) bool {
+		return generations[i] < generations[j]
+	})
+
+	return generations, nil
+}
+
+func parseGeneration(alias string, index string) (uint64, bool) {
+	suffix := strings.TrimPrefix(index, alias+"-")
+	if suffix == index || len(suffix) != generationDigits {
+		return 0, false
+	}
+
+	generation, err := strconv.ParseUint(suffix, 10, 64)
+	if err != nil {
+		return 0, false
+	}
+
+	return generation, true
+}
+
+func formatGeneration(alias string, generation uint64) string {
+	return fmt.Sprintf("%s-%0*d", alias, generationDigits, generation)
+}
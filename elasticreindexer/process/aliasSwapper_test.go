@@ -0,0 +1,180 @@
+package process
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/multiversx/mx-chain-tools-go/elasticreindexer/config"
+	"github.com/multiversx/mx-chain-tools-go/elasticreindexer/elastic"
+	"github.com/multiversx/mx-chain-tools-go/elasticreindexer/process/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewAliasSwapper(t *testing.T) {
+	as, err := NewAliasSwapper(ArgsAliasSwapper{DestinationElastic: &mock.ElasticClientStub{}})
+	require.Nil(t, as)
+	require.ErrorIs(t, err, errNilElasticHandler)
+
+	as, err = NewAliasSwapper(ArgsAliasSwapper{SourceElastic: &mock.ElasticClientStub{}})
+	require.Nil(t, as)
+	require.ErrorIs(t, err, errNilAliasClient)
+
+	as, err = NewAliasSwapper(ArgsAliasSwapper{SourceElastic: &mock.ElasticClientStub{}, DestinationElastic: &mock.ElasticClientStub{}})
+	require.NotNil(t, as)
+	require.NoError(t, err)
+}
+
+func TestAliasSwapper_CreateGenerationShouldCreateTheNextGenerationWithoutAlias(t *testing.T) {
+	createdIndex := ""
+	putAliasCalled := false
+	destination := &mock.ElasticClientStub{
+		GetIndicesNamesCalled: func(pattern string) ([]string, error) {
+			require.Equal(t, "devnet-transactions-*", pattern)
+			return []string{"devnet-transactions-000001", "devnet-transactions-000002", "devnet-transactions-backup"}, nil
+		},
+		GetAliasIndicesCalled: func(alias string) ([]string, error) {
+			require.Equal(t, "devnet-transactions", alias)
+			return []string{"devnet-transactions-000002"}, nil
+		},
+		CreateIndexWithMappingCalled: func(targetIndex string, _ *bytes.Buffer) error {
+			createdIndex = targetIndex
+			return nil
+		},
+		PutAliasCalled: func(_ string, _ string) error {
+			putAliasCalled = true
+			return nil
+		},
+	}
+	source := &mock.ElasticClientStub{
+		GetMappingCalled: func(index string) (*bytes.Buffer, error) {
+			require.Equal(t, "transactions", index)
+			return bytes.NewBufferString(`{}`), nil
+		},
+	}
+
+	as, _ := NewAliasSwapper(ArgsAliasSwapper{
+		SourceElastic:      source,
+		DestinationElastic: destination,
+		IndicesConfig:      config.IndicesConfig{DestinationIndexPrefix: "devnet-"},
+	})
+
+	generation, err := as.CreateGeneration("transactions")
+	require.NoError(t, err)
+	require.Equal(t, "devnet-transactions-000003", generation)
+	require.Equal(t, generation, createdIndex)
+	require.False(t, putAliasCalled)
+}
+
+func TestAliasSwapper_CreateGenerationShouldReuseTheUnfinishedGeneration(t *testing.T) {
+	destination := &mock.ElasticClientStub{
+		GetIndicesNamesCalled: func(_ string) ([]string, error) {
+			return []string{"transactions-000001", "transactions-000002"}, nil
+		},
+		GetAliasIndicesCalled: func(_ string) ([]string, error) {
+			return []string{"transactions-000001"}, nil
+		},
+		CreateIndexWithMappingCalled: func(_ string, _ *bytes.Buffer) error {
+			require.Fail(t, "should not create another generation")
+			return nil
+		},
+	}
+	as, _ := NewAliasSwapper(ArgsAliasSwapper{SourceElastic: &mock.ElasticClientStub{}, DestinationElastic: destination})
+
+	generation, err := as.CreateGeneration("transactions")
+	require.NoError(t, err)
+	require.Equal(t, "transactions-000002", generation)
+}
+
+func TestAliasSwapper_SwapShouldMoveAllTheAliasesWithOneRequest(t *testing.T) {
+	numSwaps := 0
+	destination := &mock.ElasticClientStub{
+		GetAliasIndicesCalled: func(alias string) ([]string, error) {
+			return []string{alias + "-000001"}, nil
+		},
+		SwapAliasesCalled: func(swaps []elastic.AliasSwap) error {
+			numSwaps++
+			require.Equal(t, []elastic.AliasSwap{
+				{Alias: "blocks", RemoveFromIndices: []string{"blocks-000001"}, AddToIndex: "blocks-000002"},
+				{Alias: "transactions", RemoveFromIndices: []string{"transactions-000001"}, AddToIndex: "transactions-000002"},
+			}, swaps)
+			return nil
+		},
+	}
+
+	as, _ := NewAliasSwapper(ArgsAliasSwapper{SourceElastic: &mock.ElasticClientStub{}, DestinationElastic: destination})
+
+	require.NoError(t, as.Swap(map[string]string{"transactions": "transactions-000002", "blocks": "blocks-000002"}))
+	require.Equal(t, 1, numSwaps)
+}
+
+func TestAliasSwapper_Rollback(t *testing.T) {
+	t.Run("should move the aliases on the previous generations", func(t *testing.T) {
+		var swapped []elastic.AliasSwap
+		destination := &mock.ElasticClientStub{
+			GetAliasIndicesCalled: func(alias string) ([]string, error) {
+				return []string{alias + "-000003"}, nil
+			},
+			GetIndicesNamesCalled: func(pattern string) ([]string, error) {
+				if pattern == "blocks-*" {
+					return []string{"blocks-000002", "blocks-000003"}, nil
+				}
+				return []string{"transactions-000001", "transactions-000003", "transactions-000004"}, nil
+			},
+			SwapAliasesCalled: func(swaps []elastic.AliasSwap) error {
+				swapped = swaps
+				return nil
+			},
+		}
+		as, _ := NewAliasSwapper(ArgsAliasSwapper{SourceElastic: &mock.ElasticClientStub{}, DestinationElastic: destination})
+
+		require.NoError(t, as.Rollback([]string{"transactions", "blocks"}))
+		require.Equal(t, []elastic.AliasSwap{
+			{Alias: "blocks", RemoveFromIndices: []string{"blocks-000003"}, AddToIndex: "blocks-000002"},
+			{Alias: "transactions", RemoveFromIndices: []string{"transactions-000003"}, AddToIndex: "transactions-000001"},
+		}, swapped)
+	})
+	t.Run("first generation should err without moving any alias", func(t *testing.T) {
+		destination := &mock.ElasticClientStub{
+			GetAliasIndicesCalled: func(alias string) ([]string, error) {
+				if alias == "blocks" {
+					return []string{"blocks-000002"}, nil
+				}
+				return []string{"transactions-000001"}, nil
+			},
+			GetIndicesNamesCalled: func(pattern string) ([]string, error) {
+				if pattern == "blocks-*" {
+					return []string{"blocks-000001", "blocks-000002"}, nil
+				}
+				return []string{"transactions-000001", "transactions-000002"}, nil
+			},
+			SwapAliasesCalled: func(_ []elastic.AliasSwap) error {
+				require.Fail(t, "should not move any alias")
+				return nil
+			},
+		}
+		as, _ := NewAliasSwapper(ArgsAliasSwapper{SourceElastic: &mock.ElasticClientStub{}, DestinationElastic: destination})
+
+		require.ErrorIs(t, as.Rollback([]string{"blocks", "transactions"}), errNoPreviousGeneration)
+	})
+	t.Run("alias on several indices should err", func(t *testing.T) {
+		destination := &mock.ElasticClientStub{
+			GetAliasIndicesCalled: func(_ string) ([]string, error) {
+				return []string{"transactions-000001", "transactions-000002"}, nil
+			},
+		}
+		as, _ := NewAliasSwapper(ArgsAliasSwapper{SourceElastic: &mock.ElasticClientStub{}, DestinationElastic: destination})
+
+		require.ErrorIs(t, as.Rollback([]string{"transactions"}), errUnexpectedAliasIndices)
+	})
+}
+
+func TestIndexNames_DestinationIndicesShouldOverrideThePrefix(t *testing.T) {
+	names := newIndexNames(config.IndicesConfig{
+		DestinationIndexPrefix: "devnet-",
+		DestinationIndices:     map[string]string{"logs": "devnet-logs-000002"},
+	})
+
+	require.Equal(t, "devnet-logs-000002", names.destination("logs"))
+	require.Equal(t, "devnet-logs", names.alias("logs"))
+	require.Equal(t, "devnet-blocks", names.destination("blocks"))
+}
@@ -2,8 +2,8 @@ package process
 
 import "bytes"
 
-// bulkSizeThreshold is the constant for the maximum size of one bulk request that is sent to the elasticsearch database
-const bulkSizeThreshold = 838860 // 0.8MB
+// defaultBulkSizeThreshold is the default maximum size of one bulk request that is sent to the elasticsearch database
+const defaultBulkSizeThreshold = 838860 // 0.8MB
 
 // BufferSlice extend structure bytes.Buffer with new methods
 type bufferSlice struct {
@@ -13,7 +13,7 @@ type bufferSlice struct {
 }
 
 // newBufferSlice will create a new buffer
-func newBufferSlice() *bufferSlice {
+func newBufferSlice(bulkSizeThreshold int) *bufferSlice {
 	return &bufferSlice{
 		buffSlice:         make([]*bytes.Buffer, 0),
 		bulkSizeThreshold: bulkSizeThreshold,
@@ -0,0 +1,123 @@
+package process
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/multiversx/mx-chain-tools-go/elasticreindexer/elastic"
+)
+
+var errBulkItemsMismatch = errors.New("the bulk response items do not match the request items")
+
+// doBulkRequestWithItemsRetries sends the bulk request and handles its failed items: the ones failed with a retryable
+// status are sent again, the other ones are written in the dead-letter file. It returns the number of documents
+// skipped because, when creating the documents, they already exist and the IDs of the documents rejected
+func (r *reindexer) doBulkRequestWithItemsRetries(buff *bytes.Buffer, index string) (int, []string, error) {
+	destinationIndex := r.names.destination(index)
+	items := splitBulkItems(buff.Bytes())
+	numSkipped, rejectedIDs := 0, make([]string, 0)
+
+	for attempt := 0; ; attempt++ {
+		err := r.destinationElastic.DoBulkRequest(buff, destinationIndex)
+		var bulkErr *elastic.BulkError
+		if !errors.As(err, &bulkErr) {
+			return numSkipped, rejectedIDs, err
+		}
+		if bulkErr.NumItems != len(items) {
+			return numSkipped, rejectedIDs, fmt.Errorf("%w, %d items sent, %d items received", errBulkItemsMismatch, len(items), bulkErr.NumItems)
+		}
+
+		retryItems := make([][]byte, 0)
+		for _, failedItem := range bulkErr.FailedItems {
+			item := items[failedItem.Position]
+			if r.documents.isSkippedItem(index, failedItem.Status) {
+				log.Debug("document already exists in the destination, skipping", "index", index, "id", failedItem.ID)
+				numSkipped++
+				continue
+			}
+			if isRetryableStatus(failedItem.Status) && attempt < r.bulkItems.NumRetries {
+				retryItems = append(retryItems, item)
+				continue
+			}
+
+			err = r.addToDeadLetter(destinationIndex, failedItem, item)
+			if err != nil {
+				return numSkipped, rejectedIDs, err
+			}
+			rejectedIDs = append(rejectedIDs, failedItem.ID)
+			r.metrics.AddRejectedItems(index, 1)
+		}
+
+		if len(retryItems) == 0 {
+			return numSkipped, rejectedIDs, nil
+		}
+
+		log.Warn("retrying failed bulk items", "index", index, "num items", len(retryItems), "attempt", attempt+1)
+		r.metrics.AddRetriedItems(index, uint64(len(retryItems)))
+		time.Sleep(time.Duration(attempt+1) * time.Duration(r.bulkItems.DelayBetweenRetriesInMs) * time.Millisecond)
+
+		items = retryItems
+		buff = bytes.NewBuffer(bytes.Join(items, nil))
+	}
+}
+
+func (r *reindexer) addToDeadLetter(destinationIndex string, failedItem *elastic.BulkItemError, item []byte) error {
+	document := &DeadLetterDocument{
+		Index:     failedItem.Index,
+		ID:        failedItem.ID,
+		Status:    failedItem.Status,
+		ErrorType: failedItem.Type,
+		Reason:    failedItem.Reason,
+	}
+	if document.Index == "" {
+		document.Index = destinationIndex
+	}
+
+	newLinePosition := bytes.IndexByte(item, '\n')
+	if newLinePosition >= 0 {
+		source := bytes.TrimSpace(item[newLinePosition+1:])
+		if len(source) > 0 {
+			document.Source = source
+		}
+	}
+
+	log.Warn("document rejected by the destination", "index", document.Index, "id", document.ID,
+		"status", document.Status, "reason", document.Reason)
+
+	err := r.deadLetter.Add(document)
+	if err != nil {
+		return fmt.Errorf("%w while writing the rejected document %s in the dead-letter file", err, document.ID)
+	}
+
+	return nil
+}
+
+// splitBulkItems splits the bulk request body in items, every item holding the action line and the document line
+func splitBulkItems(body []byte) [][]byte {
+	items := make([][]byte, 0)
+	itemStart := 0
+	numLines := 0
+	for position, character := range body {
+		if character != '\n' {
+			continue
+		}
+
+		numLines++
+		if numLines%2 == 0 {
+			items = append(items, body[itemStart:position+1])
+			itemStart = position + 1
+		}
+	}
+	if itemStart < len(body) {
+		items = append(items, body[itemStart:])
+	}
+
+	return items
+}
+
+func isRetryableStatus(status int) bool {
+	return status == http.StatusTooManyRequests || status == http.StatusServiceUnavailable
+}
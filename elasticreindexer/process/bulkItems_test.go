@@ -0,0 +1,117 @@
+package process
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/multiversx/mx-chain-tools-go/elasticreindexer/elastic"
+	"github.com/multiversx/mx-chain-tools-go/elasticreindexer/process/mock"
+	"github.com/stretchr/testify/require"
+)
+
+const testBulkBody = `{"index":{"_id":"a"}}
+{"nonce":1}
+{"index":{"_id":"b"}}
+{"nonce":2}
+{"index":{"_id":"c"}}
+{"nonce":3}
+`
+
+func TestSplitBulkItems(t *testing.T) {
+	items := splitBulkItems([]byte(testBulkBody))
+	require.Len(t, items, 3)
+	require.Equal(t, "{\"index\":{\"_id\":\"b\"}}\n{\"nonce\":2}\n", string(items[1]))
+
+	require.Len(t, splitBulkItems([]byte("{\"index\":{\"_id\":\"a\"}}\n{\"nonce\":1}")), 1)
+	require.Len(t, splitBulkItems(nil), 0)
+}
+
+func TestReindexer_DoBulkRequestsShouldRetryAndDeadLetterFailedItems(t *testing.T) {
+	requests := make([]string, 0)
+	destination := &mock.ElasticClientStub{
+		DoBulkRequestCalled: func(buff *bytes.Buffer, _ string) error {
+			requests = append(requests, buff.String())
+			switch len(requests) {
+			case 1:
+				return &elastic.BulkError{
+					NumItems: 3,
+					FailedItems: []*elastic.BulkItemError{
+						{Position: 0, ID: "a", Index: "index-000001", Status: 429, Reason: "too many requests"},
+						{Position: 2, ID: "c", Index: "index-000001", Status: 400, Type: "mapper_parsing_exception"},
+					},
+				}
+			default:
+				return nil
+			}
+		},
+	}
+	numWritten, numRetried, numRejected := uint64(0), uint64(0), uint64(0)
+	args := createMockArgsReindexer(&mock.ElasticClientStub{}, destination, nil)
+	deadLetter := &deadLetterStub{}
+	args.DeadLetter = deadLetter
+	args.BulkItems.NumRetries = 1
+	args.Metrics = &mock.MetricsHandlerStub{
+		AddDocumentsWrittenCalled: func(_ string, numDocuments uint64, _ uint64) {
+			numWritten += numDocuments
+		},
+		AddRetriedItemsCalled: func(_ string, numItems uint64) {
+			numRetried += numItems
+		},
+		AddRejectedItemsCalled: func(_ string, numItems uint64) {
+			numRejected += numItems
+		},
+	}
+	r, _ := newReindexer(args)
+
+	rejectedIDs, err := r.doBulkRequests([]*bytes.Buffer{bytes.NewBufferString(testBulkBody)}, testIndex)
+	require.NoError(t, err)
+	require.Equal(t, []string{"c"}, rejectedIDs)
+	require.Len(t, requests, 2)
+	require.Equal(t, "{\"index\":{\"_id\":\"a\"}}\n{\"nonce\":1}\n", requests[1])
+
+	require.Len(t, deadLetter.documents, 1)
+	require.Equal(t, "c", deadLetter.documents[0].ID)
+	require.Equal(t, "mapper_parsing_exception", deadLetter.documents[0].ErrorType)
+	require.Equal(t, `{"nonce":3}`, string(deadLetter.documents[0].Source))
+
+	require.Equal(t, uint64(2), numWritten)
+	require.Equal(t, uint64(1), numRetried)
+	require.Equal(t, uint64(1), numRejected)
+}
+
+func TestReindexer_DoBulkRequestsShouldDeadLetterRetryableItemsAfterAllRetries(t *testing.T) {
+	numRequests := 0
+	destination := &mock.ElasticClientStub{
+		DoBulkRequestCalled: func(buff *bytes.Buffer, _ string) error {
+			numRequests++
+			return &elastic.BulkError{
+				NumItems:    len(splitBulkItems(buff.Bytes())),
+				FailedItems: []*elastic.BulkItemError{{Position: 0, ID: "a", Status: 503}},
+			}
+		},
+	}
+	args := createMockArgsReindexer(&mock.ElasticClientStub{}, destination, nil)
+	deadLetter := &deadLetterStub{}
+	args.DeadLetter = deadLetter
+	args.BulkItems.NumRetries = 2
+	r, _ := newReindexer(args)
+
+	rejectedIDs, err := r.doBulkRequests([]*bytes.Buffer{bytes.NewBufferString(testBulkBody)}, testIndex)
+	require.NoError(t, err)
+	require.Equal(t, []string{"a"}, rejectedIDs)
+	require.Equal(t, 3, numRequests)
+	require.Len(t, deadLetter.documents, 1)
+	require.Equal(t, testIndex, deadLetter.documents[0].Index)
+}
+
+func TestReindexer_DoBulkRequestsMismatchedItemsShouldErr(t *testing.T) {
+	destination := &mock.ElasticClientStub{
+		DoBulkRequestCalled: func(_ *bytes.Buffer, _ string) error {
+			return &elastic.BulkError{NumItems: 1}
+		},
+	}
+	r, _ := newReindexer(createMockArgsReindexer(&mock.ElasticClientStub{}, destination, nil))
+
+	_, err := r.doBulkRequests([]*bytes.Buffer{bytes.NewBufferString(testBulkBody)}, testIndex)
+	require.ErrorIs(t, err, errBulkItemsMismatch)
+}
@@ -0,0 +1,299 @@
+package process
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sync"
+)
+
+const checkpointFilePermissions = 0644
+
+var errIntervalNotFound = errors.New("interval not found in checkpoint")
+
+// IntervalProgress holds the reindexing progress of a timestamp interval
+type IntervalProgress struct {
+	Start         int64    `json:"start"`
+	Stop          int64    `json:"stop"`
+	LastTimestamp int64    `json:"lastTimestamp"`
+	RejectedIDs   []string `json:"rejectedIDs,omitempty"`
+	Completed     bool     `json:"completed"`
+}
+
+type indexProgress struct {
+	Completed       bool                `json:"completed"`
+	Intervals       []*IntervalProgress `json:"intervals,omitempty"`
+	FollowTimestamp int64               `json:"followTimestamp,omitempty"`
+}
+
+type checkpointData struct {
+	StopTimestamp int64                     `json:"stopTimestamp"`
+	Indices       map[string]*indexProgress `json:"indices"`
+}
+
+// fileCheckpoint keeps the reindexing progress of every index and interval and persists it into a local file
+// after each change, so a stopped run can be resumed
+type fileCheckpoint struct {
+	mut      sync.RWMutex
+	filePath string
+	data     checkpointData
+}
+
+// NewFileCheckpoint creates a new checkpoint handler backed by the provided file. If resume is set, the previous
+// progress is loaded from the file, otherwise the file is reset
+func NewFileCheckpoint(filePath string, resume bool) (*fileCheckpoint, error) {
+	if filePath == "" {
+		return nil, errors.New("empty checkpoint file path")
+	}
+
+	fc := &fileCheckpoint{
+		filePath: filePath,
+		data: checkpointData{
+			Indices: make(map[string]*indexProgress),
+		},
+	}
+
+	if !resume {
+		return fc, fc.save()
+	}
+
+	fileBytes, err := ioutil.ReadFile(filePath)
+	if os.IsNotExist(err) {
+		log.Warn("checkpoint file not found, starting from scratch", "file", filePath)
+		return fc, fc.save()
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w while reading the checkpoint file %s", err, filePath)
+	}
+
+	err = json.Unmarshal(fileBytes, &fc.data)
+	if err != nil {
+		return nil, fmt.Errorf("%w while decoding the checkpoint file %s", err, filePath)
+	}
+	if fc.data.Indices == nil {
+		fc.data.Indices = make(map[string]*indexProgress)
+	}
+
+	log.Info("resuming from checkpoint", "file", filePath, "stop timestamp", fc.data.StopTimestamp)
+
+	return fc, nil
+}
+
+// StopTimestamp returns the upper bound of the intervals used by the run that created the checkpoint
+func (fc *fileCheckpoint) StopTimestamp() int64 {
+	fc.mut.RLock()
+	defer fc.mut.RUnlock()
+
+	return fc.data.StopTimestamp
+}
+
+// SetStopTimestamp saves the upper bound of the intervals used by the current run
+func (fc *fileCheckpoint) SetStopTimestamp(timestamp int64) error {
+	fc.mut.Lock()
+	defer fc.mut.Unlock()
+
+	fc.data.StopTimestamp = timestamp
+
+	return fc.save()
+}
+
+// IsIndexStarted returns true if the provided index has any recorded progress
+func (fc *fileCheckpoint) IsIndexStarted(index string) bool {
+	fc.mut.RLock()
+	defer fc.mut.RUnlock()
+
+	_, found := fc.data.Indices[index]
+
+	return found
+}
+
+// IsIndexCompleted returns true if the provided index was entirely reindexed
+func (fc *fileCheckpoint) IsIndexCompleted(index string) bool {
+	fc.mut.RLock()
+	defer fc.mut.RUnlock()
+
+	progress, found := fc.data.Indices[index]
+
+	return found && progress.Completed
+}
+
+// MarkIndexStarted records that the reindexing of the provided index has begun
+func (fc *fileCheckpoint) MarkIndexStarted(index string) error {
+	fc.mut.Lock()
+	defer fc.mut.Unlock()
+
+	fc.getOrCreateIndexProgress(index)
+
+	return fc.save()
+}
+
+// MarkIndexCompleted records that the provided index was entirely reindexed
+func (fc *fileCheckpoint) MarkIndexCompleted(index string) error {
+	fc.mut.Lock()
+	defer fc.mut.Unlock()
+
+	fc.getOrCreateIndexProgress(index).Completed = true
+
+	return fc.save()
+}
+
+// GetIntervals returns a copy of the intervals recorded for the provided index
+func (fc *fileCheckpoint) GetIntervals(index string) []*IntervalProgress {
+	fc.mut.RLock()
+	defer fc.mut.RUnlock()
+
+	progress, found := fc.data.Indices[index]
+	if !found {
+		return nil
+	}
+
+	intervals := make([]*IntervalProgress, 0, len(progress.Intervals))
+	for _, interv := range progress.Intervals {
+		intervalCopy := *interv
+		intervals = append(intervals, &intervalCopy)
+	}
+
+	return intervals
+}
+
+// SetIntervals records the intervals used for the provided index, resetting any previous interval progress
+func (fc *fileCheckpoint) SetIntervals(index string, intervals []*IntervalProgress) error {
+	fc.mut.Lock()
+	defer fc.mut.Unlock()
+
+	progress := fc.getOrCreateIndexProgress(index)
+	progress.Intervals = make([]*IntervalProgress, 0, len(intervals))
+	for _, interv := range intervals {
+		intervalCopy := *interv
+		progress.Intervals = append(progress.Intervals, &intervalCopy)
+	}
+
+	return fc.save()
+}
+
+// UpdateIntervalProgress records the highest timestamp confirmed as written in the destination for an interval,
+// together with the IDs of the documents of the interval rejected by the destination since the previous update. The
+// IDs already recorded are ignored, since the page holding the last timestamp is read again when the interval is resumed
+func (fc *fileCheckpoint) UpdateIntervalProgress(index string, intervalIdx int, lastTimestamp int64, rejectedIDs []string) error {
+	fc.mut.Lock()
+	defer fc.mut.Unlock()
+
+	interv, err := fc.getInterval(index, intervalIdx)
+	if err != nil {
+		return err
+	}
+
+	newRejectedIDs := newIDs(interv.RejectedIDs, rejectedIDs)
+	if lastTimestamp <= interv.LastTimestamp && len(newRejectedIDs) == 0 {
+		return nil
+	}
+
+	if lastTimestamp > interv.LastTimestamp {
+		interv.LastTimestamp = lastTimestamp
+	}
+	interv.RejectedIDs = append(interv.RejectedIDs, newRejectedIDs...)
+
+	return fc.save()
+}
+
+// MarkIntervalCompleted records that an interval was entirely reindexed
+func (fc *fileCheckpoint) MarkIntervalCompleted(index string, intervalIdx int) error {
+	fc.mut.Lock()
+	defer fc.mut.Unlock()
+
+	interv, err := fc.getInterval(index, intervalIdx)
+	if err != nil {
+		return err
+	}
+
+	interv.Completed = true
+
+	return fc.save()
+}
+
+// GetFollowTimestamp returns the highest timestamp copied for the provided index while in follow mode
+func (fc *fileCheckpoint) GetFollowTimestamp(index string) int64 {
+	fc.mut.RLock()
+	defer fc.mut.RUnlock()
+
+	progress, found := fc.data.Indices[index]
+	if !found {
+		return 0
+	}
+
+	return progress.FollowTimestamp
+}
+
+// SetFollowTimestamp records the highest timestamp copied for the provided index while in follow mode
+func (fc *fileCheckpoint) SetFollowTimestamp(index string, timestamp int64) error {
+	fc.mut.Lock()
+	defer fc.mut.Unlock()
+
+	fc.getOrCreateIndexProgress(index).FollowTimestamp = timestamp
+
+	return fc.save()
+}
+
+func (fc *fileCheckpoint) getOrCreateIndexProgress(index string) *indexProgress {
+	progress, found := fc.data.Indices[index]
+	if !found {
+		progress = &indexProgress{}
+		fc.data.Indices[index] = progress
+	}
+
+	return progress
+}
+
+func (fc *fileCheckpoint) getInterval(index string, intervalIdx int) (*IntervalProgress, error) {
+	progress, found := fc.data.Indices[index]
+	if !found || intervalIdx < 0 || intervalIdx >= len(progress.Intervals) {
+		return nil, fmt.Errorf("%w, index %s, interval nr %d", errIntervalNotFound, index, intervalIdx)
+	}
+
+	return progress.Intervals[intervalIdx], nil
+}
+
+// save writes the checkpoint into a temporary file and then renames it, so a crash while writing does not
+// corrupt the previous checkpoint. Should be called under mutex protection
+func (fc *fileCheckpoint) save() error {
+	dataBytes, err := json.MarshalIndent(fc.data, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	tempFilePath := fc.filePath + ".tmp"
+	err = ioutil.WriteFile(tempFilePath, dataBytes, checkpointFilePermissions)
+	if err != nil {
+		return fmt.Errorf("%w while writing the checkpoint file %s", err, tempFilePath)
+	}
+
+	return os.Rename(tempFilePath, fc.filePath)
+}
+
+// newIDs returns the provided IDs not found in the recorded ones, without duplicates
+func newIDs(recorded []string, ids []string) []string {
+	known := make(map[string]struct{}, len(recorded)+len(ids))
+	for _, id := range recorded {
+		known[id] = struct{}{}
+	}
+
+	result := make([]string, 0)
+	for _, id := range ids {
+		_, found := known[id]
+		if found {
+			continue
+		}
+
+		known[id] = struct{}{}
+		result = append(result, id)
+	}
+
+	return result
+}
+
+// IsInterfaceNil returns true if there is no value under the interface
+func (fc *fileCheckpoint) IsInterfaceNil() bool {
+	return fc == nil
+}
@@ -0,0 +1,79 @@
+package process
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileCheckpoint(t *testing.T) {
+	t.Run("empty file path should err", testFileCheckpointEmptyPathShouldErr)
+	t.Run("resume should restore the saved progress", testFileCheckpointResumeShouldRestoreProgress)
+	t.Run("no resume should reset the saved progress", testFileCheckpointNoResumeShouldReset)
+	t.Run("unknown interval should err", testFileCheckpointUnknownIntervalShouldErr)
+}
+
+func testFileCheckpointEmptyPathShouldErr(t *testing.T) {
+	fc, err := NewFileCheckpoint("", false)
+	require.Nil(t, fc)
+	require.Error(t, err)
+}
+
+func testFileCheckpointResumeShouldRestoreProgress(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "checkpoint.json")
+
+	fc, err := NewFileCheckpoint(filePath, false)
+	require.NoError(t, err)
+	require.NoError(t, fc.SetStopTimestamp(300))
+	require.NoError(t, fc.MarkIndexCompleted("accounts"))
+	require.NoError(t, fc.SetIntervals("blocks", []*IntervalProgress{
+		{Start: 100, Stop: 200},
+		{Start: 200, Stop: 300},
+	}))
+	require.NoError(t, fc.UpdateIntervalProgress("blocks", 0, 150, []string{"a"}))
+	require.NoError(t, fc.UpdateIntervalProgress("blocks", 0, 120, []string{"a", "b"}))
+	require.NoError(t, fc.MarkIntervalCompleted("blocks", 1))
+
+	resumed, err := NewFileCheckpoint(filePath, true)
+	require.NoError(t, err)
+	require.Equal(t, int64(300), resumed.StopTimestamp())
+	require.True(t, resumed.IsIndexCompleted("accounts"))
+	require.True(t, resumed.IsIndexStarted("blocks"))
+	require.False(t, resumed.IsIndexCompleted("blocks"))
+	require.False(t, resumed.IsIndexStarted("transactions"))
+	require.Equal(t, []*IntervalProgress{
+		{Start: 100, Stop: 200, LastTimestamp: 150, RejectedIDs: []string{"a", "b"}},
+		{Start: 200, Stop: 300, Completed: true},
+	}, resumed.GetIntervals("blocks"))
+
+	// the page holding the last timestamp is read again on resume, its rejected documents should not be recorded twice
+	require.NoError(t, resumed.UpdateIntervalProgress("blocks", 0, 150, []string{"b", "c", "c"}))
+	require.Equal(t, []string{"a", "b", "c"}, resumed.GetIntervals("blocks")[0].RejectedIDs)
+}
+
+func testFileCheckpointNoResumeShouldReset(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "checkpoint.json")
+
+	fc, err := NewFileCheckpoint(filePath, false)
+	require.NoError(t, err)
+	require.NoError(t, fc.MarkIndexCompleted("accounts"))
+
+	fc, err = NewFileCheckpoint(filePath, false)
+	require.NoError(t, err)
+	require.False(t, fc.IsIndexStarted("accounts"))
+	require.Equal(t, int64(0), fc.StopTimestamp())
+}
+
+func testFileCheckpointUnknownIntervalShouldErr(t *testing.T) {
+	fc, err := NewFileCheckpoint(filepath.Join(t.TempDir(), "checkpoint.json"), false)
+	require.NoError(t, err)
+
+	err = fc.UpdateIntervalProgress("blocks", 0, 10, nil)
+	require.True(t, errors.Is(err, errIntervalNotFound))
+
+	require.NoError(t, fc.SetIntervals("blocks", []*IntervalProgress{{Start: 100, Stop: 200}}))
+	err = fc.MarkIntervalCompleted("blocks", 1)
+	require.True(t, errors.Is(err, errIntervalNotFound))
+}
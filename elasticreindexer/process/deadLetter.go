@@ -0,0 +1,163 @@
+package process
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+)
+
+const deadLetterFilePermissions = 0644
+
+// DeadLetterDocument holds a document permanently rejected by the destination, together with the rejection reason
+type DeadLetterDocument struct {
+	Index     string          `json:"index"`
+	ID        string          `json:"_id"`
+	Status    int             `json:"status"`
+	ErrorType string          `json:"errorType"`
+	Reason    string          `json:"reason"`
+	Source    json.RawMessage `json:"_source,omitempty"`
+}
+
+// fileDeadLetter appends the rejected documents, as NDJSON lines, in a local file. The file is created when the first
+// document is rejected and it is appended to by the following runs. A document is written once per index, even if it
+// is rejected again, as it happens for the documents read by two adjacent intervals or read again on resume
+type fileDeadLetter struct {
+	mut          sync.Mutex
+	filePath     string
+	file         *os.File
+	numDocuments uint64
+	writtenIDs   map[string]map[string]struct{}
+}
+
+// NewFileDeadLetter creates a new dead-letter handler backed by the provided file. On resume, the documents already
+// written in the file are not written again
+func NewFileDeadLetter(filePath string, resume bool) (*fileDeadLetter, error) {
+	if filePath == "" {
+		return nil, errors.New("empty dead-letter file path")
+	}
+
+	fdl := &fileDeadLetter{
+		filePath:   filePath,
+		writtenIDs: make(map[string]map[string]struct{}),
+	}
+	if !resume {
+		return fdl, nil
+	}
+
+	err := fdl.loadWrittenIDs()
+	if err != nil {
+		return nil, err
+	}
+
+	return fdl, nil
+}
+
+func (fdl *fileDeadLetter) loadWrittenIDs() error {
+	file, err := os.Open(fdl.filePath)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("%w while opening the dead-letter file %s", err, fdl.filePath)
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+
+	decoder := json.NewDecoder(file)
+	for decoder.More() {
+		document := &DeadLetterDocument{}
+		err = decoder.Decode(document)
+		if err != nil {
+			return fmt.Errorf("%w while reading the dead-letter file %s", err, fdl.filePath)
+		}
+
+		fdl.markWritten(document)
+	}
+
+	return nil
+}
+
+// Add writes the rejected document in the dead-letter file, if it was not already written
+func (fdl *fileDeadLetter) Add(document *DeadLetterDocument) error {
+	line, err := json.Marshal(document)
+	if err != nil {
+		return err
+	}
+
+	fdl.mut.Lock()
+	defer fdl.mut.Unlock()
+
+	if fdl.isWritten(document) {
+		log.Debug("document already in the dead-letter file", "index", document.Index, "id", document.ID)
+		return nil
+	}
+
+	if fdl.file == nil {
+		fdl.file, err = os.OpenFile(fdl.filePath, os.O_CREATE|os.O_APPEND|os.O_WRONLY, deadLetterFilePermissions)
+		if err != nil {
+			return fmt.Errorf("%w while opening the dead-letter file %s", err, fdl.filePath)
+		}
+	}
+
+	_, err = fdl.file.Write(append(line, '\n'))
+	if err != nil {
+		return fmt.Errorf("%w while writing in the dead-letter file %s", err, fdl.filePath)
+	}
+
+	fdl.markWritten(document)
+	fdl.numDocuments++
+
+	return nil
+}
+
+func (fdl *fileDeadLetter) isWritten(document *DeadLetterDocument) bool {
+	_, found := fdl.writtenIDs[document.Index][document.ID]
+
+	return found
+}
+
+func (fdl *fileDeadLetter) markWritten(document *DeadLetterDocument) {
+	ids, found := fdl.writtenIDs[document.Index]
+	if !found {
+		ids = make(map[string]struct{})
+		fdl.writtenIDs[document.Index] = ids
+	}
+
+	ids[document.ID] = struct{}{}
+}
+
+// NumDocuments returns the number of documents written in the dead-letter file by this instance
+func (fdl *fileDeadLetter) NumDocuments() uint64 {
+	fdl.mut.Lock()
+	defer fdl.mut.Unlock()
+
+	return fdl.numDocuments
+}
+
+// FilePath returns the path of the dead-letter file
+func (fdl *fileDeadLetter) FilePath() string {
+	return fdl.filePath
+}
+
+// Close closes the dead-letter file, if it was created
+func (fdl *fileDeadLetter) Close() error {
+	fdl.mut.Lock()
+	defer fdl.mut.Unlock()
+
+	if fdl.file == nil {
+		return nil
+	}
+
+	err := fdl.file.Close()
+	fdl.file = nil
+
+	return err
+}
+
+// IsInterfaceNil returns true if there is no value under the interface
+func (fdl *fileDeadLetter) IsInterfaceNil() bool {
+	return fdl == nil
+}
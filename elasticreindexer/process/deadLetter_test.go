@@ -0,0 +1,69 @@
+package process
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type deadLetterStub struct {
+	documents []*DeadLetterDocument
+}
+
+func (dls *deadLetterStub) Add(document *DeadLetterDocument) error {
+	dls.documents = append(dls.documents, document)
+	return nil
+}
+
+func (dls *deadLetterStub) IsInterfaceNil() bool {
+	return dls == nil
+}
+
+func TestFileDeadLetter(t *testing.T) {
+	fdl, err := NewFileDeadLetter("", false)
+	require.Nil(t, fdl)
+	require.Error(t, err)
+
+	filePath := filepath.Join(t.TempDir(), "dead-letter.ndjson")
+	fdl, err = NewFileDeadLetter(filePath, false)
+	require.NoError(t, err)
+
+	// the file is only created when the first document is rejected
+	require.NoError(t, fdl.Close())
+	_, err = os.Stat(filePath)
+	require.True(t, os.IsNotExist(err))
+
+	require.NoError(t, fdl.Add(&DeadLetterDocument{Index: "blocks", ID: "a", Status: 400, Source: []byte(`{"nonce":1}`)}))
+	require.NoError(t, fdl.Add(&DeadLetterDocument{Index: "blocks", ID: "b", Status: 400}))
+	require.NoError(t, fdl.Add(&DeadLetterDocument{Index: "blocks", ID: "b", Status: 400}))
+	require.Equal(t, uint64(2), fdl.NumDocuments())
+	require.NoError(t, fdl.Close())
+
+	// on resume, the documents already written should not be written again
+	fdl, err = NewFileDeadLetter(filePath, true)
+	require.NoError(t, err)
+	require.NoError(t, fdl.Add(&DeadLetterDocument{Index: "blocks", ID: "a", Status: 400}))
+	require.NoError(t, fdl.Add(&DeadLetterDocument{Index: "miniblocks", ID: "a", Status: 400}))
+	require.Equal(t, uint64(1), fdl.NumDocuments())
+	require.NoError(t, fdl.Close())
+
+	file, err := os.Open(filePath)
+	require.NoError(t, err)
+	defer func() {
+		_ = file.Close()
+	}()
+
+	lines := make([]string, 0)
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+	require.Equal(t, []string{
+		`{"index":"blocks","_id":"a","status":400,"errorType":"","reason":"","_source":{"nonce":1}}`,
+		`{"index":"blocks","_id":"b","status":400,"errorType":"","reason":""}`,
+		`{"index":"miniblocks","_id":"a","status":400,"errorType":"","reason":""}`,
+	}, lines)
+}
@@ -0,0 +1,153 @@
+package process
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/multiversx/mx-chain-tools-go/elasticreindexer/config"
+)
+
+const (
+	bulkActionIndex  = "index"
+	bulkActionCreate = "create"
+	bulkActionUpdate = "update"
+
+	idPlaceholder    = "{id}"
+	indexPlaceholder = "{index}"
+	tagPlaceholder   = "{tag}"
+)
+
+var errInvalidDocumentsConfig = errors.New("invalid documents config")
+
+type bulkActionMeta struct {
+	ID      string `json:"_id"`
+	Routing string `json:"routing,omitempty"`
+}
+
+type upsertDocument struct {
+	Doc         json.RawMessage `json:"doc"`
+	DocAsUpsert bool            `json:"doc_as_upsert"`
+}
+
+// documentsWriter builds the bulk request lines of the documents. The ids are built from the id template, so the
+// documents coming from several clusters do not collide, and the bulk action decides what happens with the documents
+// already existing in the destination: "index" replaces them, "create" skips them and "update" merges into them
+type documentsWriter struct {
+	idTemplate      string
+	bulkAction      string
+	preserveRouting bool
+	tag             string
+	perIndex        map[string]config.PerIndexConfig
+}
+
+func newDocumentsWriter(cfg config.DocumentsConfig, perIndex map[string]config.PerIndexConfig, tag string) (*documentsWriter, error) {
+	err := checkDocumentsSettings(cfg.IDTemplate, cfg.BulkAction)
+	if err != nil {
+		return nil, err
+	}
+	for index, indexCfg := range perIndex {
+		err = checkDocumentsSettings(indexCfg.IDTemplate, indexCfg.BulkAction)
+		if err != nil {
+			return nil, fmt.Errorf("%w for index %s", err, index)
+		}
+	}
+
+	bulkAction := cfg.BulkAction
+	if bulkAction == "" {
+		bulkAction = bulkActionIndex
+	}
+
+	return &documentsWriter{
+		idTemplate:      cfg.IDTemplate,
+		bulkAction:      bulkAction,
+		preserveRouting: cfg.PreserveRouting,
+		tag:             tag,
+		perIndex:        perIndex,
+	}, nil
+}
+
+func checkDocumentsSettings(idTemplate string, bulkAction string) error {
+	switch bulkAction {
+	case "", bulkActionIndex, bulkActionCreate, bulkActionUpdate:
+	default:
+		return fmt.Errorf("%w, unknown bulk action %s", errInvalidDocumentsConfig, bulkAction)
+	}
+
+	if idTemplate != "" && !strings.Contains(idTemplate, idPlaceholder) {
+		return fmt.Errorf("%w, the id template %s does not contain %s", errInvalidDocumentsConfig, idTemplate, idPlaceholder)
+	}
+
+	return nil
+}
+
+func (dw *documentsWriter) getIDTemplate(index string) string {
+	idTemplate := dw.perIndex[index].IDTemplate
+	if idTemplate == "" {
+		return dw.idTemplate
+	}
+
+	return idTemplate
+}
+
+func (dw *documentsWriter) getBulkAction(index string) string {
+	bulkAction := dw.perIndex[index].BulkAction
+	if bulkAction == "" {
+		return dw.bulkAction
+	}
+
+	return bulkAction
+}
+
+// documentID returns the id the source document will have in the destination
+func (dw *documentsWriter) documentID(index string, id string) string {
+	idTemplate := dw.getIDTemplate(index)
+	if idTemplate == "" {
+		return id
+	}
+
+	replacer := strings.NewReplacer(idPlaceholder, id, indexPlaceholder, index, tagPlaceholder, dw.tag)
+	return replacer.Replace(idTemplate)
+}
+
+// bulkLines returns the action line, ended by a new line, and the document line of the provided hit, the source being
+// already transformed
+func (dw *documentsWriter) bulkLines(index string, hit elasticHit, source json.RawMessage) ([]byte, []byte, error) {
+	meta := bulkActionMeta{
+		ID: dw.documentID(index, hit.ID),
+	}
+	if dw.preserveRouting {
+		meta.Routing = hit.Routing
+	}
+
+	bulkAction := dw.getBulkAction(index)
+	metaLine, err := json.Marshal(map[string]bulkActionMeta{bulkAction: meta})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	documentLine := []byte(source)
+	if bulkAction == bulkActionUpdate {
+		documentLine, err = json.Marshal(upsertDocument{Doc: source, DocAsUpsert: true})
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	return append(metaLine, '\n'), documentLine, nil
+}
+
+// allowsExtraDocuments returns true if the destination index may hold more documents than the source one: the "create"
+// and "update" actions keep the documents already existing in the destination and the id templates are meant for
+// writing several sources in the same destination
+func (dw *documentsWriter) allowsExtraDocuments(index string) bool {
+	return dw.getBulkAction(index) != bulkActionIndex || dw.getIDTemplate(index) != ""
+}
+
+// isSkippedItem returns true if the bulk item failed only because the document already exists and the "create" action
+// is used for the index, so the existing document has to be kept
+func (dw *documentsWriter) isSkippedItem(index string, status int) bool {
+	return status == http.StatusConflict && dw.getBulkAction(index) == bulkActionCreate
+}
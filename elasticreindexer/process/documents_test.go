@@ -0,0 +1,98 @@
+package process
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/multiversx/mx-chain-tools-go/elasticreindexer/config"
+	"github.com/multiversx/mx-chain-tools-go/elasticreindexer/elastic"
+	"github.com/multiversx/mx-chain-tools-go/elasticreindexer/process/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewDocumentsWriter(t *testing.T) {
+	dw, err := newDocumentsWriter(config.DocumentsConfig{BulkAction: "delete"}, nil, "")
+	require.Nil(t, dw)
+	require.ErrorIs(t, err, errInvalidDocumentsConfig)
+
+	dw, err = newDocumentsWriter(config.DocumentsConfig{IDTemplate: "{tag}-"}, nil, "")
+	require.Nil(t, dw)
+	require.ErrorIs(t, err, errInvalidDocumentsConfig)
+
+	dw, err = newDocumentsWriter(config.DocumentsConfig{}, map[string]config.PerIndexConfig{
+		"blocks": {BulkAction: "upsert"},
+	}, "")
+	require.Nil(t, dw)
+	require.ErrorIs(t, err, errInvalidDocumentsConfig)
+
+	dw, err = newDocumentsWriter(config.DocumentsConfig{}, nil, "")
+	require.NoError(t, err)
+	require.Equal(t, bulkActionIndex, dw.getBulkAction("blocks"))
+}
+
+func TestDocumentsWriter_BulkLines(t *testing.T) {
+	dw, _ := newDocumentsWriter(
+		config.DocumentsConfig{
+			IDTemplate:      "{tag}-{id}",
+			BulkAction:      bulkActionCreate,
+			PreserveRouting: true,
+		},
+		map[string]config.PerIndexConfig{
+			"accounts": {IDTemplate: "{id}", BulkAction: bulkActionUpdate},
+		},
+		"shard0",
+	)
+	hit := elasticHit{ID: "a\"b", Routing: "r1"}
+	source := json.RawMessage(`{"nonce":1}`)
+
+	meta, document, err := dw.bulkLines("blocks", hit, source)
+	require.NoError(t, err)
+	require.Equal(t, "{\"create\":{\"_id\":\"shard0-a\\\"b\",\"routing\":\"r1\"}}\n", string(meta))
+	require.Equal(t, `{"nonce":1}`, string(document))
+
+	meta, document, err = dw.bulkLines("accounts", elasticHit{ID: "erd1"}, source)
+	require.NoError(t, err)
+	require.Equal(t, "{\"update\":{\"_id\":\"erd1\"}}\n", string(meta))
+	require.Equal(t, `{"doc":{"nonce":1},"doc_as_upsert":true}`, string(document))
+
+	require.True(t, dw.isSkippedItem("blocks", 409))
+	require.False(t, dw.isSkippedItem("blocks", 400))
+	require.False(t, dw.isSkippedItem("accounts", 409))
+}
+
+func TestDocumentsWriter_RoutingNotPreservedByDefault(t *testing.T) {
+	dw, _ := newDocumentsWriter(config.DocumentsConfig{IDTemplate: "{index}:{id}"}, nil, "")
+
+	meta, _, err := dw.bulkLines("blocks", elasticHit{ID: "a", Routing: "r1"}, json.RawMessage(`{}`))
+	require.NoError(t, err)
+	require.Equal(t, "{\"index\":{\"_id\":\"blocks:a\"}}\n", string(meta))
+}
+
+func TestReindexer_DoBulkRequestsShouldSkipExistingDocumentsWhenCreating(t *testing.T) {
+	destination := &mock.ElasticClientStub{
+		DoBulkRequestCalled: func(_ *bytes.Buffer, _ string) error {
+			return &elastic.BulkError{
+				NumItems:    3,
+				FailedItems: []*elastic.BulkItemError{{Position: 1, ID: "b", Status: 409, Type: "version_conflict_engine_exception"}},
+			}
+		},
+	}
+	numWritten := uint64(0)
+	args := createMockArgsReindexer(&mock.ElasticClientStub{}, destination, nil)
+	deadLetter := &deadLetterStub{}
+	args.DeadLetter = deadLetter
+	args.Documents.BulkAction = bulkActionCreate
+	args.Metrics = &mock.MetricsHandlerStub{
+		AddDocumentsWrittenCalled: func(_ string, numDocuments uint64, _ uint64) {
+			numWritten += numDocuments
+		},
+	}
+	r, _ := newReindexer(args)
+
+	rejectedIDs, err := r.doBulkRequests([]*bytes.Buffer{bytes.NewBufferString(testBulkBody)}, testIndex)
+	require.NoError(t, err)
+	require.Empty(t, rejectedIDs)
+	require.Empty(t, deadLetter.documents)
+	require.Equal(t, uint64(2), numWritten)
+}
@@ -0,0 +1,264 @@
+package process
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/multiversx/mx-chain-core-go/core/check"
+	"github.com/multiversx/mx-chain-tools-go/elasticreindexer/config"
+)
+
+const (
+	mappingFileName     = "mapping.json"
+	allDocumentsFile    = "all"
+	exportFileExtension = ".ndjson"
+	gzipFileExtension   = ".gz"
+	tempFileExtension   = ".tmp"
+	exportFilesMode     = 0644
+	exportDirsMode      = 0755
+)
+
+var errEmptyDirectory = errors.New("empty directory path")
+
+// ArgsExporter is the DTO used in the NewExporter constructor function
+type ArgsExporter struct {
+	SourceElastic ElasticClientHandler
+	IndicesConfig config.IndicesConfig
+	Directory     string
+	Compress      bool
+}
+
+// exporter dumps the source documents in NDJSON files, one directory per index and, for the indices with timestamp,
+// one file per interval. Every line holds the _id and the _source of a document
+type exporter struct {
+	sourceElastic        ElasticClientHandler
+	indicesNoTimestamp   []string
+	indicesWithTimestamp []string
+	numParallelReads     int
+	blockChainStartTime  int64
+	pageSize             int
+	indicesSettings      map[string]*indexSettings
+	directory            string
+	compress             bool
+	names                indexNames
+}
+
+// NewExporter creates a new exporter instance
+func NewExporter(args ArgsExporter) (*exporter, error) {
+	if check.IfNil(args.SourceElastic) {
+		return nil, fmt.Errorf("%w for source", errNilElasticHandler)
+	}
+	if args.Directory == "" {
+		return nil, errEmptyDirectory
+	}
+
+	indicesSettings, err := createIndicesSettings(args.IndicesConfig.PerIndex)
+	if err != nil {
+		return nil, err
+	}
+
+	e := &exporter{
+		sourceElastic:       args.SourceElastic,
+		indicesNoTimestamp:  args.IndicesConfig.Indices,
+		numParallelReads:    args.IndicesConfig.WithTimestamp.NumParallelWrites,
+		blockChainStartTime: args.IndicesConfig.WithTimestamp.BlockchainStartTime,
+		pageSize:            args.IndicesConfig.PageSize,
+		indicesSettings:     indicesSettings,
+		directory:           args.Directory,
+		compress:            args.Compress,
+		names:               newIndexNames(args.IndicesConfig),
+	}
+	if args.IndicesConfig.WithTimestamp.Enabled {
+		e.indicesWithTimestamp = args.IndicesConfig.WithTimestamp.IndicesWithTimestamp
+	}
+
+	return e, nil
+}
+
+// Export will write the mapping and the documents of all the configured indices in the export directory
+func (e *exporter) Export(stopTimestamp int64) error {
+	for _, index := range e.indicesNoTimestamp {
+		if index == "" {
+			continue
+		}
+
+		err := e.exportMapping(index)
+		if err != nil {
+			return err
+		}
+
+		query := getAll(e.pageSize, getSettingsForIndex(e.indicesSettings, index).query).Bytes()
+		numDocuments, err := e.exportQuery(index, allDocumentsFile, query)
+		if err != nil {
+			return fmt.Errorf("%w while exporting index %s", err, index)
+		}
+
+		log.Info("index exported", "index", index, "num documents", numDocuments)
+	}
+
+	if len(e.indicesWithTimestamp) == 0 {
+		return nil
+	}
+
+	intervals, err := computeIntervals(e.blockChainStartTime, stopTimestamp, int64(e.numParallelReads))
+	if err != nil {
+		return err
+	}
+
+	for _, index := range e.indicesWithTimestamp {
+		if index == "" {
+			continue
+		}
+
+		err = e.exportMapping(index)
+		if err != nil {
+			return err
+		}
+
+		err = e.exportIntervals(index, intervals)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (e *exporter) exportMapping(index string) error {
+	err := os.MkdirAll(filepath.Join(e.directory, index), exportDirsMode)
+	if err != nil {
+		return fmt.Errorf("%w while creating the export directory for index %s", err, index)
+	}
+
+	mapping, err := e.sourceElastic.GetMapping(e.names.source(index))
+	if err != nil {
+		return fmt.Errorf("%w while getting the mapping of index %s", err, index)
+	}
+
+	return ioutil.WriteFile(filepath.Join(e.directory, index, mappingFileName), mapping.Bytes(), exportFilesMode)
+}
+
+func (e *exporter) exportIntervals(index string, intervals []*interval) error {
+	filterQuery := getSettingsForIndex(e.indicesSettings, index).query
+	errs := make([]error, len(intervals))
+
+	wg := &sync.WaitGroup{}
+	wg.Add(len(intervals))
+	for idx, interv := range intervals {
+		go func(idx int, interv *interval) {
+			defer wg.Done()
+
+			fileName := fmt.Sprintf("%d-%d", interv.start, interv.stop)
+			query := getWithTimestamp(interv.start, interv.stop, true, true, e.pageSize, filterQuery).Bytes()
+			numDocuments, err := e.exportQuery(index, fileName, query)
+			if err != nil {
+				errs[idx] = fmt.Errorf("%w while exporting index %s, interval %d-%d", err, index, interv.start, interv.stop)
+				return
+			}
+
+			log.Info("interval exported", "interval nr", idx, "index", index, "num documents", numDocuments)
+		}(idx, interv)
+	}
+
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (e *exporter) exportQuery(index string, fileName string, query []byte) (uint64, error) {
+	filePath := filepath.Join(e.directory, index, fileName+exportFileExtension)
+	if e.compress {
+		filePath += gzipFileExtension
+	}
+
+	// the documents are written in a temporary file, renamed only when complete, so a failed export does not leave a
+	// partial file that would be imported
+	tempFilePath := filePath + tempFileExtension
+	writer, err := createExportFile(tempFilePath, e.compress)
+	if err != nil {
+		return 0, err
+	}
+
+	numDocuments := uint64(0)
+	err = e.sourceElastic.DoScrollRequestAllDocuments(e.names.source(index), query, func(responseBytes []byte) error {
+		esResponse, errU := unmarshalEsResponse(responseBytes)
+		if errU != nil {
+			return errU
+		}
+
+		lines := &bytes.Buffer{}
+		for _, hit := range esResponse.Hits.Hits {
+			line, errM := json.Marshal(hit)
+			if errM != nil {
+				return errM
+			}
+
+			lines.Write(line)
+			lines.WriteByte('\n')
+		}
+
+		numDocuments += uint64(len(esResponse.Hits.Hits))
+		_, errW := writer.Write(lines.Bytes())
+
+		return errW
+	})
+	if err != nil {
+		_ = writer.Close()
+		_ = os.Remove(tempFilePath)
+		return 0, err
+	}
+
+	err = writer.Close()
+	if err != nil {
+		_ = os.Remove(tempFilePath)
+		return 0, err
+	}
+
+	return numDocuments, os.Rename(tempFilePath, filePath)
+}
+
+type gzipFileWriter struct {
+	*gzip.Writer
+	file *os.File
+}
+
+// Close flushes the compressed data and closes the underlying file
+func (gfw *gzipFileWriter) Close() error {
+	err := gfw.Writer.Close()
+	if err != nil {
+		_ = gfw.file.Close()
+		return err
+	}
+
+	return gfw.file.Close()
+}
+
+func createExportFile(filePath string, compress bool) (io.WriteCloser, error) {
+	file, err := os.OpenFile(filePath, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, exportFilesMode)
+	if err != nil {
+		return nil, err
+	}
+
+	if !compress {
+		return file, nil
+	}
+
+	return &gzipFileWriter{
+		Writer: gzip.NewWriter(file),
+		file:   file,
+	}, nil
+}
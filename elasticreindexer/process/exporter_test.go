@@ -0,0 +1,139 @@
+package process
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/multiversx/mx-chain-tools-go/elasticreindexer/config"
+	"github.com/multiversx/mx-chain-tools-go/elasticreindexer/process/mock"
+	"github.com/stretchr/testify/require"
+)
+
+const testMapping = `{"mappings":{"properties":{"nonce":{"type":"long"}}}}`
+
+func createExportSourceStub() *mock.ElasticClientStub {
+	return &mock.ElasticClientStub{
+		GetMappingCalled: func(_ string) (*bytes.Buffer, error) {
+			return bytes.NewBufferString(testMapping), nil
+		},
+		DoScrollRequestAllDocumentsCalled: func(_ string, _ []byte, handlerFunc func(responseBytes []byte) error) error {
+			err := handlerFunc([]byte(`{"hits":{"hits":[{"_id":"a","_source":{"nonce":1}},{"_id":"b","_source":{"nonce":2}}]}}`))
+			if err != nil {
+				return err
+			}
+
+			return handlerFunc([]byte(`{"hits":{"hits":[{"_id":"c","_source":{"nonce":3}}]}}`))
+		},
+	}
+}
+
+func TestNewExporter(t *testing.T) {
+	e, err := NewExporter(ArgsExporter{Directory: t.TempDir()})
+	require.Nil(t, e)
+	require.ErrorIs(t, err, errNilElasticHandler)
+
+	e, err = NewExporter(ArgsExporter{SourceElastic: &mock.ElasticClientStub{}})
+	require.Nil(t, e)
+	require.Equal(t, errEmptyDirectory, err)
+
+	e, err = NewExporter(ArgsExporter{SourceElastic: &mock.ElasticClientStub{}, Directory: t.TempDir()})
+	require.NotNil(t, e)
+	require.NoError(t, err)
+}
+
+func TestExporter_ExportWithTimestampShouldWriteOneFilePerInterval(t *testing.T) {
+	directory := t.TempDir()
+	cfg := config.IndicesConfig{}
+	cfg.WithTimestamp.Enabled = true
+	cfg.WithTimestamp.BlockchainStartTime = 1000
+	cfg.WithTimestamp.NumParallelWrites = 2
+	cfg.WithTimestamp.IndicesWithTimestamp = []string{testIndex}
+
+	e, _ := NewExporter(ArgsExporter{SourceElastic: createExportSourceStub(), IndicesConfig: cfg, Directory: directory})
+	require.NoError(t, e.Export(2000))
+
+	mapping, err := ioutil.ReadFile(filepath.Join(directory, testIndex, mappingFileName))
+	require.NoError(t, err)
+	require.Equal(t, testMapping, string(mapping))
+
+	files, err := getExportFiles(filepath.Join(directory, testIndex))
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		filepath.Join(directory, testIndex, "1000-1500.ndjson"),
+		filepath.Join(directory, testIndex, "1500-2000.ndjson"),
+	}, files)
+
+	content, err := ioutil.ReadFile(files[0])
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(content)), "\n")
+	require.Len(t, lines, 3)
+	hit := elasticHit{}
+	require.NoError(t, json.Unmarshal([]byte(lines[0]), &hit))
+	require.Equal(t, "a", hit.ID)
+	require.JSONEq(t, `{"nonce":1}`, string(hit.Source))
+}
+
+func TestExporter_FailedExportShouldNotLeaveAPartialFile(t *testing.T) {
+	directory := t.TempDir()
+	expectedErr := errors.New("expected error")
+	source := createExportSourceStub()
+	source.DoScrollRequestAllDocumentsCalled = func(_ string, _ []byte, handlerFunc func(responseBytes []byte) error) error {
+		err := handlerFunc([]byte(`{"hits":{"hits":[{"_id":"a","_source":{"nonce":1}}]}}`))
+		if err != nil {
+			return err
+		}
+
+		return expectedErr
+	}
+
+	cfg := config.IndicesConfig{Indices: []string{testIndex}}
+	e, _ := NewExporter(ArgsExporter{SourceElastic: source, IndicesConfig: cfg, Directory: directory})
+	require.ErrorIs(t, e.Export(0), expectedErr)
+
+	entries, err := ioutil.ReadDir(filepath.Join(directory, testIndex))
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	require.Equal(t, mappingFileName, entries[0].Name())
+}
+
+func TestExportImport_CompressedRoundTrip(t *testing.T) {
+	directory := t.TempDir()
+	cfg := config.IndicesConfig{Indices: []string{testIndex}}
+	e, _ := NewExporter(ArgsExporter{SourceElastic: createExportSourceStub(), IndicesConfig: cfg, Directory: directory, Compress: true})
+	require.NoError(t, e.Export(0))
+
+	files, _ := getExportFiles(filepath.Join(directory, testIndex))
+	require.Equal(t, []string{filepath.Join(directory, testIndex, "all.ndjson.gz")}, files)
+
+	var createdMapping string
+	bulkBody := &bytes.Buffer{}
+	destination := &mock.ElasticClientStub{
+		CreateIndexWithMappingCalled: func(targetIndex string, body *bytes.Buffer) error {
+			require.Equal(t, testIndex+indexSuffix, targetIndex)
+			createdMapping = body.String()
+			return nil
+		},
+		DoBulkRequestCalled: func(buff *bytes.Buffer, _ string) error {
+			bulkBody.Write(buff.Bytes())
+			return nil
+		},
+	}
+	// the import does not need a source
+	args := createMockArgsReindexer(nil, destination, nil)
+	args.PageSize = 2
+	r, err := newImporter(args)
+	require.NoError(t, err)
+
+	numDocuments, err := r.ImportIndex(directory, testIndex, false, false)
+	require.NoError(t, err)
+	require.Equal(t, uint64(3), numDocuments)
+	require.Equal(t, testMapping, createdMapping)
+	for _, id := range []string{"a", "b", "c"} {
+		require.Contains(t, bulkBody.String(), `{"index":{"_id":"`+id+`"}}`)
+	}
+}
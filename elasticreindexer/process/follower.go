@@ -0,0 +1,145 @@
+package process
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/multiversx/mx-chain-core-go/core/check"
+	"github.com/multiversx/mx-chain-tools-go/elasticreindexer/config"
+)
+
+type follower struct {
+	reindexerClient     ReindexerHandler
+	checkpoint          CheckpointHandler
+	indices             []string
+	blockChainStartTime int64
+	pollInterval        time.Duration
+	overlap             int64
+	getTimeHandler      func() time.Time
+}
+
+// NewFollower creates a component able to keep the destination in sync with the source by periodically reindexing
+// the documents newer than the highest timestamp already copied, for all the indices with timestamp
+func NewFollower(reindexer ReindexerHandler, cfg config.IndicesConfig, checkpoint CheckpointHandler) (*follower, error) {
+	if reindexer == nil {
+		return nil, errors.New("nil ReindexerHandler")
+	}
+	if check.IfNil(checkpoint) {
+		return nil, errors.New("nil CheckpointHandler")
+	}
+	if cfg.WithTimestamp.BlockchainStartTime <= 0 {
+		return nil, errors.New("blockchainStartTime cannot be less than zero")
+	}
+	if cfg.Follow.PollIntervalInSeconds <= 0 {
+		return nil, errors.New("follow pollIntervalInSeconds should be greater than zero")
+	}
+	if cfg.Follow.OverlapInSeconds < 0 {
+		return nil, errors.New("follow overlapInSeconds cannot be negative")
+	}
+
+	return &follower{
+		reindexerClient:     reindexer,
+		checkpoint:          checkpoint,
+		indices:             cfg.WithTimestamp.IndicesWithTimestamp,
+		blockChainStartTime: cfg.WithTimestamp.BlockchainStartTime,
+		pollInterval:        time.Duration(cfg.Follow.PollIntervalInSeconds) * time.Second,
+		overlap:             cfg.Follow.OverlapInSeconds,
+		getTimeHandler:      time.Now,
+	}, nil
+}
+
+// Follow will reindex the new documents of every index with timestamp, in rounds, until the context is done
+func (f *follower) Follow(ctx context.Context, skipMappings bool) error {
+	for _, index := range f.indices {
+		if index == "" {
+			continue
+		}
+
+		// in follow mode the destination index is expected to exist already
+		err := f.reindexerClient.CopyMappingIfNecessary(index, true, skipMappings)
+		if err != nil {
+			return fmt.Errorf("%w while copying the mapping for index %s", err, index)
+		}
+	}
+
+	for {
+		f.doRound()
+
+		select {
+		case <-ctx.Done():
+			log.Info("follow mode stopped")
+			return nil
+		case <-time.After(f.pollInterval):
+		}
+	}
+}
+
+func (f *follower) doRound() {
+	for _, index := range f.indices {
+		if index == "" {
+			continue
+		}
+
+		err := f.followIndex(index)
+		if err != nil {
+			log.Warn("cannot follow index", "index", index, "error", err)
+		}
+	}
+}
+
+func (f *follower) followIndex(index string) error {
+	lastTimestamp := f.getLastTimestamp(index)
+	start := lastTimestamp - f.overlap
+	if start < f.blockChainStartTime {
+		start = f.blockChainStartTime
+	}
+	stop := f.getTimeHandler().Unix()
+
+	count := uint64(0)
+	highestTimestamp := lastTimestamp
+	progressHandler := func(timestamp int64, _ []string) error {
+		if timestamp > highestTimestamp {
+			highestTimestamp = timestamp
+		}
+
+		return nil
+	}
+
+	err := f.reindexerClient.ProcessIndexWithTimestamp(index, start, stop, &count, progressHandler)
+	if err != nil {
+		return err
+	}
+
+	if highestTimestamp > lastTimestamp {
+		err = f.checkpoint.SetFollowTimestamp(index, highestTimestamp)
+		if err != nil {
+			return err
+		}
+	}
+
+	log.Info("index followed",
+		"index", index,
+		"window start", start,
+		"window stop", stop,
+		"num pages", count,
+		"highest timestamp", highestTimestamp,
+		"lag in seconds", stop-highestTimestamp)
+
+	return nil
+}
+
+// getLastTimestamp returns the highest timestamp known to be copied for the provided index. If the index was never
+// followed, but it was entirely reindexed, the end of the reindexing intervals is used
+func (f *follower) getLastTimestamp(index string) int64 {
+	followTimestamp := f.checkpoint.GetFollowTimestamp(index)
+	if followTimestamp > 0 {
+		return followTimestamp
+	}
+	if f.checkpoint.IsIndexCompleted(index) {
+		return f.checkpoint.StopTimestamp()
+	}
+
+	return f.blockChainStartTime
+}
@@ -0,0 +1,120 @@
+package process
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/multiversx/mx-chain-tools-go/elasticreindexer/config"
+	"github.com/multiversx/mx-chain-tools-go/elasticreindexer/process/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func createFollowConfig() config.IndicesConfig {
+	cfg := config.IndicesConfig{}
+	cfg.WithTimestamp.BlockchainStartTime = 1000
+	cfg.WithTimestamp.IndicesWithTimestamp = []string{testIndex}
+	cfg.Follow.PollIntervalInSeconds = 1
+	cfg.Follow.OverlapInSeconds = 50
+
+	return cfg
+}
+
+func TestNewFollower(t *testing.T) {
+	checkpoint, _ := NewFileCheckpoint(filepath.Join(t.TempDir(), "checkpoint.json"), false)
+
+	cfg := createFollowConfig()
+	cfg.Follow.PollIntervalInSeconds = 0
+	f, err := NewFollower(&mock.ReindexerHandlerStub{}, cfg, checkpoint)
+	require.Nil(t, f)
+	require.Error(t, err)
+
+	cfg = createFollowConfig()
+	cfg.Follow.OverlapInSeconds = -1
+	f, err = NewFollower(&mock.ReindexerHandlerStub{}, cfg, checkpoint)
+	require.Nil(t, f)
+	require.Error(t, err)
+
+	f, err = NewFollower(&mock.ReindexerHandlerStub{}, createFollowConfig(), nil)
+	require.Nil(t, f)
+	require.Error(t, err)
+
+	f, err = NewFollower(&mock.ReindexerHandlerStub{}, createFollowConfig(), checkpoint)
+	require.NotNil(t, f)
+	require.NoError(t, err)
+}
+
+func TestFollower_FollowIndex(t *testing.T) {
+	t.Run("never reindexed index should start from the blockchain start time", func(t *testing.T) {
+		var windowStart, windowStop int64
+		reindexerStub := &mock.ReindexerHandlerStub{
+			ProcessIndexWithTimestampCalled: func(_ string, start, stop int64, _ *uint64, progressHandler func(int64, []string) error) error {
+				windowStart, windowStop = start, stop
+				return progressHandler(1500, nil)
+			},
+		}
+		checkpoint, _ := NewFileCheckpoint(filepath.Join(t.TempDir(), "checkpoint.json"), false)
+		f, _ := NewFollower(reindexerStub, createFollowConfig(), checkpoint)
+		f.getTimeHandler = func() time.Time {
+			return time.Unix(2000, 0)
+		}
+
+		require.NoError(t, f.followIndex(testIndex))
+		require.Equal(t, int64(1000), windowStart)
+		require.Equal(t, int64(2000), windowStop)
+		require.Equal(t, int64(1500), checkpoint.GetFollowTimestamp(testIndex))
+	})
+	t.Run("completed index should continue from the checkpoint stop timestamp with overlap", func(t *testing.T) {
+		var windowStart int64
+		reindexerStub := &mock.ReindexerHandlerStub{
+			ProcessIndexWithTimestampCalled: func(_ string, start, _ int64, _ *uint64, _ func(int64, []string) error) error {
+				windowStart = start
+				return nil
+			},
+		}
+		checkpoint, _ := NewFileCheckpoint(filepath.Join(t.TempDir(), "checkpoint.json"), false)
+		_ = checkpoint.SetStopTimestamp(1800)
+		_ = checkpoint.MarkIndexCompleted(testIndex)
+		f, _ := NewFollower(reindexerStub, createFollowConfig(), checkpoint)
+
+		require.NoError(t, f.followIndex(testIndex))
+		require.Equal(t, int64(1750), windowStart)
+		// no new document was copied, so the follow timestamp is not changed
+		require.Equal(t, int64(0), checkpoint.GetFollowTimestamp(testIndex))
+	})
+	t.Run("followed index should continue from the follow timestamp with overlap", func(t *testing.T) {
+		var windowStart int64
+		reindexerStub := &mock.ReindexerHandlerStub{
+			ProcessIndexWithTimestampCalled: func(_ string, start, _ int64, _ *uint64, _ func(int64, []string) error) error {
+				windowStart = start
+				return nil
+			},
+		}
+		checkpoint, _ := NewFileCheckpoint(filepath.Join(t.TempDir(), "checkpoint.json"), false)
+		_ = checkpoint.SetFollowTimestamp(testIndex, 1900)
+		f, _ := NewFollower(reindexerStub, createFollowConfig(), checkpoint)
+
+		require.NoError(t, f.followIndex(testIndex))
+		require.Equal(t, int64(1850), windowStart)
+	})
+}
+
+func TestFollower_FollowShouldStopWhenContextIsDone(t *testing.T) {
+	numRounds := 0
+	reindexerStub := &mock.ReindexerHandlerStub{
+		ProcessIndexWithTimestampCalled: func(_ string, _, _ int64, _ *uint64, _ func(int64, []string) error) error {
+			numRounds++
+			return nil
+		},
+	}
+	checkpoint, _ := NewFileCheckpoint(filepath.Join(t.TempDir(), "checkpoint.json"), false)
+	f, _ := NewFollower(reindexerStub, createFollowConfig(), checkpoint)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err := f.Follow(ctx, false)
+	require.NoError(t, err)
+	require.Equal(t, 1, numRounds)
+}
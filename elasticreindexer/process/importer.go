@@ -0,0 +1,164 @@
+package process
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	defaultImportBatchSize = 1000
+	maxImportLineSize      = 100 * 1024 * 1024
+)
+
+// ImportIndex creates, if necessary, the index and its alias in the destination using the exported mapping and writes
+// all the documents from the export files of the index. It returns the number of imported documents
+func (r *reindexer) ImportIndex(directory string, index string, overwrite bool, skipMappings bool) (uint64, error) {
+	indexDirectory := filepath.Join(directory, index)
+	getMapping := func() (*bytes.Buffer, error) {
+		mapping, err := ioutil.ReadFile(filepath.Join(indexDirectory, mappingFileName))
+		if err != nil {
+			return nil, err
+		}
+
+		return bytes.NewBuffer(mapping), nil
+	}
+
+	err := r.createIndexAndAliasIfNecessary(index, overwrite, skipMappings, getMapping)
+	if err != nil {
+		return 0, fmt.Errorf("%w while creating the index %s", err, index)
+	}
+
+	files, err := getExportFiles(indexDirectory)
+	if err != nil {
+		return 0, err
+	}
+
+	numDocuments := uint64(0)
+	for _, file := range files {
+		numFileDocuments, errImport := r.importFile(index, file)
+		if errImport != nil {
+			return numDocuments, fmt.Errorf("%w while importing file %s", errImport, file)
+		}
+
+		numDocuments += numFileDocuments
+		log.Info("file imported", "index", index, "file", file, "num documents", numFileDocuments)
+	}
+
+	return numDocuments, nil
+}
+
+func getExportFiles(indexDirectory string) ([]string, error) {
+	entries, err := ioutil.ReadDir(indexDirectory)
+	if err != nil {
+		return nil, err
+	}
+
+	files := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+
+		name := entry.Name()
+		if strings.HasSuffix(name, exportFileExtension) || strings.HasSuffix(name, exportFileExtension+gzipFileExtension) {
+			files = append(files, filepath.Join(indexDirectory, name))
+		}
+	}
+
+	return files, nil
+}
+
+func (r *reindexer) importFile(index string, filePath string) (uint64, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return 0, err
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+
+	var reader io.Reader = file
+	if strings.HasSuffix(filePath, gzipFileExtension) {
+		gzipReader, errGzip := gzip.NewReader(file)
+		if errGzip != nil {
+			return 0, errGzip
+		}
+		defer func() {
+			_ = gzipReader.Close()
+		}()
+
+		reader = gzipReader
+	}
+
+	batchSize := r.pageSize
+	if batchSize == 0 {
+		batchSize = defaultImportBatchSize
+	}
+
+	scanner := bufio.NewScanner(reader)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxImportLineSize)
+
+	numDocuments := uint64(0)
+	count := 0
+	batch := &generalElasticResponse{}
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+
+		hit := elasticHit{}
+		err = json.Unmarshal(line, &hit)
+		if err != nil {
+			return numDocuments, fmt.Errorf("%w on line %d", err, lineNumber)
+		}
+
+		batch.Hits.Hits = append(batch.Hits.Hits, hit)
+		if len(batch.Hits.Hits) < batchSize {
+			continue
+		}
+
+		count++
+		err = r.importBatch(batch, index, count)
+		if err != nil {
+			return numDocuments, err
+		}
+
+		numDocuments += uint64(len(batch.Hits.Hits))
+		batch = &generalElasticResponse{}
+	}
+	if scanner.Err() != nil {
+		return numDocuments, scanner.Err()
+	}
+
+	if len(batch.Hits.Hits) == 0 {
+		return numDocuments, nil
+	}
+
+	count++
+	err = r.importBatch(batch, index, count)
+	if err != nil {
+		return numDocuments, err
+	}
+
+	return numDocuments + uint64(len(batch.Hits.Hits)), nil
+}
+
+func (r *reindexer) importBatch(batch *generalElasticResponse, index string, count int) error {
+	dataBuffers, err := r.prepareDataForIndexing(batch, index, count)
+	if err != nil {
+		return fmt.Errorf("%w while preparing data for indexing", err)
+	}
+
+	_, err = r.doBulkRequests(dataBuffers, index)
+
+	return err
+}
@@ -0,0 +1,37 @@
+package process
+
+import "github.com/multiversx/mx-chain-tools-go/elasticreindexer/config"
+
+// indexNames maps the configured index names to the names used in the source and in the destination clusters, which
+// can be namespaced with a prefix (e.g. "devnet-transactions") or, for the destination, replaced with a concrete index
+type indexNames struct {
+	sourcePrefix       string
+	destinationPrefix  string
+	destinationIndices map[string]string
+}
+
+func newIndexNames(indicesConfig config.IndicesConfig) indexNames {
+	return indexNames{
+		sourcePrefix:       indicesConfig.SourceIndexPrefix,
+		destinationPrefix:  indicesConfig.DestinationIndexPrefix,
+		destinationIndices: indicesConfig.DestinationIndices,
+	}
+}
+
+func (in indexNames) source(index string) string {
+	return in.sourcePrefix + index
+}
+
+func (in indexNames) destination(index string) string {
+	destinationIndex, found := in.destinationIndices[index]
+	if found {
+		return destinationIndex
+	}
+
+	return in.destinationPrefix + index
+}
+
+// alias returns the name of the destination alias of the index, regardless of the concrete index it is written in
+func (in indexNames) alias(index string) string {
+	return in.destinationPrefix + index
+}
@@ -0,0 +1,24 @@
+package process
+
+import "errors"
+
+var errCountsMismatch = errors.New("source and destination counts are not equal")
+
+// IndexResult holds the outcome of the reindexing of an index with timestamp
+type IndexResult struct {
+	Index               string
+	IntervalsSucceeded  int
+	IntervalsFailed     int
+	SourceCount         uint64
+	DestinationCount    uint64
+	RejectedCount       uint64
+	AllowExtraDocuments bool
+	Errors              []error
+}
+
+// IsSuccessful returns true if all the intervals were reindexed and the destination holds all the source documents,
+// except the ones rejected by the destination. The destination can hold more documents if extra documents are allowed
+func (ir *IndexResult) IsSuccessful() bool {
+	return ir.IntervalsFailed == 0 && len(ir.Errors) == 0 &&
+		areCountsMatching(ir.SourceCount, ir.DestinationCount, ir.RejectedCount, ir.AllowExtraDocuments)
+}
@@ -0,0 +1,261 @@
+package process
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/multiversx/mx-chain-tools-go/elasticreindexer/config"
+)
+
+const timestampField = "timestamp"
+
+var errInvalidIndexSettings = errors.New("invalid index settings")
+
+// fieldsKeyedClauses are the query clauses keyed by the names of the fields they filter on
+var fieldsKeyedClauses = map[string]struct{}{
+	"term":                {},
+	"terms":               {},
+	"range":               {},
+	"match":               {},
+	"match_phrase":        {},
+	"match_phrase_prefix": {},
+	"prefix":              {},
+	"wildcard":            {},
+	"regexp":              {},
+	"fuzzy":               {},
+}
+
+// indexSettings holds the per-index query filter and fields transformations
+type indexSettings struct {
+	query       json.RawMessage
+	transformer *fieldsTransformer
+}
+
+func newIndexSettings(cfg config.PerIndexConfig) (*indexSettings, error) {
+	settings := &indexSettings{}
+
+	query := bytes.TrimSpace([]byte(cfg.Query))
+	if len(query) > 0 {
+		if !json.Valid(query) || query[0] != '{' {
+			return nil, fmt.Errorf("%w, the query should be a JSON object: %s", errInvalidIndexSettings, cfg.Query)
+		}
+
+		settings.query = query
+	}
+
+	transformer, err := newFieldsTransformer(cfg)
+	if err != nil {
+		return nil, err
+	}
+	// the documents are counted with the same query in the destination, so the query fields have to be kept
+	for _, field := range getQueryFields(settings.query) {
+		if transformer.changesField(field) {
+			return nil, fmt.Errorf("%w, the query uses the field %s changed by the fields transformations", errInvalidIndexSettings, field)
+		}
+	}
+	if transformer.isActive() {
+		settings.transformer = transformer
+	}
+
+	return settings, nil
+}
+
+func createIndicesSettings(cfg map[string]config.PerIndexConfig) (map[string]*indexSettings, error) {
+	indicesSettings := make(map[string]*indexSettings, len(cfg))
+	for index, indexCfg := range cfg {
+		settings, err := newIndexSettings(indexCfg)
+		if err != nil {
+			return nil, fmt.Errorf("%w for index %s", err, index)
+		}
+
+		indicesSettings[index] = settings
+	}
+
+	return indicesSettings, nil
+}
+
+// checkTimestampFieldKept returns an error if the fields transformations of an index with timestamp change the
+// timestamp field, as the destination documents of the intervals are counted by this field
+func checkTimestampFieldKept(cfg config.IndicesConfig) error {
+	if !cfg.WithTimestamp.Enabled {
+		return nil
+	}
+
+	for _, index := range cfg.WithTimestamp.IndicesWithTimestamp {
+		transformer, err := newFieldsTransformer(cfg.PerIndex[index])
+		if err != nil {
+			return fmt.Errorf("%w for index %s", err, index)
+		}
+		if transformer.changesField(timestampField) {
+			return fmt.Errorf("%w for index %s, the field %s is changed by the fields transformations",
+				errInvalidIndexSettings, index, timestampField)
+		}
+	}
+
+	return nil
+}
+
+// getQueryFields returns the sorted names of the fields the provided query filters on
+func getQueryFields(query json.RawMessage) []string {
+	if len(query) == 0 {
+		return nil
+	}
+
+	var decoded interface{}
+	err := json.Unmarshal(query, &decoded)
+	if err != nil {
+		return nil
+	}
+
+	fields := make(map[string]struct{})
+	collectQueryFields(decoded, fields)
+
+	sortedFields := make([]string, 0, len(fields))
+	for field := range fields {
+		sortedFields = append(sortedFields, field)
+	}
+	sort.Strings(sortedFields)
+
+	return sortedFields
+}
+
+func collectQueryFields(value interface{}, fields map[string]struct{}) {
+	switch typedValue := value.(type) {
+	case []interface{}:
+		for _, element := range typedValue {
+			collectQueryFields(element, fields)
+		}
+	case map[string]interface{}:
+		for key, child := range typedValue {
+			field, isString := child.(string)
+			if key == "field" && isString {
+				fields[field] = struct{}{}
+				continue
+			}
+
+			clause, isObject := child.(map[string]interface{})
+			_, isFieldsKeyed := fieldsKeyedClauses[key]
+			if !isObject || !isFieldsKeyed {
+				collectQueryFields(child, fields)
+				continue
+			}
+
+			for clauseField := range clause {
+				if clauseField != "boost" && clauseField != "_name" {
+					fields[clauseField] = struct{}{}
+				}
+			}
+		}
+	}
+}
+
+// getSettingsForIndex returns the settings of the provided index or empty settings if the index has none configured
+func getSettingsForIndex(indicesSettings map[string]*indexSettings, index string) *indexSettings {
+	settings, found := indicesSettings[index]
+	if !found {
+		return &indexSettings{}
+	}
+
+	return settings
+}
+
+// transformSource applies the configured fields transformations, if any, on the provided document source
+func (is *indexSettings) transformSource(source json.RawMessage) (json.RawMessage, error) {
+	if is.transformer == nil {
+		return source, nil
+	}
+
+	return is.transformer.transform(source)
+}
+
+// fieldsTransformer applies, in order, the includes, the excludes and the renames on the top level fields of a document
+type fieldsTransformer struct {
+	includeFields map[string]struct{}
+	excludeFields map[string]struct{}
+	renameFields  []config.RenameFieldConfig
+}
+
+func newFieldsTransformer(cfg config.PerIndexConfig) (*fieldsTransformer, error) {
+	ft := &fieldsTransformer{
+		includeFields: make(map[string]struct{}, len(cfg.IncludeFields)),
+		excludeFields: make(map[string]struct{}, len(cfg.ExcludeFields)),
+		renameFields:  cfg.RenameFields,
+	}
+
+	for _, field := range cfg.IncludeFields {
+		ft.includeFields[field] = struct{}{}
+	}
+	for _, field := range cfg.ExcludeFields {
+		ft.excludeFields[field] = struct{}{}
+	}
+	for _, rename := range cfg.RenameFields {
+		if rename.From == "" || rename.To == "" {
+			return nil, fmt.Errorf("%w, empty field name in rename %s -> %s", errInvalidIndexSettings, rename.From, rename.To)
+		}
+	}
+
+	return ft, nil
+}
+
+func (ft *fieldsTransformer) isActive() bool {
+	return len(ft.includeFields) > 0 || len(ft.excludeFields) > 0 || len(ft.renameFields) > 0
+}
+
+// changesField returns true if the provided top level field is dropped or renamed by the transformations
+func (ft *fieldsTransformer) changesField(field string) bool {
+	if len(ft.includeFields) > 0 {
+		_, included := ft.includeFields[field]
+		if !included {
+			return true
+		}
+	}
+
+	_, excluded := ft.excludeFields[field]
+	if excluded {
+		return true
+	}
+
+	for _, rename := range ft.renameFields {
+		if rename.From == field {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (ft *fieldsTransformer) transform(source json.RawMessage) (json.RawMessage, error) {
+	fields := make(map[string]json.RawMessage)
+	err := json.Unmarshal(source, &fields)
+	if err != nil {
+		return nil, fmt.Errorf("%w while decoding the document source", err)
+	}
+
+	if len(ft.includeFields) > 0 {
+		for field := range fields {
+			_, included := ft.includeFields[field]
+			if !included {
+				delete(fields, field)
+			}
+		}
+	}
+
+	for field := range ft.excludeFields {
+		delete(fields, field)
+	}
+
+	for _, rename := range ft.renameFields {
+		value, found := fields[rename.From]
+		if !found {
+			continue
+		}
+
+		delete(fields, rename.From)
+		fields[rename.To] = value
+	}
+
+	return json.Marshal(fields)
+}
@@ -0,0 +1,130 @@
+package process
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/multiversx/mx-chain-tools-go/elasticreindexer/config"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewIndexSettings(t *testing.T) {
+	t.Run("invalid query should err", func(t *testing.T) {
+		settings, err := newIndexSettings(config.PerIndexConfig{Query: `{"term": `})
+		require.Nil(t, settings)
+		require.True(t, errors.Is(err, errInvalidIndexSettings))
+
+		settings, err = newIndexSettings(config.PerIndexConfig{Query: `["term"]`})
+		require.Nil(t, settings)
+		require.True(t, errors.Is(err, errInvalidIndexSettings))
+	})
+	t.Run("empty rename should err", func(t *testing.T) {
+		settings, err := newIndexSettings(config.PerIndexConfig{
+			RenameFields: []config.RenameFieldConfig{{From: "a"}},
+		})
+		require.Nil(t, settings)
+		require.True(t, errors.Is(err, errInvalidIndexSettings))
+	})
+	t.Run("query on a transformed field should err", func(t *testing.T) {
+		query := `{"bool":{"must":[{"term":{"senderShard":1}},{"exists":{"field":"data"}}]}}`
+
+		settings, err := newIndexSettings(config.PerIndexConfig{Query: query, ExcludeFields: []string{"data"}})
+		require.Nil(t, settings)
+		require.ErrorIs(t, err, errInvalidIndexSettings)
+		require.Contains(t, err.Error(), "field data")
+
+		settings, err = newIndexSettings(config.PerIndexConfig{
+			Query:        query,
+			RenameFields: []config.RenameFieldConfig{{From: "senderShard", To: "shard"}},
+		})
+		require.Nil(t, settings)
+		require.ErrorIs(t, err, errInvalidIndexSettings)
+
+		settings, err = newIndexSettings(config.PerIndexConfig{Query: query, IncludeFields: []string{"data", "bool"}})
+		require.Nil(t, settings)
+		require.ErrorIs(t, err, errInvalidIndexSettings)
+		require.Contains(t, err.Error(), "field senderShard")
+
+		settings, err = newIndexSettings(config.PerIndexConfig{Query: query, IncludeFields: []string{"data", "senderShard"}})
+		require.NotNil(t, settings)
+		require.NoError(t, err)
+	})
+	t.Run("no transformations should keep the source", func(t *testing.T) {
+		settings, err := newIndexSettings(config.PerIndexConfig{})
+		require.NoError(t, err)
+		require.Nil(t, settings.query)
+		require.Nil(t, settings.transformer)
+
+		source := json.RawMessage(`{"b":1, "a":2}`)
+		transformed, err := settings.transformSource(source)
+		require.NoError(t, err)
+		require.Equal(t, source, transformed)
+	})
+}
+
+func TestCheckTimestampFieldKept(t *testing.T) {
+	cfg := config.IndicesConfig{
+		PerIndex: map[string]config.PerIndexConfig{
+			"blocks":   {RenameFields: []config.RenameFieldConfig{{From: "timestamp", To: "time"}}},
+			"accounts": {ExcludeFields: []string{"data"}},
+		},
+	}
+	cfg.WithTimestamp.Enabled = true
+	cfg.WithTimestamp.IndicesWithTimestamp = []string{"accounts"}
+	require.NoError(t, checkTimestampFieldKept(cfg))
+
+	cfg.WithTimestamp.IndicesWithTimestamp = []string{"accounts", "blocks"}
+	err := checkTimestampFieldKept(cfg)
+	require.ErrorIs(t, err, errInvalidIndexSettings)
+	require.Contains(t, err.Error(), "index blocks")
+
+	cfg.WithTimestamp.Enabled = false
+	require.NoError(t, checkTimestampFieldKept(cfg))
+}
+
+func TestIndexSettings_TransformSource(t *testing.T) {
+	source := json.RawMessage(`{"sender":"erd1","receiver":"erd2","data":"aGVsbG8=","nonce":5}`)
+
+	t.Run("include fields", func(t *testing.T) {
+		settings, _ := newIndexSettings(config.PerIndexConfig{IncludeFields: []string{"sender", "nonce", "missing"}})
+
+		transformed, err := settings.transformSource(source)
+		require.NoError(t, err)
+		require.JSONEq(t, `{"sender":"erd1","nonce":5}`, string(transformed))
+	})
+	t.Run("exclude and rename fields", func(t *testing.T) {
+		settings, _ := newIndexSettings(config.PerIndexConfig{
+			ExcludeFields: []string{"data"},
+			RenameFields: []config.RenameFieldConfig{
+				{From: "sender", To: "from"},
+				{From: "missing", To: "other"},
+			},
+		})
+
+		transformed, err := settings.transformSource(source)
+		require.NoError(t, err)
+		require.JSONEq(t, `{"from":"erd1","receiver":"erd2","nonce":5}`, string(transformed))
+	})
+	t.Run("invalid source should err", func(t *testing.T) {
+		settings, _ := newIndexSettings(config.PerIndexConfig{ExcludeFields: []string{"data"}})
+
+		_, err := settings.transformSource(json.RawMessage(`[1, 2]`))
+		require.Error(t, err)
+	})
+}
+
+func TestGetWithTimestamp_WithFilterQuery(t *testing.T) {
+	filter := json.RawMessage(`{"term":{"senderShard":1}}`)
+
+	query := getWithTimestamp(10, 20, true, true, 100, filter)
+	require.JSONEq(t, `{
+		"query": {"bool": {"filter": [{"range": {"timestamp": {"gte": 10, "lte": 20}}}, {"term": {"senderShard": 1}}]}},
+		"sort": [{"timestamp": {"order": "asc"}}],
+		"_source": true,
+		"size": 100
+	}`, query.String())
+
+	query = getAll(0, filter)
+	require.JSONEq(t, `{"query": {"bool": {"filter": [{"term": {"senderShard": 1}}]}}}`, query.String())
+}
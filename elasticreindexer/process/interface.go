@@ -1,6 +1,10 @@
 package process
 
-import "bytes"
+import (
+	"bytes"
+
+	"github.com/multiversx/mx-chain-tools-go/elasticreindexer/elastic"
+)
 
 // ElasticClientHandler defines the behaviour of an elastic search client handler
 type ElasticClientHandler interface {
@@ -13,16 +17,83 @@ type ElasticClientHandler interface {
 	) error
 	GetCount(index string) (uint64, error)
 	GetCountWithBody(index string, body []byte) (uint64, error)
+	GetStoreSize(index string) (uint64, error)
 	DoesAliasExist(alias string) bool
 	DoBulkRequest(buff *bytes.Buffer, index string) error
 	DoesIndexExist(index string) bool
 	PutAlias(index string, alias string) error
+	RefreshIndex(index string) error
 	IsInterfaceNil() bool
 }
 
+// AliasClientHandler defines the behaviour of an elastic search client handler able to manage the generations of
+// concrete indices behind an alias
+type AliasClientHandler interface {
+	ElasticClientHandler
+	GetIndicesNames(pattern string) ([]string, error)
+	GetAliasIndices(alias string) ([]string, error)
+	SwapAliases(swaps []elastic.AliasSwap) error
+}
+
 // ReindexerHandler defines the behaviour of an reindexer handler
 type ReindexerHandler interface {
 	Process(overwrite bool, skipMappings bool, indices ...string) error
-	ProcessIndexWithTimestamp(index string, overwrite bool, skipMappings bool, start, stop int64, count *uint64) error
+	CopyMappingIfNecessary(index string, overwrite bool, skipMappings bool) error
+	ProcessIndexWithTimestamp(
+		index string,
+		start, stop int64,
+		count *uint64,
+		progressHandler func(lastTimestamp int64, rejectedIDs []string) error,
+	) error
 	GetCountsForInterval(index string, start, stop int64) (uint64, uint64, error)
+	GetSourcesBoundaries(start, stop int64) []int64
+}
+
+// IndexImporter defines the behaviour of a component able to import the exported files of an index in the destination
+type IndexImporter interface {
+	ImportIndex(directory string, index string, overwrite bool, skipMappings bool) (uint64, error)
+}
+
+// CheckpointHandler defines the behaviour of a component able to persist the reindexing progress
+type CheckpointHandler interface {
+	StopTimestamp() int64
+	SetStopTimestamp(timestamp int64) error
+	IsIndexStarted(index string) bool
+	IsIndexCompleted(index string) bool
+	MarkIndexStarted(index string) error
+	MarkIndexCompleted(index string) error
+	GetIntervals(index string) []*IntervalProgress
+	SetIntervals(index string, intervals []*IntervalProgress) error
+	UpdateIntervalProgress(index string, intervalIdx int, lastTimestamp int64, rejectedIDs []string) error
+	MarkIntervalCompleted(index string, intervalIdx int) error
+	GetFollowTimestamp(index string) int64
+	SetFollowTimestamp(index string, timestamp int64) error
+	IsInterfaceNil() bool
+}
+
+// RateLimiter defines the behaviour of a component able to throttle the requests sent to the destination
+type RateLimiter interface {
+	Wait(numDocuments int, numBytes int)
+	IsInterfaceNil() bool
+}
+
+// MetricsHandler defines the behaviour of a component able to collect the reindexing progress
+type MetricsHandler interface {
+	AddDocumentsRead(index string, numDocuments uint64)
+	AddDocumentsWritten(index string, numDocuments uint64, numBytes uint64)
+	AddBulkError(index string)
+	AddRetriedItems(index string, numItems uint64)
+	AddRejectedItems(index string, numItems uint64)
+	AddIntervalRetry(index string)
+	AddRequestRetry(cluster string)
+	SetExpectedDocuments(index string, numDocuments uint64)
+	SetIntervals(index string, numIntervals int, numCompleted int)
+	MarkIntervalCompleted(index string)
+	IsInterfaceNil() bool
+}
+
+// DeadLetterHandler defines the behaviour of a component able to store the documents permanently rejected by the destination
+type DeadLetterHandler interface {
+	Add(document *DeadLetterDocument) error
+	IsInterfaceNil() bool
 }
@@ -0,0 +1,146 @@
+package process
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+const objectFieldType = "object"
+
+// fieldTypeChange holds a field that has a different type in the source and in the destination mappings
+type fieldTypeChange struct {
+	field           string
+	sourceType      string
+	destinationType string
+}
+
+// mappingDiff is the structured difference between the source and the destination mappings of an index.
+// The fields are identified by their full path (e.g. "data.creator")
+type mappingDiff struct {
+	added   []string
+	removed []string
+	changed []*fieldTypeChange
+}
+
+type mappingHolder struct {
+	Mappings struct {
+		Properties map[string]json.RawMessage `json:"properties"`
+	} `json:"mappings"`
+}
+
+type fieldMapping struct {
+	Type       string                     `json:"type"`
+	Properties map[string]json.RawMessage `json:"properties"`
+}
+
+// computeMappingDiff compares the properties of the provided mappings. A field is added if it only exists in the
+// destination and removed if it only exists in the source
+func computeMappingDiff(sourceMapping []byte, destinationMapping []byte) (*mappingDiff, error) {
+	sourceFields, err := flattenMapping(sourceMapping)
+	if err != nil {
+		return nil, fmt.Errorf("%w while decoding the source mapping", err)
+	}
+
+	destinationFields, err := flattenMapping(destinationMapping)
+	if err != nil {
+		return nil, fmt.Errorf("%w while decoding the destination mapping", err)
+	}
+
+	diff := &mappingDiff{}
+	for field, sourceType := range sourceFields {
+		destinationType, found := destinationFields[field]
+		if !found {
+			diff.removed = append(diff.removed, field)
+			continue
+		}
+
+		if sourceType != destinationType {
+			diff.changed = append(diff.changed, &fieldTypeChange{
+				field:           field,
+				sourceType:      sourceType,
+				destinationType: destinationType,
+			})
+		}
+	}
+	for field := range destinationFields {
+		_, found := sourceFields[field]
+		if !found {
+			diff.added = append(diff.added, field)
+		}
+	}
+
+	sort.Strings(diff.added)
+	sort.Strings(diff.removed)
+	sort.Slice(diff.changed, func(i, j int) bool {
+		return diff.changed[i].field < diff.changed[j].field
+	})
+
+	return diff, nil
+}
+
+func flattenMapping(mapping []byte) (map[string]string, error) {
+	fields := make(map[string]string)
+	if len(mapping) == 0 {
+		return fields, nil
+	}
+
+	holder := &mappingHolder{}
+	err := json.Unmarshal(mapping, holder)
+	if err != nil {
+		return nil, err
+	}
+
+	err = flattenProperties(holder.Mappings.Properties, "", fields)
+	if err != nil {
+		return nil, err
+	}
+
+	return fields, nil
+}
+
+func flattenProperties(properties map[string]json.RawMessage, prefix string, fields map[string]string) error {
+	for name, rawField := range properties {
+		field := &fieldMapping{}
+		err := json.Unmarshal(rawField, field)
+		if err != nil {
+			return fmt.Errorf("%w for field %s", err, prefix+name)
+		}
+
+		fieldType := field.Type
+		if fieldType == "" {
+			fieldType = objectFieldType
+		}
+		fields[prefix+name] = fieldType
+
+		err = flattenProperties(field.Properties, prefix+name+".", fields)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// isEmpty returns true if the mappings have the same fields, with the same types
+func (md *mappingDiff) isEmpty() bool {
+	return len(md.added) == 0 && md.isCompatible()
+}
+
+// isCompatible returns true if all the source fields exist in the destination with the same type. The fields missing
+// from the destination are incompatible because their type would be guessed by the dynamic mapping
+func (md *mappingDiff) isCompatible() bool {
+	return len(md.removed) == 0 && len(md.changed) == 0
+}
+
+// String returns a human-readable representation of the diff
+func (md *mappingDiff) String() string {
+	changes := make([]string, 0, len(md.changed))
+	for _, change := range md.changed {
+		changes = append(changes, fmt.Sprintf("%s: %s -> %s", change.field, change.sourceType, change.destinationType))
+	}
+
+	return fmt.Sprintf("added [%s], removed [%s], changed [%s]",
+		strings.Join(md.added, ", "), strings.Join(md.removed, ", "), strings.Join(changes, ", "))
+}
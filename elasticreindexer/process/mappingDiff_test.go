@@ -0,0 +1,55 @@
+package process
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestComputeMappingDiff(t *testing.T) {
+	source := []byte(`{"mappings":{"properties":{
+		"nonce":{"type":"long"},
+		"sender":{"type":"keyword"},
+		"data":{"properties":{"creator":{"type":"keyword"},"name":{"type":"text"}}}
+	}}}`)
+
+	t.Run("same mapping should have an empty diff", func(t *testing.T) {
+		diff, err := computeMappingDiff(source, source)
+		require.NoError(t, err)
+		require.True(t, diff.isEmpty())
+	})
+	t.Run("added fields should be compatible", func(t *testing.T) {
+		destination := []byte(`{"mappings":{"properties":{
+			"nonce":{"type":"long"},
+			"sender":{"type":"keyword"},
+			"timestamp":{"type":"date"},
+			"data":{"properties":{"creator":{"type":"keyword"},"name":{"type":"text"},"hash":{"type":"keyword"}}}
+		}}}`)
+
+		diff, err := computeMappingDiff(source, destination)
+		require.NoError(t, err)
+		require.False(t, diff.isEmpty())
+		require.True(t, diff.isCompatible())
+		require.Equal(t, []string{"data.hash", "timestamp"}, diff.added)
+	})
+	t.Run("removed and changed fields should be incompatible", func(t *testing.T) {
+		destination := []byte(`{"mappings":{"properties":{
+			"nonce":{"type":"keyword"},
+			"data":{"properties":{"creator":{"type":"keyword"},"name":{"type":"keyword"}}}
+		}}}`)
+
+		diff, err := computeMappingDiff(source, destination)
+		require.NoError(t, err)
+		require.False(t, diff.isCompatible())
+		require.Equal(t, []string{"sender"}, diff.removed)
+		require.Equal(t, []*fieldTypeChange{
+			{field: "data.name", sourceType: "text", destinationType: "keyword"},
+			{field: "nonce", sourceType: "long", destinationType: "keyword"},
+		}, diff.changed)
+		require.Equal(t, "added [], removed [sender], changed [data.name: text -> keyword, nonce: long -> keyword]", diff.String())
+	})
+	t.Run("invalid mapping should err", func(t *testing.T) {
+		_, err := computeMappingDiff(source, []byte(`{"mappings":{"properties":{"nonce":"long"}}}`))
+		require.Error(t, err)
+	})
+}
@@ -2,6 +2,8 @@ package mock
 
 import (
 	"bytes"
+
+	"github.com/multiversx/mx-chain-tools-go/elasticreindexer/elastic"
 )
 
 // ElasticClientStub -
@@ -10,10 +12,16 @@ type ElasticClientStub struct {
 	CreateIndexWithMappingCalled      func(targetIndex string, body *bytes.Buffer) error
 	DoScrollRequestAllDocumentsCalled func(index string, body []byte, handlerFunc func(responseBytes []byte) error) error
 	GetCountCalled                    func(index string) (uint64, error)
+	GetCountWithBodyCalled            func(index string, body []byte) (uint64, error)
+	GetStoreSizeCalled                func(index string) (uint64, error)
 	DoesAliasExistCalled              func(alias string) bool
 	DoBulkRequestCalled               func(buff *bytes.Buffer, index string) error
 	DoesIndexExistCalled              func(index string) bool
 	PutAliasCalled                    func(index string, alias string) error
+	RefreshIndexCalled                func(index string) error
+	GetIndicesNamesCalled             func(pattern string) ([]string, error)
+	GetAliasIndicesCalled             func(alias string) ([]string, error)
+	SwapAliasesCalled                 func(swaps []elastic.AliasSwap) error
 }
 
 // GetMapping -
@@ -26,7 +34,11 @@ func (e *ElasticClientStub) GetMapping(index string) (*bytes.Buffer, error) {
 }
 
 // GetCountWithBody -
-func (e *ElasticClientStub) GetCountWithBody(_ string, _ []byte) (uint64, error) {
+func (e *ElasticClientStub) GetCountWithBody(index string, body []byte) (uint64, error) {
+	if e.GetCountWithBodyCalled != nil {
+		return e.GetCountWithBodyCalled(index, body)
+	}
+
 	return 0, nil
 }
 
@@ -57,6 +69,15 @@ func (e *ElasticClientStub) GetCount(index string) (uint64, error) {
 	return 0, nil
 }
 
+// GetStoreSize -
+func (e *ElasticClientStub) GetStoreSize(index string) (uint64, error) {
+	if e.GetStoreSizeCalled != nil {
+		return e.GetStoreSizeCalled(index)
+	}
+
+	return 0, nil
+}
+
 // DoesAliasExist -
 func (e *ElasticClientStub) DoesAliasExist(alias string) bool {
 	if e.DoesAliasExistCalled != nil {
@@ -93,6 +114,42 @@ func (e *ElasticClientStub) PutAlias(index string, alias string) error {
 	return nil
 }
 
+// RefreshIndex -
+func (e *ElasticClientStub) RefreshIndex(index string) error {
+	if e.RefreshIndexCalled != nil {
+		return e.RefreshIndexCalled(index)
+	}
+
+	return nil
+}
+
+// GetIndicesNames -
+func (e *ElasticClientStub) GetIndicesNames(pattern string) ([]string, error) {
+	if e.GetIndicesNamesCalled != nil {
+		return e.GetIndicesNamesCalled(pattern)
+	}
+
+	return nil, nil
+}
+
+// GetAliasIndices -
+func (e *ElasticClientStub) GetAliasIndices(alias string) ([]string, error) {
+	if e.GetAliasIndicesCalled != nil {
+		return e.GetAliasIndicesCalled(alias)
+	}
+
+	return nil, nil
+}
+
+// SwapAliases -
+func (e *ElasticClientStub) SwapAliases(swaps []elastic.AliasSwap) error {
+	if e.SwapAliasesCalled != nil {
+		return e.SwapAliasesCalled(swaps)
+	}
+
+	return nil
+}
+
 // IsInterfaceNil -
 func (e *ElasticClientStub) IsInterfaceNil() bool {
 	return e == nil
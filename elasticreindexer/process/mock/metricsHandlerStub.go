@@ -0,0 +1,90 @@
+package mock
+
+// MetricsHandlerStub -
+type MetricsHandlerStub struct {
+	AddDocumentsReadCalled      func(index string, numDocuments uint64)
+	AddDocumentsWrittenCalled   func(index string, numDocuments uint64, numBytes uint64)
+	AddBulkErrorCalled          func(index string)
+	AddRetriedItemsCalled       func(index string, numItems uint64)
+	AddRejectedItemsCalled      func(index string, numItems uint64)
+	AddIntervalRetryCalled      func(index string)
+	AddRequestRetryCalled       func(cluster string)
+	SetExpectedDocumentsCalled  func(index string, numDocuments uint64)
+	SetIntervalsCalled          func(index string, numIntervals int, numCompleted int)
+	MarkIntervalCompletedCalled func(index string)
+}
+
+// AddDocumentsRead -
+func (m *MetricsHandlerStub) AddDocumentsRead(index string, numDocuments uint64) {
+	if m.AddDocumentsReadCalled != nil {
+		m.AddDocumentsReadCalled(index, numDocuments)
+	}
+}
+
+// AddDocumentsWritten -
+func (m *MetricsHandlerStub) AddDocumentsWritten(index string, numDocuments uint64, numBytes uint64) {
+	if m.AddDocumentsWrittenCalled != nil {
+		m.AddDocumentsWrittenCalled(index, numDocuments, numBytes)
+	}
+}
+
+// AddBulkError -
+func (m *MetricsHandlerStub) AddBulkError(index string) {
+	if m.AddBulkErrorCalled != nil {
+		m.AddBulkErrorCalled(index)
+	}
+}
+
+// AddRetriedItems -
+func (m *MetricsHandlerStub) AddRetriedItems(index string, numItems uint64) {
+	if m.AddRetriedItemsCalled != nil {
+		m.AddRetriedItemsCalled(index, numItems)
+	}
+}
+
+// AddRejectedItems -
+func (m *MetricsHandlerStub) AddRejectedItems(index string, numItems uint64) {
+	if m.AddRejectedItemsCalled != nil {
+		m.AddRejectedItemsCalled(index, numItems)
+	}
+}
+
+// AddIntervalRetry -
+func (m *MetricsHandlerStub) AddIntervalRetry(index string) {
+	if m.AddIntervalRetryCalled != nil {
+		m.AddIntervalRetryCalled(index)
+	}
+}
+
+// AddRequestRetry -
+func (m *MetricsHandlerStub) AddRequestRetry(cluster string) {
+	if m.AddRequestRetryCalled != nil {
+		m.AddRequestRetryCalled(cluster)
+	}
+}
+
+// SetExpectedDocuments -
+func (m *MetricsHandlerStub) SetExpectedDocuments(index string, numDocuments uint64) {
+	if m.SetExpectedDocumentsCalled != nil {
+		m.SetExpectedDocumentsCalled(index, numDocuments)
+	}
+}
+
+// SetIntervals -
+func (m *MetricsHandlerStub) SetIntervals(index string, numIntervals int, numCompleted int) {
+	if m.SetIntervalsCalled != nil {
+		m.SetIntervalsCalled(index, numIntervals, numCompleted)
+	}
+}
+
+// MarkIntervalCompleted -
+func (m *MetricsHandlerStub) MarkIntervalCompleted(index string) {
+	if m.MarkIntervalCompletedCalled != nil {
+		m.MarkIntervalCompletedCalled(index)
+	}
+}
+
+// IsInterfaceNil -
+func (m *MetricsHandlerStub) IsInterfaceNil() bool {
+	return m == nil
+}
@@ -0,0 +1,60 @@
+package mock
+
+// ReindexerHandlerStub -
+type ReindexerHandlerStub struct {
+	ProcessCalled                   func(overwrite bool, skipMappings bool, indices ...string) error
+	CopyMappingIfNecessaryCalled    func(index string, overwrite bool, skipMappings bool) error
+	ProcessIndexWithTimestampCalled func(index string, start, stop int64, count *uint64, progressHandler func(lastTimestamp int64, rejectedIDs []string) error) error
+	GetCountsForIntervalCalled      func(index string, start, stop int64) (uint64, uint64, error)
+	GetSourcesBoundariesCalled      func(start, stop int64) []int64
+}
+
+// Process -
+func (stub *ReindexerHandlerStub) Process(overwrite bool, skipMappings bool, indices ...string) error {
+	if stub.ProcessCalled != nil {
+		return stub.ProcessCalled(overwrite, skipMappings, indices...)
+	}
+
+	return nil
+}
+
+// CopyMappingIfNecessary -
+func (stub *ReindexerHandlerStub) CopyMappingIfNecessary(index string, overwrite bool, skipMappings bool) error {
+	if stub.CopyMappingIfNecessaryCalled != nil {
+		return stub.CopyMappingIfNecessaryCalled(index, overwrite, skipMappings)
+	}
+
+	return nil
+}
+
+// ProcessIndexWithTimestamp -
+func (stub *ReindexerHandlerStub) ProcessIndexWithTimestamp(
+	index string,
+	start, stop int64,
+	count *uint64,
+	progressHandler func(lastTimestamp int64, rejectedIDs []string) error,
+) error {
+	if stub.ProcessIndexWithTimestampCalled != nil {
+		return stub.ProcessIndexWithTimestampCalled(index, start, stop, count, progressHandler)
+	}
+
+	return nil
+}
+
+// GetCountsForInterval -
+func (stub *ReindexerHandlerStub) GetCountsForInterval(index string, start, stop int64) (uint64, uint64, error) {
+	if stub.GetCountsForIntervalCalled != nil {
+		return stub.GetCountsForIntervalCalled(index, start, stop)
+	}
+
+	return 0, 0, nil
+}
+
+// GetSourcesBoundaries -
+func (stub *ReindexerHandlerStub) GetSourcesBoundaries(start, stop int64) []int64 {
+	if stub.GetSourcesBoundariesCalled != nil {
+		return stub.GetSourcesBoundariesCalled(start, stop)
+	}
+
+	return nil
+}
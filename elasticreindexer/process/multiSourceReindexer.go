@@ -0,0 +1,201 @@
+package process
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+)
+
+var errNoSources = errors.New("no source to reindex from")
+
+// SourceReindexer holds the reindexer of a source and the time window in which the source is used. A zero stop time
+// means the window has no end, both bounds being inclusive
+type SourceReindexer struct {
+	Name      string
+	Reindexer ReindexerHandler
+	Priority  int
+	StartTime int64
+	StopTime  int64
+}
+
+// ArgsMultiSourceReindexer is the DTO used in the NewMultiSourceReindexer constructor function
+type ArgsMultiSourceReindexer struct {
+	Sources []*SourceReindexer
+}
+
+type sourceSegment struct {
+	source *SourceReindexer
+	start  int64
+	stop   int64
+}
+
+// multiSourceReindexer reindexes the documents of several sources in the same destination. The documents with
+// timestamp are read, for every period, from the source with the highest priority whose time window covers the period,
+// the sources with the same priority being preferred in their configuration order
+type multiSourceReindexer struct {
+	sources []*SourceReindexer
+}
+
+// NewMultiSourceReindexer creates a new instance of multiSourceReindexer
+func NewMultiSourceReindexer(args ArgsMultiSourceReindexer) (*multiSourceReindexer, error) {
+	if len(args.Sources) == 0 {
+		return nil, errNoSources
+	}
+	for idx, source := range args.Sources {
+		if source == nil || source.Reindexer == nil {
+			return nil, fmt.Errorf("nil ReindexerHandler for source nr %d", idx)
+		}
+		if source.StopTime != 0 && source.StopTime < source.StartTime {
+			return nil, fmt.Errorf("%w, the stop time of source %s is lower than its start time", errInvalidValue, source.Name)
+		}
+	}
+
+	return &multiSourceReindexer{
+		sources: args.Sources,
+	}, nil
+}
+
+// Process will reindex the provided indices from all the sources, the time windows being ignored for them. The
+// sources are processed in ascending priority, so the documents of the preferred source are written last
+func (msr *multiSourceReindexer) Process(overwrite bool, skipMappings bool, indices ...string) error {
+	for idx, source := range msr.sourcesByAscendingPriority() {
+		log.Info("reindexing from source", "source", source.Name, "indices", indices)
+
+		// the indices are already created in the destination by the previous sources
+		err := source.Reindexer.Process(overwrite || idx > 0, skipMappings, indices...)
+		if err != nil {
+			return fmt.Errorf("%w while reindexing from source %s", err, source.Name)
+		}
+	}
+
+	return nil
+}
+
+// CopyMappingIfNecessary will create the index with the mapping of the preferred source
+func (msr *multiSourceReindexer) CopyMappingIfNecessary(index string, overwrite bool, skipMappings bool) error {
+	sources := msr.sourcesByAscendingPriority()
+	preferredSource := sources[len(sources)-1]
+
+	return preferredSource.Reindexer.CopyMappingIfNecessary(index, overwrite, skipMappings)
+}
+
+// ProcessIndexWithTimestamp will reindex every period of the provided interval from the source chosen for it
+func (msr *multiSourceReindexer) ProcessIndexWithTimestamp(
+	index string,
+	start, stop int64,
+	count *uint64,
+	progressHandler func(lastTimestamp int64, rejectedIDs []string) error,
+) error {
+	for _, segment := range msr.computeSegments(start, stop) {
+		log.Debug("reindexing period", "index", index, "source", segment.source.Name, "start", segment.start, "stop", segment.stop)
+
+		err := segment.source.Reindexer.ProcessIndexWithTimestamp(index, segment.start, segment.stop, count, progressHandler)
+		if err != nil {
+			return fmt.Errorf("%w while reindexing from source %s", err, segment.source.Name)
+		}
+	}
+
+	return nil
+}
+
+// GetCountsForInterval will return the counts of the provided interval, the source count being the sum of the counts
+// of the periods from the sources chosen for them
+func (msr *multiSourceReindexer) GetCountsForInterval(index string, start, stop int64) (uint64, uint64, error) {
+	countSource, countDestination := uint64(0), uint64(0)
+	for _, segment := range msr.computeSegments(start, stop) {
+		segmentCountSource, segmentCountDestination, err := segment.source.Reindexer.GetCountsForInterval(index, segment.start, segment.stop)
+		if err != nil {
+			return 0, 0, fmt.Errorf("%w while getting the counts from source %s", err, segment.source.Name)
+		}
+
+		countSource += segmentCountSource
+		countDestination += segmentCountDestination
+	}
+
+	return countSource, countDestination, nil
+}
+
+// GetSourcesBoundaries returns the timestamps, inside the provided interval, where the reading moves to another source
+func (msr *multiSourceReindexer) GetSourcesBoundaries(start, stop int64) []int64 {
+	segments := msr.computeSegments(start, stop)
+
+	boundaries := make([]int64, 0)
+	for idx := 1; idx < len(segments); idx++ {
+		boundaries = append(boundaries, segments[idx].start)
+	}
+
+	return boundaries
+}
+
+func (msr *multiSourceReindexer) sourcesByAscendingPriority() []*SourceReindexer {
+	sources := make([]*SourceReindexer, 0, len(msr.sources))
+	for idx := len(msr.sources) - 1; idx >= 0; idx-- {
+		sources = append(sources, msr.sources[idx])
+	}
+
+	sort.SliceStable(sources, func(i, j int) bool {
+		return sources[i].Priority < sources[j].Priority
+	})
+
+	return sources
+}
+
+// computeSegments splits the provided interval in periods read from a single source. The periods not covered by any
+// source are skipped
+func (msr *multiSourceReindexer) computeSegments(start, stop int64) []*sourceSegment {
+	boundaries := []int64{start, stop + 1}
+	for _, source := range msr.sources {
+		if source.StartTime > start && source.StartTime <= stop {
+			boundaries = append(boundaries, source.StartTime)
+		}
+		if source.StopTime != 0 && source.StopTime >= start && source.StopTime < stop {
+			boundaries = append(boundaries, source.StopTime+1)
+		}
+	}
+	sort.Slice(boundaries, func(i, j int) bool {
+		return boundaries[i] < boundaries[j]
+	})
+
+	segments := make([]*sourceSegment, 0)
+	for idx := 0; idx < len(boundaries)-1; idx++ {
+		segmentStart, segmentStop := boundaries[idx], boundaries[idx+1]-1
+		if segmentStart > segmentStop {
+			continue
+		}
+
+		source := msr.getSourceForTimestamp(segmentStart)
+		if source == nil {
+			log.Warn("no source covers the period, skipping", "start", segmentStart, "stop", segmentStop)
+			continue
+		}
+
+		lastIdx := len(segments) - 1
+		if lastIdx >= 0 && segments[lastIdx].source == source && segments[lastIdx].stop+1 == segmentStart {
+			segments[lastIdx].stop = segmentStop
+			continue
+		}
+
+		segments = append(segments, &sourceSegment{
+			source: source,
+			start:  segmentStart,
+			stop:   segmentStop,
+		})
+	}
+
+	return segments
+}
+
+func (msr *multiSourceReindexer) getSourceForTimestamp(timestamp int64) *SourceReindexer {
+	var chosenSource *SourceReindexer
+	for _, source := range msr.sources {
+		isCovered := timestamp >= source.StartTime && (source.StopTime == 0 || timestamp <= source.StopTime)
+		if !isCovered {
+			continue
+		}
+		if chosenSource == nil || source.Priority > chosenSource.Priority {
+			chosenSource = source
+		}
+	}
+
+	return chosenSource
+}
@@ -0,0 +1,156 @@
+package process
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/multiversx/mx-chain-tools-go/elasticreindexer/process/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type processedInterval struct {
+	source string
+	start  int64
+	stop   int64
+}
+
+func createSourceReindexer(name string, priority int, startTime, stopTime int64, processed *[]processedInterval) *SourceReindexer {
+	return &SourceReindexer{
+		Name:      name,
+		Priority:  priority,
+		StartTime: startTime,
+		StopTime:  stopTime,
+		Reindexer: &mock.ReindexerHandlerStub{
+			ProcessIndexWithTimestampCalled: func(_ string, start, stop int64, _ *uint64, _ func(int64, []string) error) error {
+				*processed = append(*processed, processedInterval{source: name, start: start, stop: stop})
+				return nil
+			},
+			GetCountsForIntervalCalled: func(_ string, start, stop int64) (uint64, uint64, error) {
+				return uint64(stop - start + 1), 1, nil
+			},
+		},
+	}
+}
+
+func TestNewMultiSourceReindexer(t *testing.T) {
+	_, err := NewMultiSourceReindexer(ArgsMultiSourceReindexer{})
+	require.Equal(t, errNoSources, err)
+
+	_, err = NewMultiSourceReindexer(ArgsMultiSourceReindexer{Sources: []*SourceReindexer{{Name: "a"}}})
+	require.Error(t, err)
+
+	processed := make([]processedInterval, 0)
+	_, err = NewMultiSourceReindexer(ArgsMultiSourceReindexer{Sources: []*SourceReindexer{
+		createSourceReindexer("a", 0, 100, 50, &processed),
+	}})
+	require.True(t, errors.Is(err, errInvalidValue))
+
+	msr, err := NewMultiSourceReindexer(ArgsMultiSourceReindexer{Sources: []*SourceReindexer{
+		createSourceReindexer("a", 0, 0, 0, &processed),
+	}})
+	require.NoError(t, err)
+	require.NotNil(t, msr)
+}
+
+func TestMultiSourceReindexer_ProcessIndexWithTimestamp(t *testing.T) {
+	t.Run("overlapping windows should be read from the preferred source", func(t *testing.T) {
+		processed := make([]processedInterval, 0)
+		msr, _ := NewMultiSourceReindexer(ArgsMultiSourceReindexer{Sources: []*SourceReindexer{
+			createSourceReindexer("old", 1, 0, 200, &processed),
+			createSourceReindexer("new", 2, 150, 0, &processed),
+		}})
+
+		err := msr.ProcessIndexWithTimestamp(testIndex, 100, 300, new(uint64), nil)
+		require.NoError(t, err)
+		require.Equal(t, []processedInterval{
+			{source: "old", start: 100, stop: 149},
+			{source: "new", start: 150, stop: 300},
+		}, processed)
+		require.Equal(t, []int64{150}, msr.GetSourcesBoundaries(100, 300))
+
+		countSource, countDestination, err := msr.GetCountsForInterval(testIndex, 100, 300)
+		require.NoError(t, err)
+		require.Equal(t, uint64(201), countSource)
+		require.Equal(t, uint64(2), countDestination)
+	})
+
+	t.Run("window inside a lower priority window should split it", func(t *testing.T) {
+		processed := make([]processedInterval, 0)
+		msr, _ := NewMultiSourceReindexer(ArgsMultiSourceReindexer{Sources: []*SourceReindexer{
+			createSourceReindexer("backup", 0, 0, 0, &processed),
+			createSourceReindexer("main", 1, 120, 180, &processed),
+		}})
+
+		err := msr.ProcessIndexWithTimestamp(testIndex, 100, 200, new(uint64), nil)
+		require.NoError(t, err)
+		require.Equal(t, []processedInterval{
+			{source: "backup", start: 100, stop: 119},
+			{source: "main", start: 120, stop: 180},
+			{source: "backup", start: 181, stop: 200},
+		}, processed)
+	})
+
+	t.Run("equal priorities should prefer the first source and gaps should be skipped", func(t *testing.T) {
+		processed := make([]processedInterval, 0)
+		msr, _ := NewMultiSourceReindexer(ArgsMultiSourceReindexer{Sources: []*SourceReindexer{
+			createSourceReindexer("first", 0, 100, 150, &processed),
+			createSourceReindexer("second", 0, 120, 160, &processed),
+			createSourceReindexer("third", 0, 180, 0, &processed),
+		}})
+
+		err := msr.ProcessIndexWithTimestamp(testIndex, 100, 200, new(uint64), nil)
+		require.NoError(t, err)
+		require.Equal(t, []processedInterval{
+			{source: "first", start: 100, stop: 150},
+			{source: "second", start: 151, stop: 160},
+			{source: "third", start: 180, stop: 200},
+		}, processed)
+	})
+
+	t.Run("source error should be returned", func(t *testing.T) {
+		expectedErr := errors.New("expected error")
+		msr, _ := NewMultiSourceReindexer(ArgsMultiSourceReindexer{Sources: []*SourceReindexer{
+			{
+				Name: "a",
+				Reindexer: &mock.ReindexerHandlerStub{
+					ProcessIndexWithTimestampCalled: func(_ string, _, _ int64, _ *uint64, _ func(int64, []string) error) error {
+						return expectedErr
+					},
+				},
+			},
+		}})
+
+		err := msr.ProcessIndexWithTimestamp(testIndex, 100, 200, new(uint64), nil)
+		require.True(t, errors.Is(err, expectedErr))
+	})
+}
+
+func TestMultiSourceReindexer_Process(t *testing.T) {
+	overwrites := make(map[string]bool)
+	createSource := func(name string, priority int) *SourceReindexer {
+		return &SourceReindexer{
+			Name:     name,
+			Priority: priority,
+			Reindexer: &mock.ReindexerHandlerStub{
+				ProcessCalled: func(overwrite bool, _ bool, _ ...string) error {
+					overwrites[name] = overwrite
+					return nil
+				},
+			},
+		}
+	}
+
+	order := make([]string, 0)
+	msr, _ := NewMultiSourceReindexer(ArgsMultiSourceReindexer{Sources: []*SourceReindexer{
+		createSource("preferred", 5),
+		createSource("fallback", 1),
+	}})
+	for _, source := range msr.sourcesByAscendingPriority() {
+		order = append(order, source.Name)
+	}
+	require.Equal(t, []string{"fallback", "preferred"}, order)
+
+	err := msr.Process(false, false, testIndex)
+	require.NoError(t, err)
+	require.Equal(t, map[string]bool{"fallback": false, "preferred": true}, overwrites)
+}
@@ -0,0 +1,252 @@
+package process
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/multiversx/mx-chain-core-go/core/check"
+	"github.com/multiversx/mx-chain-tools-go/elasticreindexer/config"
+)
+
+// ArgsPlanner is the DTO used in the NewPlanner constructor function
+type ArgsPlanner struct {
+	SourceElastic      ElasticClientHandler
+	DestinationElastic ElasticClientHandler
+	IndicesConfig      config.IndicesConfig
+}
+
+// IntervalPlan holds what would be copied for an interval of an index with timestamp
+type IntervalPlan struct {
+	Start          int64
+	Stop           int64
+	SourceCount    uint64
+	EstimatedBytes uint64
+}
+
+// IndexPlan holds what the reindexing would do for an index
+type IndexPlan struct {
+	Index          string
+	AliasExists    bool
+	IndexExists    bool
+	MappingAction  string
+	SourceCount    uint64
+	EstimatedBytes uint64
+	Intervals      []*IntervalPlan
+}
+
+// planner computes what a reindexing run would do, without writing anything in the destination
+type planner struct {
+	sourceElastic        ElasticClientHandler
+	destinationElastic   ElasticClientHandler
+	indicesNoTimestamp   []string
+	indicesWithTimestamp []string
+	numParallelWrites    int
+	blockChainStartTime  int64
+	indicesSettings      map[string]*indexSettings
+	names                indexNames
+	mappingsConfig       config.MappingsConfig
+}
+
+// NewPlanner creates a new planner instance
+func NewPlanner(args ArgsPlanner) (*planner, error) {
+	if check.IfNil(args.SourceElastic) {
+		return nil, fmt.Errorf("%w for source", errNilElasticHandler)
+	}
+	if check.IfNil(args.DestinationElastic) {
+		return nil, fmt.Errorf("%w for destination", errNilElasticHandler)
+	}
+
+	indicesSettings, err := createIndicesSettings(args.IndicesConfig.PerIndex)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &planner{
+		sourceElastic:       args.SourceElastic,
+		destinationElastic:  args.DestinationElastic,
+		indicesNoTimestamp:  args.IndicesConfig.Indices,
+		numParallelWrites:   args.IndicesConfig.WithTimestamp.NumParallelWrites,
+		blockChainStartTime: args.IndicesConfig.WithTimestamp.BlockchainStartTime,
+		indicesSettings:     indicesSettings,
+		names:               newIndexNames(args.IndicesConfig),
+		mappingsConfig:      args.IndicesConfig.Mappings,
+	}
+	if args.IndicesConfig.WithTimestamp.Enabled {
+		p.indicesWithTimestamp = args.IndicesConfig.WithTimestamp.IndicesWithTimestamp
+	}
+
+	return p, nil
+}
+
+// Plan returns, for every configured index, what would be created in the destination and how much data would be copied.
+// The indices with timestamp are split in the same intervals as the ones used for reindexing
+func (p *planner) Plan(overwrite bool, skipMappings bool, stopTimestamp int64) ([]*IndexPlan, error) {
+	plans := make([]*IndexPlan, 0, len(p.indicesNoTimestamp)+len(p.indicesWithTimestamp))
+	for _, index := range p.indicesNoTimestamp {
+		if index == "" {
+			continue
+		}
+
+		plan, _, err := p.planIndex(index, overwrite, skipMappings)
+		if err != nil {
+			return nil, err
+		}
+
+		plans = append(plans, plan)
+	}
+
+	if len(p.indicesWithTimestamp) == 0 {
+		return plans, nil
+	}
+
+	intervals, err := computeIntervals(p.blockChainStartTime, stopTimestamp, int64(p.numParallelWrites))
+	if err != nil {
+		return nil, err
+	}
+
+	for _, index := range p.indicesWithTimestamp {
+		if index == "" {
+			continue
+		}
+
+		plan, bytesPerDocument, err := p.planIndex(index, overwrite, skipMappings)
+		if err != nil {
+			return nil, err
+		}
+
+		plan.Intervals, err = p.planIntervals(index, intervals, bytesPerDocument)
+		if err != nil {
+			return nil, err
+		}
+
+		plans = append(plans, plan)
+	}
+
+	return plans, nil
+}
+
+// planIndex returns the plan of the index and the average size of its documents in the source
+func (p *planner) planIndex(index string, overwrite bool, skipMappings bool) (*IndexPlan, float64, error) {
+	sourceIndex := p.names.source(index)
+	totalCount, err := p.sourceElastic.GetCount(sourceIndex)
+	if err != nil {
+		return nil, 0, fmt.Errorf("%w while getting the source count for index %s", err, index)
+	}
+
+	sourceCount, err := p.sourceElastic.GetCountWithBody(sourceIndex, getAll(0, getSettingsForIndex(p.indicesSettings, index).query).Bytes())
+	if err != nil {
+		return nil, 0, fmt.Errorf("%w while getting the source count for index %s", err, index)
+	}
+
+	storeSize, err := p.sourceElastic.GetStoreSize(sourceIndex)
+	if err != nil {
+		return nil, 0, fmt.Errorf("%w while getting the source size for index %s", err, index)
+	}
+
+	bytesPerDocument := float64(0)
+	if totalCount > 0 {
+		bytesPerDocument = float64(storeSize) / float64(totalCount)
+	}
+
+	plan := &IndexPlan{
+		Index:          index,
+		SourceCount:    sourceCount,
+		EstimatedBytes: uint64(bytesPerDocument * float64(sourceCount)),
+	}
+
+	destinationIndex := p.names.destination(index)
+	mapping, err := planMappingCopy(p.destinationElastic, destinationIndex, overwrite)
+	plan.AliasExists = mapping.aliasExists
+	plan.IndexExists = mapping.indexExists
+	plan.MappingAction = p.describeMappingPlan(index, mapping, err, skipMappings)
+
+	return plan, bytesPerDocument, nil
+}
+
+func (p *planner) planIntervals(index string, intervals []*interval, bytesPerDocument float64) ([]*IntervalPlan, error) {
+	filterQuery := getSettingsForIndex(p.indicesSettings, index).query
+
+	intervalPlans := make([]*IntervalPlan, 0, len(intervals))
+	for _, interv := range intervals {
+		body := getWithTimestamp(interv.start, interv.stop, false, false, 0, filterQuery).Bytes()
+		count, err := p.sourceElastic.GetCountWithBody(p.names.source(index), body)
+		if err != nil {
+			return nil, fmt.Errorf("%w while getting the source count for index %s, interval %d-%d",
+				err, index, interv.start, interv.stop)
+		}
+
+		intervalPlans = append(intervalPlans, &IntervalPlan{
+			Start:          interv.start,
+			Stop:           interv.stop,
+			SourceCount:    count,
+			EstimatedBytes: uint64(bytesPerDocument * float64(count)),
+		})
+	}
+
+	return intervalPlans, nil
+}
+
+// describeMappingPlan describes what the reindexing would create in the destination for the index, with the body the
+// index would be created with, and the result of the mappings comparison, if enabled
+func (p *planner) describeMappingPlan(index string, plan *mappingPlan, err error, skipMappings bool) string {
+	if skipMappings {
+		return "skip, the mappings copy is disabled"
+	}
+	if err != nil {
+		return fmt.Sprintf("fail: %s", err.Error())
+	}
+
+	getMapping := func() (*bytes.Buffer, error) {
+		return p.sourceElastic.GetMapping(p.names.source(index))
+	}
+
+	var indexBody *bytes.Buffer
+	isFromTemplate := false
+	if plan.createIndex {
+		indexBody, isFromTemplate, err = getIndexBody(p.mappingsConfig.TemplatesDirectory, index, getMapping)
+		if err != nil {
+			return fmt.Sprintf("fail: %s", err.Error())
+		}
+	}
+
+	destinationIndex := p.names.destination(index)
+	action := describeMappingActions(destinationIndex, plan, isFromTemplate)
+	if !shouldCompareMappings(p.mappingsConfig, getSettingsForIndex(p.indicesSettings, index), index) {
+		return action
+	}
+
+	diff, err := computeDestinationMappingDiff(p.destinationElastic, destinationIndex, plan, getMapping, indexBody)
+	if err != nil {
+		return fmt.Sprintf("fail: %s", err.Error())
+	}
+
+	switch {
+	case diff.isEmpty():
+		return action + ", same mappings"
+	case diff.isCompatible():
+		return fmt.Sprintf("%s, the destination mapping has additional fields: %s", action, diff.String())
+	case p.mappingsConfig.ForceIncompatible:
+		return fmt.Sprintf("%s, incompatible mappings forced: %s", action, diff.String())
+	default:
+		return fmt.Sprintf("fail: %s for index %s: %s", errIncompatibleMappings.Error(), index, diff.String())
+	}
+}
+
+func describeMappingActions(index string, plan *mappingPlan, isFromTemplate bool) string {
+	indexWithSuffix := index + indexSuffix
+	body := "the source mapping"
+	if isFromTemplate {
+		body = "the template"
+	}
+
+	switch {
+	case plan.createIndex && plan.putAlias:
+		return fmt.Sprintf("create index %s with %s and alias %s", indexWithSuffix, body, index)
+	case plan.createIndex:
+		return fmt.Sprintf("create index %s with %s, alias %s already exists", indexWithSuffix, body, index)
+	case plan.putAlias:
+		return fmt.Sprintf("create alias %s, index %s already exists", index, indexWithSuffix)
+	default:
+		return "nothing, the index and the alias already exist"
+	}
+}
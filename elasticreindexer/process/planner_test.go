@@ -0,0 +1,143 @@
+package process
+
+import (
+	"bytes"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/multiversx/mx-chain-tools-go/elasticreindexer/config"
+	"github.com/multiversx/mx-chain-tools-go/elasticreindexer/process/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewPlanner(t *testing.T) {
+	p, err := NewPlanner(ArgsPlanner{DestinationElastic: &mock.ElasticClientStub{}})
+	require.Nil(t, p)
+	require.ErrorIs(t, err, errNilElasticHandler)
+
+	p, err = NewPlanner(ArgsPlanner{SourceElastic: &mock.ElasticClientStub{}})
+	require.Nil(t, p)
+	require.ErrorIs(t, err, errNilElasticHandler)
+
+	p, err = NewPlanner(ArgsPlanner{SourceElastic: &mock.ElasticClientStub{}, DestinationElastic: &mock.ElasticClientStub{}})
+	require.NotNil(t, p)
+	require.NoError(t, err)
+}
+
+func TestPlanner_PlanShouldNotWriteInDestination(t *testing.T) {
+	source := &mock.ElasticClientStub{
+		GetCountCalled: func(_ string) (uint64, error) {
+			return 100, nil
+		},
+		GetStoreSizeCalled: func(_ string) (uint64, error) {
+			return 10000, nil
+		},
+	}
+	destination := &mock.ElasticClientStub{
+		DoesAliasExistCalled: func(alias string) bool {
+			return alias == "tokens"
+		},
+		CreateIndexWithMappingCalled: func(_ string, _ *bytes.Buffer) error {
+			require.Fail(t, "should not create indices")
+			return nil
+		},
+		PutAliasCalled: func(_ string, _ string) error {
+			require.Fail(t, "should not create aliases")
+			return nil
+		},
+		DoBulkRequestCalled: func(_ *bytes.Buffer, _ string) error {
+			require.Fail(t, "should not write documents")
+			return nil
+		},
+	}
+
+	cfg := config.IndicesConfig{Indices: []string{testIndex}}
+	cfg.WithTimestamp.Enabled = true
+	cfg.WithTimestamp.BlockchainStartTime = 1000
+	cfg.WithTimestamp.NumParallelWrites = 2
+	cfg.WithTimestamp.IndicesWithTimestamp = []string{"tokens"}
+	p, _ := NewPlanner(ArgsPlanner{SourceElastic: source, DestinationElastic: destination, IndicesConfig: cfg})
+
+	plans, err := p.Plan(false, false, 2000)
+	require.NoError(t, err)
+	require.Len(t, plans, 2)
+
+	require.Equal(t, testIndex, plans[0].Index)
+	require.False(t, plans[0].AliasExists)
+	require.True(t, strings.HasPrefix(plans[0].MappingAction, "create index"))
+	require.Empty(t, plans[0].Intervals)
+
+	require.Equal(t, "tokens", plans[1].Index)
+	require.True(t, plans[1].AliasExists)
+	require.True(t, strings.HasPrefix(plans[1].MappingAction, "fail"))
+	require.Len(t, plans[1].Intervals, 2)
+}
+
+func TestPlanner_PlanShouldEstimateBytesFromTheAverageDocumentSize(t *testing.T) {
+	source := &mock.ElasticClientStub{
+		GetCountCalled: func(_ string) (uint64, error) {
+			return 100, nil
+		},
+		GetCountWithBodyCalled: func(_ string, _ []byte) (uint64, error) {
+			return 25, nil
+		},
+		GetStoreSizeCalled: func(_ string) (uint64, error) {
+			return 10000, nil
+		},
+	}
+
+	cfg := config.IndicesConfig{}
+	cfg.WithTimestamp.Enabled = true
+	cfg.WithTimestamp.BlockchainStartTime = 1000
+	cfg.WithTimestamp.NumParallelWrites = 1
+	cfg.WithTimestamp.IndicesWithTimestamp = []string{testIndex}
+	p, _ := NewPlanner(ArgsPlanner{SourceElastic: source, DestinationElastic: &mock.ElasticClientStub{}, IndicesConfig: cfg})
+
+	plans, err := p.Plan(true, true, 2000)
+	require.NoError(t, err)
+	require.Equal(t, uint64(2500), plans[0].EstimatedBytes)
+	require.Equal(t, uint64(2500), plans[0].Intervals[0].EstimatedBytes)
+	require.Equal(t, "skip, the mappings copy is disabled", plans[0].MappingAction)
+}
+
+func TestPlanner_PlanShouldDescribeTheIndexBodyAndTheMappingsComparison(t *testing.T) {
+	templatesDirectory := t.TempDir()
+	template := `{"index_patterns":["tokens-*"],"mappings":{"properties":{"nonce":{"type":"long"},"sender":{"type":"keyword"},"extra":{"type":"long"}}}}`
+	require.NoError(t, ioutil.WriteFile(filepath.Join(templatesDirectory, "tokens.json"), []byte(template), 0644))
+
+	source := &mock.ElasticClientStub{
+		GetMappingCalled: func(_ string) (*bytes.Buffer, error) {
+			return bytes.NewBufferString(`{"mappings":{"properties":{"nonce":{"type":"long"},"sender":{"type":"keyword"}}}}`), nil
+		},
+	}
+	destination := &mock.ElasticClientStub{
+		DoesIndexExistCalled: func(index string) bool {
+			return index == "accounts"+indexSuffix
+		},
+		GetMappingCalled: func(_ string) (*bytes.Buffer, error) {
+			return bytes.NewBufferString(`{"mappings":{"properties":{"nonce":{"type":"keyword"}}}}`), nil
+		},
+	}
+
+	cfg := config.IndicesConfig{Indices: []string{testIndex, "tokens", "accounts"}}
+	cfg.Mappings.TemplatesDirectory = templatesDirectory
+	cfg.Mappings.Compare = true
+	p, _ := NewPlanner(ArgsPlanner{SourceElastic: source, DestinationElastic: destination, IndicesConfig: cfg})
+
+	plans, err := p.Plan(true, false, 2000)
+	require.NoError(t, err)
+	require.Len(t, plans, 3)
+	require.Equal(t, "create index "+testIndex+indexSuffix+" with the source mapping and alias "+testIndex+", same mappings", plans[0].MappingAction)
+	require.Equal(t, "create index tokens"+indexSuffix+" with the template and alias tokens, "+
+		"the destination mapping has additional fields: added [extra], removed [], changed []", plans[1].MappingAction)
+	require.True(t, strings.HasPrefix(plans[2].MappingAction, "fail: "+errIncompatibleMappings.Error()+" for index accounts"))
+
+	cfg.Mappings.ForceIncompatible = true
+	p, _ = NewPlanner(ArgsPlanner{SourceElastic: source, DestinationElastic: destination, IndicesConfig: cfg})
+
+	plans, err = p.Plan(true, false, 2000)
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(plans[2].MappingAction, "create alias accounts, index accounts"+indexSuffix+" already exists, incompatible mappings forced: "))
+}
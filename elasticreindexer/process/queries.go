@@ -17,29 +17,47 @@ func encodeQuery(query object) (bytes.Buffer, error) {
 	return buff, nil
 }
 
-func getAll() *bytes.Buffer {
+func getAll(pageSize int, filterQuery json.RawMessage) *bytes.Buffer {
 	obj := object{
 		"query": object{
 			"match_all": object{},
 		},
 	}
+	if len(filterQuery) > 0 {
+		obj["query"] = object{
+			"bool": object{
+				"filter": []interface{}{filterQuery},
+			},
+		}
+	}
+	if pageSize > 0 {
+		obj["size"] = pageSize
+	}
 
 	encoded, _ := encodeQuery(obj)
 
 	return &encoded
 }
 
-func getWithTimestamp(start, stop int64, withSource bool, withSortAsc bool) *bytes.Buffer {
-	obj := object{
-		"query": object{
-			"range": object{
-				"timestamp": object{
-					"gte": start,
-					"lte": stop,
-				},
+func getWithTimestamp(start, stop int64, withSource bool, withSortAsc bool, pageSize int, filterQuery json.RawMessage) *bytes.Buffer {
+	rangeQuery := object{
+		"range": object{
+			"timestamp": object{
+				"gte": start,
+				"lte": stop,
 			},
 		},
 	}
+	obj := object{
+		"query": rangeQuery,
+	}
+	if len(filterQuery) > 0 {
+		obj["query"] = object{
+			"bool": object{
+				"filter": []interface{}{rangeQuery, filterQuery},
+			},
+		}
+	}
 
 	if withSortAsc {
 		obj["sort"] = []interface{}{
@@ -53,6 +71,34 @@ func getWithTimestamp(start, stop int64, withSource bool, withSortAsc bool) *byt
 	if withSource {
 		obj["_source"] = true
 	}
+	if pageSize > 0 {
+		obj["size"] = pageSize
+	}
+
+	encoded, _ := encodeQuery(obj)
+
+	return &encoded
+}
+
+// getByIDs returns the query of the documents having the provided ids, all of them fitting in one page
+func getByIDs(ids []string, withSource bool, filterQuery json.RawMessage) *bytes.Buffer {
+	idsQuery := object{
+		"ids": object{
+			"values": ids,
+		},
+	}
+	obj := object{
+		"query":   idsQuery,
+		"_source": withSource,
+		"size":    len(ids),
+	}
+	if len(filterQuery) > 0 {
+		obj["query"] = object{
+			"bool": object{
+				"filter": []interface{}{idsQuery, filterQuery},
+			},
+		}
+	}
 
 	encoded, _ := encodeQuery(obj)
 
@@ -61,19 +107,34 @@ func getWithTimestamp(start, stop int64, withSource bool, withSortAsc bool) *byt
 
 type generalElasticResponse struct {
 	Hits struct {
-		Hits []struct {
-			ID     string          `json:"_id"`
-			Source json.RawMessage `json:"_source"`
-		} `json:"hits"`
+		Hits []elasticHit `json:"hits"`
 	} `json:"hits"`
 }
 
-func extractSourceFromEsResponse(response generalElasticResponse) map[string]json.RawMessage {
+type elasticHit struct {
+	ID      string          `json:"_id"`
+	Routing string          `json:"_routing,omitempty"`
+	Source  json.RawMessage `json:"_source"`
+}
+
+type timestampHolder struct {
+	Timestamp int64 `json:"timestamp"`
+}
+
+// extractLastTimestampFromEsResponse returns the timestamp of the last hit. The hits are expected to be sorted ascending
+// by timestamp, so this is the highest timestamp of the page
+func extractLastTimestampFromEsResponse(response *generalElasticResponse) (int64, bool) {
 	hits := response.Hits.Hits
-	recordsMap := make(map[string]json.RawMessage, len(hits))
-	for i := 0; i < len(hits); i++ {
-		recordsMap[hits[i].ID] = hits[i].Source
+	if len(hits) == 0 {
+		return 0, false
+	}
+
+	holder := &timestampHolder{}
+	err := json.Unmarshal(hits[len(hits)-1].Source, holder)
+	if err != nil {
+		log.Warn("cannot extract timestamp from document", "id", hits[len(hits)-1].ID, "error", err)
+		return 0, false
 	}
 
-	return recordsMap
+	return holder.Timestamp, true
 }
@@ -0,0 +1,61 @@
+package process
+
+import (
+	"sync"
+	"time"
+)
+
+// rateLimiter spreads the bulk requests in time so the documents and bytes written per second do not exceed the
+// configured values. A zero value disables the corresponding limit
+type rateLimiter struct {
+	mut                sync.Mutex
+	documentsPerSecond uint64
+	bytesPerSecond     uint64
+	nextAvailableTime  time.Time
+}
+
+// NewRateLimiter creates a new rate limiter instance
+func NewRateLimiter(documentsPerSecond uint64, bytesPerSecond uint64) *rateLimiter {
+	return &rateLimiter{
+		documentsPerSecond: documentsPerSecond,
+		bytesPerSecond:     bytesPerSecond,
+	}
+}
+
+// Wait blocks until the provided number of documents and bytes can be sent without exceeding the configured rates
+func (rl *rateLimiter) Wait(numDocuments int, numBytes int) {
+	if rl.documentsPerSecond == 0 && rl.bytesPerSecond == 0 {
+		return
+	}
+
+	rl.mut.Lock()
+	now := time.Now()
+	if rl.nextAvailableTime.Before(now) {
+		rl.nextAvailableTime = now
+	}
+	waitUntil := rl.nextAvailableTime
+	rl.nextAvailableTime = rl.nextAvailableTime.Add(rl.computeDuration(numDocuments, numBytes))
+	rl.mut.Unlock()
+
+	time.Sleep(time.Until(waitUntil))
+}
+
+func (rl *rateLimiter) computeDuration(numDocuments int, numBytes int) time.Duration {
+	duration := time.Duration(0)
+	if rl.documentsPerSecond > 0 {
+		duration = time.Duration(uint64(numDocuments) * uint64(time.Second) / rl.documentsPerSecond)
+	}
+	if rl.bytesPerSecond > 0 {
+		bytesDuration := time.Duration(uint64(numBytes) * uint64(time.Second) / rl.bytesPerSecond)
+		if bytesDuration > duration {
+			duration = bytesDuration
+		}
+	}
+
+	return duration
+}
+
+// IsInterfaceNil returns true if there is no value under the interface
+func (rl *rateLimiter) IsInterfaceNil() bool {
+	return rl == nil
+}
@@ -0,0 +1,36 @@
+package process
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRateLimiter_Wait(t *testing.T) {
+	t.Run("no limits should not wait", func(t *testing.T) {
+		rl := NewRateLimiter(0, 0)
+
+		start := time.Now()
+		for i := 0; i < 100; i++ {
+			rl.Wait(1000, 1000000)
+		}
+		require.Less(t, int64(time.Since(start)), int64(100*time.Millisecond))
+	})
+	t.Run("documents limit should spread the requests", func(t *testing.T) {
+		rl := NewRateLimiter(1000, 0)
+
+		start := time.Now()
+		rl.Wait(100, 1)
+		rl.Wait(100, 1)
+		rl.Wait(100, 1)
+		// the third request has to wait for the first two, 100 documents each at 1000 documents per second
+		require.GreaterOrEqual(t, int64(time.Since(start)), int64(200*time.Millisecond))
+	})
+	t.Run("the most restrictive limit should be used", func(t *testing.T) {
+		rl := NewRateLimiter(1000000, 1000)
+
+		require.Equal(t, 500*time.Millisecond, rl.computeDuration(10, 500))
+		require.Equal(t, time.Duration(0), rl.computeDuration(0, 0))
+	})
+}
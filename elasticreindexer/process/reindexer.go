@@ -5,38 +5,139 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"os"
 	"sync/atomic"
 
 	"github.com/multiversx/mx-chain-core-go/core/check"
 	logger "github.com/multiversx/mx-chain-logger-go"
+	"github.com/multiversx/mx-chain-tools-go/elasticreindexer/config"
+	"github.com/multiversx/mx-chain-tools-go/elasticreindexer/reader"
 )
 
 var (
-	errNilElasticHandler = errors.New("nil elastic handler")
-	log                  = logger.GetOrCreate("process")
+	errNilElasticHandler    = errors.New("nil elastic handler")
+	errNilRateLimiter       = errors.New("nil rate limiter")
+	errNilMetricsHandler    = errors.New("nil metrics handler")
+	errNilDeadLetterHandler = errors.New("nil dead-letter handler")
+	errInvalidValue         = errors.New("invalid value")
+	errIncompatibleMappings = errors.New("incompatible mappings")
+	log                     = logger.GetOrCreate("process")
 )
 
 const indexSuffix = "-000001"
 
+// ArgsReindexer is the DTO used in the newReindexer constructor function
+type ArgsReindexer struct {
+	SourceElastic      ElasticClientHandler
+	DestinationElastic ElasticClientHandler
+	Indices            []string
+	PageSize           int
+	BulkSizeThreshold  int
+	RateLimiter        RateLimiter
+	IndicesSettings    map[string]config.PerIndexConfig
+	Mappings           config.MappingsConfig
+	SourcePrefix       string
+	DestinationPrefix  string
+	DestinationIndices map[string]string
+	Metrics            MetricsHandler
+	BulkItems          config.BulkItemsConfig
+	DeadLetter         DeadLetterHandler
+	Documents          config.DocumentsConfig
+	SourceTag          string
+	SharedDestination  bool
+}
+
 type reindexer struct {
 	sourceElastic      ElasticClientHandler
 	destinationElastic ElasticClientHandler
 	indices            []string
+	pageSize           int
+	bulkSizeThreshold  int
+	rateLimiter        RateLimiter
+	indicesSettings    map[string]*indexSettings
+	mappingsConfig     config.MappingsConfig
+	names              indexNames
+	metrics            MetricsHandler
+	bulkItems          config.BulkItemsConfig
+	deadLetter         DeadLetterHandler
+	documents          *documentsWriter
+	sharedDestination  bool
 }
 
 // newReindexer returns a new instance of reindexer if the provided params aren't nil, or error otherwise
-func newReindexer(sourceElastic ElasticClientHandler, destinationElastic ElasticClientHandler, indices []string) (*reindexer, error) {
-	if check.IfNil(sourceElastic) {
+func newReindexer(args ArgsReindexer) (*reindexer, error) {
+	if check.IfNil(args.SourceElastic) {
 		return nil, fmt.Errorf("%w for source", errNilElasticHandler)
 	}
-	if check.IfNil(destinationElastic) {
+
+	return createReindexer(args)
+}
+
+// newImporter returns a new instance of reindexer able only to import the exported files in the destination, so the
+// source elastic handler is not needed
+func newImporter(args ArgsReindexer) (*reindexer, error) {
+	args.SourceElastic = nil
+
+	return createReindexer(args)
+}
+
+func createReindexer(args ArgsReindexer) (*reindexer, error) {
+	if check.IfNil(args.DestinationElastic) {
 		return nil, fmt.Errorf("%w for destination", errNilElasticHandler)
 	}
+	if check.IfNil(args.RateLimiter) {
+		return nil, errNilRateLimiter
+	}
+	if check.IfNil(args.Metrics) {
+		return nil, errNilMetricsHandler
+	}
+	if check.IfNil(args.DeadLetter) {
+		return nil, errNilDeadLetterHandler
+	}
+	if args.BulkItems.NumRetries < 0 {
+		return nil, fmt.Errorf("%w, bulk items num retries %d", errInvalidValue, args.BulkItems.NumRetries)
+	}
+	if args.PageSize < 0 {
+		return nil, fmt.Errorf("%w, page size %d", errInvalidValue, args.PageSize)
+	}
+	if args.BulkSizeThreshold < 0 {
+		return nil, fmt.Errorf("%w, bulk size threshold %d", errInvalidValue, args.BulkSizeThreshold)
+	}
+
+	bulkSizeThreshold := args.BulkSizeThreshold
+	if bulkSizeThreshold == 0 {
+		bulkSizeThreshold = defaultBulkSizeThreshold
+	}
+
+	indicesSettings, err := createIndicesSettings(args.IndicesSettings)
+	if err != nil {
+		return nil, err
+	}
+
+	documents, err := newDocumentsWriter(args.Documents, args.IndicesSettings, args.SourceTag)
+	if err != nil {
+		return nil, err
+	}
 
 	return &reindexer{
-		sourceElastic:      sourceElastic,
-		destinationElastic: destinationElastic,
-		indices:            indices,
+		sourceElastic:      args.SourceElastic,
+		destinationElastic: args.DestinationElastic,
+		indices:            args.Indices,
+		pageSize:           args.PageSize,
+		bulkSizeThreshold:  bulkSizeThreshold,
+		rateLimiter:        args.RateLimiter,
+		indicesSettings:    indicesSettings,
+		mappingsConfig:     args.Mappings,
+		names: indexNames{
+			sourcePrefix:       args.SourcePrefix,
+			destinationPrefix:  args.DestinationPrefix,
+			destinationIndices: args.DestinationIndices,
+		},
+		metrics:           args.Metrics,
+		bulkItems:         args.BulkItems,
+		deadLetter:        args.DeadLetter,
+		documents:         documents,
+		sharedDestination: args.SharedDestination,
 	}, nil
 }
 
@@ -61,11 +162,17 @@ func (r *reindexer) Process(overwrite bool, skipMappings bool, indices ...string
 	return nil
 }
 
+func (r *reindexer) getIndexSettings(index string) *indexSettings {
+	return getSettingsForIndex(r.indicesSettings, index)
+}
+
 func (r *reindexer) processIndex(index string, overwrite bool, skipMappings bool) error {
-	originalSourceCount, err := r.sourceElastic.GetCount(index)
+	countQuery := getAll(0, r.getIndexSettings(index).query).Bytes()
+	originalSourceCount, err := r.sourceElastic.GetCountWithBody(r.names.source(index), countQuery)
 	if err != nil {
 		return fmt.Errorf("%w while getting the source count for index %s", err, index)
 	}
+	r.metrics.SetExpectedDocuments(index, originalSourceCount)
 
 	err = r.copyMappingIfNecessary(index, overwrite, skipMappings)
 	if err != nil {
@@ -74,12 +181,18 @@ func (r *reindexer) processIndex(index string, overwrite bool, skipMappings bool
 
 	log.Info("starting reindexing", "index", index)
 
-	err = r.reindexData(index)
+	numRejected, err := r.reindexData(index)
 	if err != nil {
 		return fmt.Errorf("%w while reindexing data for index %s", err, index)
 	}
 
-	destinationCount, err := r.destinationElastic.GetCount(index)
+	// the destination is refreshed first, so the documents just written are counted
+	err = r.destinationElastic.RefreshIndex(r.names.destination(index))
+	if err != nil {
+		return fmt.Errorf("%w while refreshing the destination index %s", err, index)
+	}
+
+	destinationCount, err := r.destinationElastic.GetCountWithBody(r.names.destination(index), countQuery)
 	if err != nil {
 		return fmt.Errorf("%w while getting the destination count for index %s", err, index)
 	}
@@ -87,108 +200,310 @@ func (r *reindexer) processIndex(index string, overwrite bool, skipMappings bool
 	log.Info("finished indexing for index",
 		"index", index,
 		"original source count", originalSourceCount,
-		"destination count (estimation)", destinationCount)
+		"destination count", destinationCount,
+		"count rejected", numRejected)
+
+	// the destination written from several sources also holds the documents of the other sources
+	allowExtraDocuments := r.sharedDestination || r.documents.allowsExtraDocuments(index)
+	if !areCountsMatching(originalSourceCount, destinationCount, numRejected, allowExtraDocuments) {
+		return fmt.Errorf("%w for index %s, count source %d, count destination %d, count rejected %d",
+			errCountsMismatch, index, originalSourceCount, destinationCount, numRejected)
+	}
 
 	return nil
 }
 
+// CopyMappingIfNecessary will create the index with the source's mapping and its alias in the destination, if they do not exist
+func (r *reindexer) CopyMappingIfNecessary(index string, overwrite bool, skipMappings bool) error {
+	return r.copyMappingIfNecessary(index, overwrite, skipMappings)
+}
+
 func (r *reindexer) copyMappingIfNecessary(index string, overwrite bool, skipMappings bool) error {
+	getMapping := func() (*bytes.Buffer, error) {
+		return r.sourceElastic.GetMapping(r.names.source(index))
+	}
+
+	return r.createIndexAndAliasIfNecessary(index, overwrite, skipMappings, getMapping)
+}
+
+func (r *reindexer) createIndexAndAliasIfNecessary(
+	index string,
+	overwrite bool,
+	skipMappings bool,
+	getMapping func() (*bytes.Buffer, error),
+) error {
 	if skipMappings {
 		return nil
 	}
 
-	indexWithSuffix := index + indexSuffix
-
-	aliasExists := r.destinationElastic.DoesAliasExist(index)
-	if aliasExists && !overwrite {
-		return fmt.Errorf("index with alias %s already exists. Please clean the destination indexer before"+
-			" retrying, or start the tool using --overwrite flag", index)
+	destinationIndex := r.names.destination(index)
+	plan, err := planMappingCopy(r.destinationElastic, destinationIndex, overwrite)
+	if err != nil {
+		return err
 	}
 
-	indexExists := r.destinationElastic.DoesIndexExist(indexWithSuffix)
-	if indexExists && !overwrite {
-		return fmt.Errorf("index %s already exists. Please clean the destination indexer before"+
-			" retrying, or start the tool using --overwrite flag", index)
+	indexWithSuffix := destinationIndex + indexSuffix
+	var indexBody *bytes.Buffer
+	if plan.createIndex {
+		indexBody, _, err = getIndexBody(r.mappingsConfig.TemplatesDirectory, index, getMapping)
+		if err != nil {
+			return err
+		}
 	}
 
-	if !indexExists {
-		sourceMapping, err := r.sourceElastic.GetMapping(index)
+	if shouldCompareMappings(r.mappingsConfig, r.getIndexSettings(index), index) {
+		err = r.checkMappingsCompatibility(index, plan, getMapping, indexBody)
 		if err != nil {
-			return fmt.Errorf("error while getting mapping from source: %w", err)
+			return err
 		}
+	}
 
-		err = r.destinationElastic.CreateIndexWithMapping(indexWithSuffix, sourceMapping)
+	if plan.createIndex {
+		err = r.destinationElastic.CreateIndexWithMapping(indexWithSuffix, indexBody)
 		if err != nil {
 			return fmt.Errorf("error while creating index with mapping to destination: %w", err)
 		}
 	}
 
-	if aliasExists {
+	if !plan.putAlias {
+		return nil
+	}
+
+	return r.destinationElastic.PutAlias(indexWithSuffix, destinationIndex)
+}
+
+// getIndexBody returns the body used to create the destination index: the template of the index, if a templates
+// directory is configured and it contains one, or the source mapping otherwise. The returned flag is true if the body
+// is the template
+func getIndexBody(templatesDirectory string, index string, getMapping func() (*bytes.Buffer, error)) (*bytes.Buffer, bool, error) {
+	if templatesDirectory != "" {
+		body, err := reader.GetIndexBodyFromTemplate(templatesDirectory, index)
+		if err == nil {
+			log.Info("the destination index will be created from template", "index", index,
+				"templates directory", templatesDirectory)
+			return body, true, nil
+		}
+		if !errors.Is(err, os.ErrNotExist) {
+			return nil, false, fmt.Errorf("%w while reading the template of index %s", err, index)
+		}
+
+		log.Info("no template found, the destination index will be created from the source mapping", "index", index)
+	}
+
+	sourceMapping, err := getMapping()
+	if err != nil {
+		return nil, false, fmt.Errorf("error while getting mapping from source: %w", err)
+	}
+
+	return sourceMapping, false, nil
+}
+
+// shouldCompareMappings returns true if the mappings comparison is enabled and the documents of the index are not
+// transformed, as the source mapping still holds the fields that are excluded or renamed in the destination
+func shouldCompareMappings(mappingsConfig config.MappingsConfig, settings *indexSettings, index string) bool {
+	if !mappingsConfig.Compare {
+		return false
+	}
+	if settings.transformer != nil {
+		log.Info("the mappings are not compared because the documents are transformed", "index", index)
+		return false
+	}
+
+	return true
+}
+
+// checkMappingsCompatibility compares the source mapping with the mapping of the destination index: the existing one
+// or the one it will be created with
+func (r *reindexer) checkMappingsCompatibility(
+	index string,
+	plan *mappingPlan,
+	getMapping func() (*bytes.Buffer, error),
+	indexBody *bytes.Buffer,
+) error {
+	diff, err := computeDestinationMappingDiff(r.destinationElastic, r.names.destination(index), plan, getMapping, indexBody)
+	if err != nil {
+		return err
+	}
+	if diff.isEmpty() {
+		return nil
+	}
+
+	if diff.isCompatible() {
+		log.Info("the destination mapping has additional fields", "index", index, "diff", diff.String())
+		return nil
+	}
+	if r.mappingsConfig.ForceIncompatible {
+		log.Warn("incompatible mappings, continuing because the incompatible mappings are forced", "index", index, "diff", diff.String())
+		return nil
+	}
+
+	return fmt.Errorf("%w for index %s: %s. Fix the destination mapping or start the tool using --force-mappings flag",
+		errIncompatibleMappings, index, diff.String())
+}
+
+// computeDestinationMappingDiff returns the differences between the source mapping and the mapping of the destination
+// index: the existing one or the one it will be created with
+func computeDestinationMappingDiff(
+	destination ElasticClientHandler,
+	destinationIndex string,
+	plan *mappingPlan,
+	getMapping func() (*bytes.Buffer, error),
+	indexBody *bytes.Buffer,
+) (*mappingDiff, error) {
+	sourceMapping, err := getMapping()
+	if err != nil {
+		return nil, fmt.Errorf("error while getting mapping from source: %w", err)
+	}
+
+	destinationMapping := indexBody
+	if plan.indexExists {
+		destinationMapping, err = destination.GetMapping(destinationIndex)
+		if err != nil {
+			return nil, fmt.Errorf("error while getting mapping from destination: %w", err)
+		}
+	}
+
+	return computeMappingDiff(bufferBytes(sourceMapping), bufferBytes(destinationMapping))
+}
+
+func bufferBytes(buff *bytes.Buffer) []byte {
+	if buff == nil {
 		return nil
 	}
 
-	return r.destinationElastic.PutAlias(indexWithSuffix, index)
+	return buff.Bytes()
+}
+
+// mappingPlan describes what has to be created in the destination before the documents of an index can be copied
+type mappingPlan struct {
+	aliasExists bool
+	indexExists bool
+	createIndex bool
+	putAlias    bool
 }
 
-func (r *reindexer) reindexData(index string) error {
+func planMappingCopy(destination ElasticClientHandler, index string, overwrite bool) (*mappingPlan, error) {
+	plan := &mappingPlan{}
+
+	plan.aliasExists = destination.DoesAliasExist(index)
+	if plan.aliasExists && !overwrite {
+		return plan, fmt.Errorf("index with alias %s already exists. Please clean the destination indexer before"+
+			" retrying, or start the tool using --overwrite flag", index)
+	}
+
+	plan.indexExists = destination.DoesIndexExist(index + indexSuffix)
+	if plan.indexExists && !overwrite {
+		return plan, fmt.Errorf("index %s already exists. Please clean the destination indexer before"+
+			" retrying, or start the tool using --overwrite flag", index)
+	}
+
+	plan.createIndex = !plan.indexExists
+	plan.putAlias = !plan.aliasExists
+
+	return plan, nil
+}
+
+// reindexData copies all the documents of the index and returns the number of documents rejected by the destination
+func (r *reindexer) reindexData(index string) (uint64, error) {
 	count := 0
+	numRejected := uint64(0)
 	handlerFunc := func(responseBytes []byte) error {
 		count++
-		dataBuffers, err := prepareDataForIndexing(responseBytes, index, count)
+		esResponse, err := unmarshalEsResponse(responseBytes)
 		if err != nil {
-			return fmt.Errorf("%w while preparing data for indexing", err)
+			return err
 		}
 
-		for i := 0; i < len(dataBuffers); i++ {
-			err = r.destinationElastic.DoBulkRequest(dataBuffers[i], index)
-			if err != nil {
-				return fmt.Errorf("%w while r.destinationElastic.DoBulkRequest", err)
-			}
+		dataBuffers, err := r.prepareDataForIndexing(esResponse, index, count)
+		if err != nil {
+			return fmt.Errorf("%w while preparing data for indexing", err)
 		}
 
-		return nil
+		rejectedIDs, err := r.doBulkRequests(dataBuffers, index)
+		numRejected += uint64(len(rejectedIDs))
+
+		return err
 	}
 
-	err := r.sourceElastic.DoScrollRequestAllDocuments(index, getAll().Bytes(), handlerFunc)
+	query := getAll(r.pageSize, r.getIndexSettings(index).query)
+	err := r.sourceElastic.DoScrollRequestAllDocuments(r.names.source(index), query.Bytes(), handlerFunc)
 	if err != nil {
-		return fmt.Errorf("%w while r.sourceElastic.DoScrollRequestAllDocuments", err)
+		return 0, fmt.Errorf("%w while r.sourceElastic.DoScrollRequestAllDocuments", err)
 	}
 
-	return nil
+	return numRejected, nil
 }
 
-func prepareDataForIndexing(responseBytes []byte, index string, count int) ([]*bytes.Buffer, error) {
-	var esResponse generalElasticResponse
-	err := json.Unmarshal(responseBytes, &esResponse)
+func unmarshalEsResponse(responseBytes []byte) (*generalElasticResponse, error) {
+	esResponse := &generalElasticResponse{}
+	err := json.Unmarshal(responseBytes, esResponse)
 	if err != nil {
 		return nil, err
 	}
 
-	resultsMap := extractSourceFromEsResponse(esResponse)
-	log.Info("\tindexing", "index", index, "bulk size", len(resultsMap), "count", count)
-	buffSlice := newBufferSlice()
-	for id, source := range resultsMap {
-		meta := []byte(fmt.Sprintf(`{ "index" : { "_id" : "%s" } }%s`, id, "\n"))
+	return esResponse, nil
+}
+
+// doBulkRequests writes the provided buffers in the destination and returns the IDs of the documents rejected by it
+func (r *reindexer) doBulkRequests(dataBuffers []*bytes.Buffer, index string) ([]string, error) {
+	rejectedIDs := make([]string, 0)
+	for i := 0; i < len(dataBuffers); i++ {
+		numDocuments := bytes.Count(dataBuffers[i].Bytes(), []byte("\n")) / 2
+		numBytes := dataBuffers[i].Len()
+		r.rateLimiter.Wait(numDocuments, numBytes)
 
-		err = buffSlice.PutData(meta, source)
+		numSkipped, rejectedIDsInBulk, err := r.doBulkRequestWithItemsRetries(dataBuffers[i], index)
+		rejectedIDs = append(rejectedIDs, rejectedIDsInBulk...)
 		if err != nil {
-			return nil, err
+			r.metrics.AddBulkError(index)
+			return rejectedIDs, fmt.Errorf("%w while r.destinationElastic.DoBulkRequest", err)
 		}
 
+		r.metrics.AddDocumentsWritten(index, uint64(numDocuments-numSkipped-len(rejectedIDsInBulk)), uint64(numBytes))
 	}
 
-	return buffSlice.Buffers(), nil
+	return rejectedIDs, nil
 }
 
-// ProcessIndexWithTimestamp will handle the reindexing from source Elastic client to destination Elastic client based on the provided interval
-func (r *reindexer) ProcessIndexWithTimestamp(index string, overwrite bool, skipMappings bool, start, stop int64, count *uint64) error {
-	err := r.copyMappingIfNecessary(index, overwrite, skipMappings)
-	if err != nil {
-		return fmt.Errorf("%w while copying the mapping for index %s", err, index)
+func (r *reindexer) prepareDataForIndexing(esResponse *generalElasticResponse, index string, count int) ([]*bytes.Buffer, error) {
+	hits := esResponse.Hits.Hits
+	log.Info("\tindexing", "index", index, "bulk size", len(hits), "count", count)
+	r.metrics.AddDocumentsRead(index, uint64(len(hits)))
+	settings := r.getIndexSettings(index)
+	buffSlice := newBufferSlice(r.bulkSizeThreshold)
+	for _, hit := range hits {
+		transformedSource, err := settings.transformSource(hit.Source)
+		if err != nil {
+			return nil, fmt.Errorf("%w for document with id %s", err, hit.ID)
+		}
+
+		meta, document, err := r.documents.bulkLines(index, hit, transformedSource)
+		if err != nil {
+			return nil, fmt.Errorf("%w for document with id %s", err, hit.ID)
+		}
+
+		err = buffSlice.PutData(meta, document)
+		if err != nil {
+			return nil, err
+		}
 	}
 
-	scrollRequestHandlerFunc := r.createScrollRequestHandlerFunction(count, index)
-	err = r.sourceElastic.DoScrollRequestAllDocuments(index, getWithTimestamp(start, stop, true, true).Bytes(), scrollRequestHandlerFunc)
+	return buffSlice.Buffers(), nil
+}
+
+// ProcessIndexWithTimestamp will handle the reindexing from source Elastic client to destination Elastic client based on the provided interval.
+// The mapping should be already copied by calling CopyMappingIfNecessary. The progress handler is called with the highest
+// timestamp of every page, after the page was written in the destination, and with the number of documents of the page
+// rejected by the destination
+func (r *reindexer) ProcessIndexWithTimestamp(
+	index string,
+	start, stop int64,
+	count *uint64,
+	progressHandler func(lastTimestamp int64, rejectedIDs []string) error,
+) error {
+	scrollRequestHandlerFunc := r.createScrollRequestHandlerFunction(count, index, progressHandler)
+	query := getWithTimestamp(start, stop, true, true, r.pageSize, r.getIndexSettings(index).query)
+	err := r.sourceElastic.DoScrollRequestAllDocuments(r.names.source(index), query.Bytes(), scrollRequestHandlerFunc)
 	if err != nil {
 		return fmt.Errorf("%w while r.sourceElastic.DoScrollRequestAllDocuments", err)
 	}
@@ -196,15 +511,21 @@ func (r *reindexer) ProcessIndexWithTimestamp(index string, overwrite bool, skip
 	return nil
 }
 
-// GetCountsForInterval will return the counts from source and destination client based on the provided intervals
+// GetCountsForInterval will return the counts from source and destination client based on the provided intervals. The
+// destination is refreshed first, so the documents just written are counted
 func (r *reindexer) GetCountsForInterval(index string, start, stop int64) (uint64, uint64, error) {
-	body := getWithTimestamp(start, stop, false, false).Bytes()
+	body := getWithTimestamp(start, stop, false, false, 0, r.getIndexSettings(index).query).Bytes()
 
-	countFromSource, err := r.sourceElastic.GetCountWithBody(index, body)
+	err := r.destinationElastic.RefreshIndex(r.names.destination(index))
+	if err != nil {
+		return 0, 0, fmt.Errorf("%w while refreshing the destination index", err)
+	}
+
+	countFromSource, err := r.sourceElastic.GetCountWithBody(r.names.source(index), body)
 	if err != nil {
 		return 0, 0, err
 	}
-	countFromDestination, err := r.destinationElastic.GetCountWithBody(index, body)
+	countFromDestination, err := r.destinationElastic.GetCountWithBody(r.names.destination(index), body)
 	if err != nil {
 		return 0, 0, err
 	}
@@ -212,20 +533,42 @@ func (r *reindexer) GetCountsForInterval(index string, start, stop int64) (uint6
 	return countFromSource, countFromDestination, nil
 }
 
-func (r *reindexer) createScrollRequestHandlerFunction(count *uint64, index string) func([]byte) error {
+// GetSourcesBoundaries returns no boundary, the documents being read from a single source
+func (r *reindexer) GetSourcesBoundaries(_, _ int64) []int64 {
+	return nil
+}
+
+func (r *reindexer) createScrollRequestHandlerFunction(
+	count *uint64,
+	index string,
+	progressHandler func(lastTimestamp int64, rejectedIDs []string) error,
+) func([]byte) error {
 	return func(responseBytes []byte) error {
 		atomic.AddUint64(count, 1)
-		dataBuffers, errP := prepareDataForIndexing(responseBytes, index, int(atomic.LoadUint64(count)))
+		esResponse, errU := unmarshalEsResponse(responseBytes)
+		if errU != nil {
+			return errU
+		}
+
+		dataBuffers, errP := r.prepareDataForIndexing(esResponse, index, int(atomic.LoadUint64(count)))
 		if errP != nil {
 			return fmt.Errorf("%w while preparing data for indexing", errP)
 		}
 
-		for i := 0; i < len(dataBuffers); i++ {
-			err := r.destinationElastic.DoBulkRequest(dataBuffers[i], index)
-			if err != nil {
-				return fmt.Errorf("%w while r.destinationElastic.DoBulkRequest", err)
-			}
+		rejectedIDs, err := r.doBulkRequests(dataBuffers, index)
+		if err != nil {
+			return err
 		}
-		return nil
+
+		if progressHandler == nil {
+			return nil
+		}
+
+		lastTimestamp, found := extractLastTimestampFromEsResponse(esResponse)
+		if !found {
+			return nil
+		}
+
+		return progressHandler(lastTimestamp, rejectedIDs)
 	}
 }
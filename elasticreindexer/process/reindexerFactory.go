@@ -2,17 +2,189 @@ package process
 
 import (
 	"errors"
+	"fmt"
+	"io"
 
+	"github.com/multiversx/mx-chain-core-go/core/check"
 	"github.com/multiversx/mx-chain-tools-go/elasticreindexer/config"
 	"github.com/multiversx/mx-chain-tools-go/elasticreindexer/elastic"
 )
 
-// CreateReindexer will create the source and destination elastic handlers and create a reindexer based on them
-func CreateReindexer(cfg *config.GeneralConfig) (*reindexer, error) {
-	if cfg.Indexers.Input.URL == "" {
+// CreateReindexer will create the source and destination elastic handlers and create a reindexer based on them. The
+// reindexing progress, including the requests retried by the elastic clients, is reported to the metrics handler and
+// the documents rejected by the destination are written in the dead-letter handler
+func CreateReindexer(cfg *config.GeneralConfig, metricsHandler MetricsHandler, deadLetter DeadLetterHandler) (*reindexer, error) {
+	if check.IfNil(metricsHandler) {
+		return nil, errNilMetricsHandler
+	}
+
+	sourceElastic, destinationElastic, err := createElasticClients(cfg, metricsHandler)
+	if err != nil {
+		return nil, err
+	}
+
+	args := createReindexerArgs(cfg, sourceElastic, destinationElastic, cfg.Indexers.Input.Tag, metricsHandler, deadLetter)
+
+	return newReindexer(args)
+}
+
+// CreateImporter will create the destination elastic handler and create an importer based on it. The input cluster is
+// not needed, as the documents are read from the export files
+func CreateImporter(cfg *config.GeneralConfig, metricsHandler MetricsHandler, deadLetter DeadLetterHandler) (IndexImporter, error) {
+	if check.IfNil(metricsHandler) {
+		return nil, errNilMetricsHandler
+	}
+
+	destinationElastic, err := createElasticClient(cfg.Indexers.Output, "output", metricsHandler)
+	if err != nil {
+		return nil, err
+	}
+
+	args := createReindexerArgs(cfg, nil, destinationElastic, cfg.Indexers.Input.Tag, metricsHandler, deadLetter)
+
+	return newImporter(args)
+}
+
+// CreateMultiSourceReindexer will create a reindexer for every configured input, all of them writing in the same
+// destination, and a multi-source reindexer choosing between them
+func CreateMultiSourceReindexer(cfg *config.GeneralConfig, metricsHandler MetricsHandler, deadLetter DeadLetterHandler) (*multiSourceReindexer, error) {
+	if check.IfNil(metricsHandler) {
+		return nil, errNilMetricsHandler
+	}
+	if len(cfg.Indexers.Inputs) == 0 {
+		return nil, errNoSources
+	}
+	if isElasticConfigured(cfg.Indexers.Input) {
+		return nil, errors.New("the input and the inputs cannot be configured together")
+	}
+
+	destinationElastic, err := createElasticClient(cfg.Indexers.Output, "output", metricsHandler)
+	if err != nil {
+		return nil, err
+	}
+
+	sources := make([]*SourceReindexer, 0, len(cfg.Indexers.Inputs))
+	for idx, input := range cfg.Indexers.Inputs {
+		name := input.Tag
+		if name == "" {
+			name = fmt.Sprintf("input-%d", idx)
+		}
+
+		sourceElastic, errCreate := createElasticClient(input.ElasticInstanceConfig, name, metricsHandler)
+		if errCreate != nil {
+			return nil, fmt.Errorf("%w for input %s", errCreate, name)
+		}
+
+		args := createReindexerArgs(cfg, sourceElastic, destinationElastic, input.Tag, metricsHandler, deadLetter)
+		// all the inputs write in the same destination
+		args.SharedDestination = true
+		sourceReindexer, errCreate := newReindexer(args)
+		if errCreate != nil {
+			return nil, fmt.Errorf("%w for input %s", errCreate, name)
+		}
+
+		sources = append(sources, &SourceReindexer{
+			Name:      name,
+			Reindexer: sourceReindexer,
+			Priority:  input.Priority,
+			StartTime: input.StartTime,
+			StopTime:  input.StopTime,
+		})
+	}
+
+	return NewMultiSourceReindexer(ArgsMultiSourceReindexer{
+		Sources: sources,
+	})
+}
+
+func createReindexerArgs(
+	cfg *config.GeneralConfig,
+	sourceElastic ElasticClientHandler,
+	destinationElastic ElasticClientHandler,
+	sourceTag string,
+	metricsHandler MetricsHandler,
+	deadLetter DeadLetterHandler,
+) ArgsReindexer {
+	indicesConfig := cfg.Indexers.IndicesConfig
+
+	return ArgsReindexer{
+		SourceElastic:      sourceElastic,
+		DestinationElastic: destinationElastic,
+		Indices:            indicesConfig.Indices,
+		PageSize:           indicesConfig.PageSize,
+		BulkSizeThreshold:  indicesConfig.BulkSizeThresholdInBytes,
+		RateLimiter:        NewRateLimiter(indicesConfig.Throttling.DocumentsPerSecond, indicesConfig.Throttling.BytesPerSecond),
+		IndicesSettings:    indicesConfig.PerIndex,
+		Mappings:           indicesConfig.Mappings,
+		SourcePrefix:       indicesConfig.SourceIndexPrefix,
+		DestinationPrefix:  indicesConfig.DestinationIndexPrefix,
+		DestinationIndices: indicesConfig.DestinationIndices,
+		Metrics:            metricsHandler,
+		BulkItems:          indicesConfig.BulkItems,
+		DeadLetter:         deadLetter,
+		Documents:          indicesConfig.Documents,
+		SourceTag:          sourceTag,
+	}
+}
+
+// CreateVerifier will create the source and destination elastic handlers and create a verifier based on them
+func CreateVerifier(cfg *config.GeneralConfig, reportWriter io.Writer) (*verifier, error) {
+	sourceElastic, destinationElastic, err := createElasticClients(cfg, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	args := ArgsVerifier{
+		SourceElastic:      sourceElastic,
+		DestinationElastic: destinationElastic,
+		IndicesConfig:      cfg.Indexers.IndicesConfig,
+		ReportWriter:       reportWriter,
+		SourceTag:          cfg.Indexers.Input.Tag,
+	}
+
+	return NewVerifier(args)
+}
+
+// CreatePlanner will create the source and destination elastic handlers and create a planner based on them
+func CreatePlanner(cfg *config.GeneralConfig) (*planner, error) {
+	sourceElastic, destinationElastic, err := createElasticClients(cfg, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	args := ArgsPlanner{
+		SourceElastic:      sourceElastic,
+		DestinationElastic: destinationElastic,
+		IndicesConfig:      cfg.Indexers.IndicesConfig,
+	}
+
+	return NewPlanner(args)
+}
+
+// CreateExporter will create the source elastic handler and create an exporter based on it
+func CreateExporter(cfg *config.GeneralConfig, directory string, compress bool) (*exporter, error) {
+	if !isElasticConfigured(cfg.Indexers.Input) {
 		return nil, errors.New("empty url for the input cluster")
 	}
-	if cfg.Indexers.Output.URL == "" {
+
+	sourceElastic, err := elastic.NewElasticClient(cfg.Indexers.Input)
+	if err != nil {
+		return nil, err
+	}
+
+	args := ArgsExporter{
+		SourceElastic: sourceElastic,
+		IndicesConfig: cfg.Indexers.IndicesConfig,
+		Directory:     directory,
+		Compress:      compress,
+	}
+
+	return NewExporter(args)
+}
+
+// CreateAliasSwapper will create the source and destination elastic handlers and create an alias swapper based on them
+func CreateAliasSwapper(cfg *config.GeneralConfig) (*aliasSwapper, error) {
+	if !isElasticConfigured(cfg.Indexers.Output) {
 		return nil, errors.New("empty url for the output cluster")
 	}
 
@@ -26,5 +198,59 @@ func CreateReindexer(cfg *config.GeneralConfig) (*reindexer, error) {
 		return nil, err
 	}
 
-	return newReindexer(sourceElastic, destinationElastic, cfg.Indexers.IndicesConfig.Indices)
+	args := ArgsAliasSwapper{
+		SourceElastic:      sourceElastic,
+		DestinationElastic: destinationElastic,
+		IndicesConfig:      cfg.Indexers.IndicesConfig,
+	}
+
+	return NewAliasSwapper(args)
+}
+
+// createElasticClients creates the source and destination elastic handlers. If a metrics handler is provided, the
+// retried requests are reported to it
+func createElasticClients(cfg *config.GeneralConfig, metricsHandler MetricsHandler) (ElasticClientHandler, ElasticClientHandler, error) {
+	sourceElastic, err := createElasticClient(cfg.Indexers.Input, "input", metricsHandler)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	destinationElastic, err := createElasticClient(cfg.Indexers.Output, "output", metricsHandler)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return sourceElastic, destinationElastic, nil
+}
+
+// createElasticClient creates the elastic handler of the provided cluster. If a metrics handler is provided, the
+// retried requests are reported to it under the provided name
+func createElasticClient(cfg config.ElasticInstanceConfig, name string, metricsHandler MetricsHandler) (ElasticClientHandler, error) {
+	if !isElasticConfigured(cfg) {
+		return nil, fmt.Errorf("empty url for the %s cluster", name)
+	}
+
+	elasticClient, err := elastic.NewElasticClient(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	if !check.IfNil(metricsHandler) {
+		elasticClient.SetRetryHandler(func() {
+			metricsHandler.AddRequestRetry(name)
+		})
+	}
+
+	return elasticClient, nil
+}
+
+// isElasticConfigured returns true if the cluster can be reached either by its url, its addresses or its cloud id
+func isElasticConfigured(cfg config.ElasticInstanceConfig) bool {
+	for _, address := range cfg.Addresses {
+		if address != "" {
+			return true
+		}
+	}
+
+	return cfg.URL != "" || cfg.CloudID != ""
 }
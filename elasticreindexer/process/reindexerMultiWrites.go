@@ -2,12 +2,17 @@ package process
 
 import (
 	"errors"
+	"fmt"
 	"sync"
 	"time"
 
+	"github.com/multiversx/mx-chain-core-go/core/check"
 	"github.com/multiversx/mx-chain-tools-go/elasticreindexer/config"
 )
 
+// defaultDelayBetweenIntervals is the delay between the start of two consecutive intervals used when none is configured
+const defaultDelayBetweenIntervals = time.Second
+
 type interval struct {
 	start int64
 	stop  int64
@@ -20,16 +25,55 @@ type reindexerMultiWrite struct {
 	blockChainStartTime  int64
 	enabled              bool
 
+	numRetriesFailedIntervals int
+	delayBetweenIntervals     time.Duration
+
 	reindexerClient ReindexerHandler
+	checkpoint      CheckpointHandler
+	metrics         MetricsHandler
+	documents       *documentsWriter
 }
 
-func NewReindexerMultiWrite(reindexer ReindexerHandler, cfg config.IndicesConfig) (*reindexerMultiWrite, error) {
+// NewReindexerMultiWrite creates a new instance of reindexerMultiWrite
+func NewReindexerMultiWrite(
+	reindexer ReindexerHandler,
+	cfg config.IndicesConfig,
+	checkpoint CheckpointHandler,
+	metrics MetricsHandler,
+) (*reindexerMultiWrite, error) {
 	if reindexer == nil {
 		return nil, errors.New("nil ReindexerHandler")
 	}
+	if check.IfNil(checkpoint) {
+		return nil, errors.New("nil CheckpointHandler")
+	}
+	if check.IfNil(metrics) {
+		return nil, errNilMetricsHandler
+	}
 	if cfg.WithTimestamp.BlockchainStartTime <= 0 {
 		return nil, errors.New("blockchainStartTime cannot be less than zero")
 	}
+	if cfg.WithTimestamp.NumRetriesFailedIntervals < 0 {
+		return nil, errors.New("numRetriesFailedIntervals cannot be negative")
+	}
+	if cfg.WithTimestamp.DelayBetweenIntervalsStartInMs < 0 {
+		return nil, errors.New("delayBetweenIntervalsStartInMs cannot be negative")
+	}
+
+	err := checkTimestampFieldKept(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	documents, err := newDocumentsWriter(cfg.Documents, cfg.PerIndex, "")
+	if err != nil {
+		return nil, err
+	}
+
+	delayBetweenIntervals := time.Duration(cfg.WithTimestamp.DelayBetweenIntervalsStartInMs) * time.Millisecond
+	if delayBetweenIntervals == 0 {
+		delayBetweenIntervals = defaultDelayBetweenIntervals
+	}
 
 	return &reindexerMultiWrite{
 		reindexerClient:      reindexer,
@@ -38,16 +82,40 @@ func NewReindexerMultiWrite(reindexer ReindexerHandler, cfg config.IndicesConfig
 		numParallelWrite:     cfg.WithTimestamp.NumParallelWrites,
 		blockChainStartTime:  cfg.WithTimestamp.BlockchainStartTime,
 		enabled:              cfg.WithTimestamp.Enabled,
+		checkpoint:           checkpoint,
+		metrics:              metrics,
+		documents:            documents,
+
+		numRetriesFailedIntervals: cfg.WithTimestamp.NumRetriesFailedIntervals,
+		delayBetweenIntervals:     delayBetweenIntervals,
 	}, nil
 }
 
+// ProcessNoTimestamp will reindex, one by one, all the indices without timestamp, skipping the ones already
+// completed according to the checkpoint
 func (rmw *reindexerMultiWrite) ProcessNoTimestamp(overwrite bool, skipMappings bool) error {
 	for _, index := range rmw.indicesNoTimestamp {
 		if index == "" {
 			continue
 		}
+		if rmw.checkpoint.IsIndexCompleted(index) {
+			log.Info("index already reindexed, skipping", "index", index)
+			continue
+		}
+
+		// a previously started index already has its mapping and alias in the destination
+		overwriteIndex := overwrite || rmw.checkpoint.IsIndexStarted(index)
+		err := rmw.checkpoint.MarkIndexStarted(index)
+		if err != nil {
+			return err
+		}
+
+		err = rmw.reindexerClient.Process(overwriteIndex, skipMappings, index)
+		if err != nil {
+			return err
+		}
 
-		err := rmw.reindexerClient.Process(overwrite, skipMappings, index)
+		err = rmw.checkpoint.MarkIndexCompleted(index)
 		if err != nil {
 			return err
 		}
@@ -56,75 +124,264 @@ func (rmw *reindexerMultiWrite) ProcessNoTimestamp(overwrite bool, skipMappings
 	return nil
 }
 
-func (rmw *reindexerMultiWrite) ProcessWithTimestamp(overwrite bool, skipMappings bool) error {
+// ProcessWithTimestamp will reindex all the indices with timestamp, by splitting them in intervals that are
+// processed in parallel. Completed intervals are skipped and partial ones are restarted from their last checkpoint.
+// It returns a result for every processed index, the error being returned only when the processing cannot continue
+func (rmw *reindexerMultiWrite) ProcessWithTimestamp(overwrite bool, skipMappings bool) ([]*IndexResult, error) {
 	if !rmw.enabled {
-		return nil
+		return nil, nil
+	}
+
+	stopTimestamp := rmw.checkpoint.StopTimestamp()
+	if stopTimestamp == 0 {
+		stopTimestamp = time.Now().Unix()
+		err := rmw.checkpoint.SetStopTimestamp(stopTimestamp)
+		if err != nil {
+			return nil, err
+		}
 	}
 
-	currentTimestampUnix := time.Now().Unix()
-	intervals, err := computeIntervals(rmw.blockChainStartTime, currentTimestampUnix, int64(rmw.numParallelWrite))
+	intervals, err := computeIntervals(rmw.blockChainStartTime, stopTimestamp, int64(rmw.numParallelWrite))
 	if err != nil {
-		return err
+		return nil, err
 	}
+	// every interval is read from a single source
+	intervals = splitIntervals(intervals, rmw.reindexerClient.GetSourcesBoundaries(rmw.blockChainStartTime, stopTimestamp))
 
+	results := make([]*IndexResult, 0, len(rmw.indicesWithTimestamp))
 	for _, index := range rmw.indicesWithTimestamp {
 		if index == "" {
 			continue
 		}
+		if rmw.checkpoint.IsIndexCompleted(index) {
+			log.Info("index already reindexed, skipping", "index", index)
+			continue
+		}
 
-		err = rmw.reindexBasedOnIntervals(index, intervals, overwrite, skipMappings)
+		// a previously started index already has its mapping and alias in the destination
+		overwriteIndex := overwrite || rmw.checkpoint.IsIndexStarted(index)
+		indexIntervals, errIntervals := rmw.getIntervalsForIndex(index, intervals)
+		if errIntervals != nil {
+			return results, errIntervals
+		}
+
+		err = rmw.reindexerClient.CopyMappingIfNecessary(index, overwriteIndex, skipMappings)
 		if err != nil {
-			return err
+			results = append(results, &IndexResult{
+				Index:           index,
+				IntervalsFailed: len(indexIntervals),
+				Errors:          []error{fmt.Errorf("%w while copying the mapping for index %s", err, index)},
+			})
+			continue
 		}
+
+		results = append(results, rmw.reindexBasedOnIntervals(index, indexIntervals))
 	}
 
-	return nil
+	return results, nil
 }
 
-func (rmw *reindexerMultiWrite) reindexBasedOnIntervals(
-	index string,
-	intervals []*interval,
-	overwrite bool,
-	skipMappings bool,
-) error {
-	wg := &sync.WaitGroup{}
-	wg.Add(len(intervals))
+func (rmw *reindexerMultiWrite) getIntervalsForIndex(index string, intervals []*interval) ([]*IntervalProgress, error) {
+	indexIntervals := rmw.checkpoint.GetIntervals(index)
+	if len(indexIntervals) > 0 {
+		return indexIntervals, nil
+	}
+
+	indexIntervals = make([]*IntervalProgress, 0, len(intervals))
+	for _, interv := range intervals {
+		indexIntervals = append(indexIntervals, &IntervalProgress{
+			Start: interv.start,
+			Stop:  interv.stop,
+		})
+	}
 
+	return indexIntervals, rmw.checkpoint.SetIntervals(index, indexIntervals)
+}
+
+func (rmw *reindexerMultiWrite) reindexBasedOnIntervals(index string, intervals []*IntervalProgress) *IndexResult {
 	log.Info("starting reindexing", "index", index)
 
+	result := &IndexResult{
+		Index: index,
+	}
 	count := uint64(0)
+	intervalErrors := make([]error, len(intervals))
 
+	pendingIntervals := make([]int, 0, len(intervals))
 	for idx, interv := range intervals {
-		go func(startTime, stopTime int64, idx int, w *sync.WaitGroup) {
-			defer func() {
-				time.Sleep(time.Second)
-				countSource, countDestination, err := rmw.reindexerClient.GetCountsForInterval(index, startTime, stopTime)
-				if err != nil {
-					log.Warn("cannot get count", "interval nr", idx, "index", index, "error", err)
-				}
-
-				if countSource != countDestination {
-					log.Warn("counts are not equals", "interval nr", idx, "count source", countSource, "count destination", countDestination)
-				}
-
-				log.Info("done", "interval nr", idx, "count source", countSource, "count destination", countDestination)
-				w.Done()
-			}()
-
-			errIndex := rmw.reindexerClient.ProcessIndexWithTimestamp(index, overwrite, skipMappings, startTime, stopTime, &count)
-			if errIndex != nil {
-				log.Warn("rmw.processIndexWithTimestamp", "index", index, "error", errIndex.Error())
+		if interv.Completed {
+			log.Info("interval already reindexed, skipping", "interval nr", idx, "index", index)
+			continue
+		}
+
+		pendingIntervals = append(pendingIntervals, idx)
+	}
+	rmw.metrics.SetIntervals(index, len(intervals), len(intervals)-len(pendingIntervals))
+	rmw.setExpectedDocuments(index, intervals)
+
+	for attempt := 0; ; attempt++ {
+		rmw.processIntervals(index, intervals, pendingIntervals, &count, intervalErrors)
+
+		failedIntervals := make([]int, 0)
+		for _, idx := range pendingIntervals {
+			if intervalErrors[idx] != nil {
+				failedIntervals = append(failedIntervals, idx)
+			}
+		}
+		if len(failedIntervals) == 0 || attempt >= rmw.numRetriesFailedIntervals {
+			break
+		}
+
+		log.Warn("retrying failed intervals", "index", index, "num failed intervals", len(failedIntervals), "attempt", attempt+1)
+		for range failedIntervals {
+			rmw.metrics.AddIntervalRetry(index)
+		}
+		// the intervals are reloaded, so the retries start from the last checkpoint
+		intervals = rmw.checkpoint.GetIntervals(index)
+		pendingIntervals = failedIntervals
+	}
+
+	for _, errInterval := range intervalErrors {
+		if errInterval != nil {
+			result.IntervalsFailed++
+			result.Errors = append(result.Errors, errInterval)
+			continue
+		}
+
+		result.IntervalsSucceeded++
+	}
+
+	var err error
+	result.SourceCount, result.DestinationCount, err = rmw.reindexerClient.GetCountsForInterval(index, intervals[0].Start, intervals[len(intervals)-1].Stop)
+	if err != nil {
+		result.Errors = append(result.Errors, fmt.Errorf("%w while getting the counts for index %s", err, index))
+	}
+	result.RejectedCount = rmw.getNumRejected(index, -1)
+	result.AllowExtraDocuments = rmw.documents.allowsExtraDocuments(index)
+
+	if !result.IsSuccessful() {
+		return result
+	}
+
+	err = rmw.checkpoint.MarkIndexCompleted(index)
+	if err != nil {
+		result.Errors = append(result.Errors, err)
+	}
+
+	return result
+}
+
+// setExpectedDocuments sets the number of source documents not yet found in the destination, used for estimating the
+// remaining time
+func (rmw *reindexerMultiWrite) setExpectedDocuments(index string, intervals []*IntervalProgress) {
+	countSource, countDestination, err := rmw.reindexerClient.GetCountsForInterval(index, intervals[0].Start, intervals[len(intervals)-1].Stop)
+	if err != nil {
+		log.Debug("cannot get the counts for the progress metrics", "index", index, "error", err.Error())
+		return
+	}
+
+	expectedDocuments := uint64(0)
+	if countSource > countDestination {
+		expectedDocuments = countSource - countDestination
+	}
+
+	rmw.metrics.SetExpectedDocuments(index, expectedDocuments)
+}
+
+func (rmw *reindexerMultiWrite) processIntervals(
+	index string,
+	intervals []*IntervalProgress,
+	intervalsIndexes []int,
+	count *uint64,
+	intervalErrors []error,
+) {
+	wg := &sync.WaitGroup{}
+	wg.Add(len(intervalsIndexes))
+
+	for _, idx := range intervalsIndexes {
+		go func(idx int, interv *IntervalProgress, w *sync.WaitGroup) {
+			intervalErrors[idx] = rmw.processInterval(index, idx, interv, count)
+			if intervalErrors[idx] != nil {
+				log.Warn("cannot reindex interval", "interval nr", idx, "index", index, "error", intervalErrors[idx].Error())
 			}
-		}(interv.start, interv.stop, idx, wg)
 
-		time.Sleep(time.Second)
+			w.Done()
+		}(idx, intervals[idx], wg)
+
+		time.Sleep(rmw.delayBetweenIntervals)
 	}
 
 	wg.Wait()
+}
+
+func (rmw *reindexerMultiWrite) processInterval(index string, idx int, interv *IntervalProgress, count *uint64) error {
+	startTime := interv.Start
+	if interv.LastTimestamp > startTime {
+		startTime = interv.LastTimestamp
+		log.Info("resuming interval", "interval nr", idx, "index", index, "from timestamp", startTime)
+	}
+
+	progressHandler := func(lastTimestamp int64, rejectedIDs []string) error {
+		return rmw.checkpoint.UpdateIntervalProgress(index, idx, lastTimestamp, rejectedIDs)
+	}
+
+	err := rmw.reindexerClient.ProcessIndexWithTimestamp(index, startTime, interv.Stop, count, progressHandler)
+	if err != nil {
+		return fmt.Errorf("%w while reindexing interval nr %d of index %s", err, idx, index)
+	}
+
+	// the whole interval is counted, as the documents rejected by the destination are recorded since its start
+	countSource, countDestination, err := rmw.reindexerClient.GetCountsForInterval(index, interv.Start, interv.Stop)
+	if err != nil {
+		return fmt.Errorf("%w while getting the counts for interval nr %d of index %s", err, idx, index)
+	}
+
+	numRejected := rmw.getNumRejected(index, idx)
+	log.Info("done", "interval nr", idx, "index", index, "count source", countSource, "count destination", countDestination,
+		"count rejected", numRejected)
+	if !areCountsMatching(countSource, countDestination, numRejected, rmw.documents.allowsExtraDocuments(index)) {
+		return fmt.Errorf("%w for interval nr %d of index %s, count source %d, count destination %d, count rejected %d",
+			errCountsMismatch, idx, index, countSource, countDestination, numRejected)
+	}
+
+	err = rmw.checkpoint.MarkIntervalCompleted(index, idx)
+	if err != nil {
+		return err
+	}
+
+	rmw.metrics.MarkIntervalCompleted(index)
 
 	return nil
 }
 
+// getNumRejected returns the number of documents rejected by the destination, as recorded in the checkpoint, for the
+// provided interval or, if the interval index is negative, for all the intervals of the index. A document is counted
+// once even if recorded by two adjacent intervals, as the timestamp at their boundary is included in both of them
+func (rmw *reindexerMultiWrite) getNumRejected(index string, intervalIdx int) uint64 {
+	rejectedIDs := make(map[string]struct{})
+	for idx, interv := range rmw.checkpoint.GetIntervals(index) {
+		if intervalIdx >= 0 && idx != intervalIdx {
+			continue
+		}
+
+		for _, id := range interv.RejectedIDs {
+			rejectedIDs[id] = struct{}{}
+		}
+	}
+
+	return uint64(len(rejectedIDs))
+}
+
+// areCountsMatching returns true if the destination holds all the source documents, except the rejected ones. If extra
+// documents are allowed, the destination can also hold documents not found in the source
+func areCountsMatching(countSource uint64, countDestination uint64, numRejected uint64, allowExtraDocuments bool) bool {
+	if allowExtraDocuments {
+		return countDestination+numRejected >= countSource
+	}
+
+	return countDestination+numRejected == countSource
+}
+
 func computeIntervals(startTime, endTime int64, numIntervals int64) ([]*interval, error) {
 	if startTime > endTime {
 		return nil, errors.New("blockchain start time is greater than current timestamp")
@@ -157,3 +414,34 @@ func computeIntervals(startTime, endTime int64, numIntervals int64) ([]*interval
 
 	return intervals, nil
 }
+
+// splitIntervals splits the intervals containing any of the provided boundaries, a boundary being the start of a new
+// interval
+func splitIntervals(intervals []*interval, boundaries []int64) []*interval {
+	if len(boundaries) == 0 {
+		return intervals
+	}
+
+	splitResult := make([]*interval, 0, len(intervals)+len(boundaries))
+	for _, interv := range intervals {
+		start := interv.start
+		for _, boundary := range boundaries {
+			if boundary <= start || boundary > interv.stop {
+				continue
+			}
+
+			splitResult = append(splitResult, &interval{
+				start: start,
+				stop:  boundary - 1,
+			})
+			start = boundary
+		}
+
+		splitResult = append(splitResult, &interval{
+			start: start,
+			stop:  interv.stop,
+		})
+	}
+
+	return splitResult
+}
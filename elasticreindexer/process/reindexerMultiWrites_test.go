@@ -1,8 +1,16 @@
 package process
 
 import (
+	"bytes"
+	"errors"
+	"fmt"
+	"path/filepath"
 	"testing"
+	"time"
 
+	"github.com/multiversx/mx-chain-tools-go/elasticreindexer/config"
+	"github.com/multiversx/mx-chain-tools-go/elasticreindexer/elastic"
+	"github.com/multiversx/mx-chain-tools-go/elasticreindexer/process/mock"
 	"github.com/stretchr/testify/require"
 )
 
@@ -27,3 +35,200 @@ func TestComputeIntervals(t *testing.T) {
 		},
 	}, res)
 }
+
+func TestSplitIntervals(t *testing.T) {
+	intervals := []*interval{
+		{start: 100, stop: 200},
+		{start: 200, stop: 300},
+	}
+
+	require.Equal(t, intervals, splitIntervals(intervals, nil))
+	require.Equal(t, []*interval{
+		{start: 100, stop: 149},
+		{start: 150, stop: 200},
+		{start: 200, stop: 249},
+		{start: 250, stop: 300},
+	}, splitIntervals(intervals, []int64{150, 250}))
+}
+
+func createMultiWriteConfig(numRetries int, indices ...string) config.IndicesConfig {
+	cfg := config.IndicesConfig{}
+	cfg.WithTimestamp.Enabled = true
+	cfg.WithTimestamp.BlockchainStartTime = time.Now().Unix() - 100
+	cfg.WithTimestamp.NumParallelWrites = 1
+	cfg.WithTimestamp.NumRetriesFailedIntervals = numRetries
+	cfg.WithTimestamp.DelayBetweenIntervalsStartInMs = 10
+	cfg.WithTimestamp.IndicesWithTimestamp = indices
+
+	return cfg
+}
+
+func TestNewReindexerMultiWrite_DefaultDelayBetweenIntervals(t *testing.T) {
+	cfg := createMultiWriteConfig(0, testIndex)
+	cfg.WithTimestamp.DelayBetweenIntervalsStartInMs = 0
+	checkpoint, _ := NewFileCheckpoint(filepath.Join(t.TempDir(), "checkpoint.json"), false)
+	rmw, err := NewReindexerMultiWrite(&mock.ReindexerHandlerStub{}, cfg, checkpoint, &mock.MetricsHandlerStub{})
+	require.NoError(t, err)
+	require.Equal(t, defaultDelayBetweenIntervals, rmw.delayBetweenIntervals)
+
+	cfg.WithTimestamp.DelayBetweenIntervalsStartInMs = -1
+	rmw, err = NewReindexerMultiWrite(&mock.ReindexerHandlerStub{}, cfg, checkpoint, &mock.MetricsHandlerStub{})
+	require.Nil(t, rmw)
+	require.Error(t, err)
+}
+
+func TestReindexerMultiWrite_ProcessWithTimestamp(t *testing.T) {
+	t.Run("failed interval should be reported", func(t *testing.T) {
+		expectedErr := errors.New("expected error")
+		reindexerStub := &mock.ReindexerHandlerStub{
+			ProcessIndexWithTimestampCalled: func(_ string, _, _ int64, _ *uint64, _ func(int64, []string) error) error {
+				return expectedErr
+			},
+		}
+		checkpoint, _ := NewFileCheckpoint(filepath.Join(t.TempDir(), "checkpoint.json"), false)
+		rmw, _ := NewReindexerMultiWrite(reindexerStub, createMultiWriteConfig(0, testIndex), checkpoint, &mock.MetricsHandlerStub{})
+
+		results, err := rmw.ProcessWithTimestamp(false, false)
+		require.NoError(t, err)
+		require.Len(t, results, 1)
+		require.False(t, results[0].IsSuccessful())
+		require.Equal(t, 1, results[0].IntervalsFailed)
+		require.True(t, errors.Is(results[0].Errors[0], expectedErr))
+		require.False(t, checkpoint.IsIndexCompleted(testIndex))
+	})
+	t.Run("counts mismatch should be reported", func(t *testing.T) {
+		reindexerStub := &mock.ReindexerHandlerStub{
+			GetCountsForIntervalCalled: func(_ string, _, _ int64) (uint64, uint64, error) {
+				return 10, 9, nil
+			},
+		}
+		checkpoint, _ := NewFileCheckpoint(filepath.Join(t.TempDir(), "checkpoint.json"), false)
+		rmw, _ := NewReindexerMultiWrite(reindexerStub, createMultiWriteConfig(0, testIndex), checkpoint, &mock.MetricsHandlerStub{})
+
+		results, err := rmw.ProcessWithTimestamp(false, false)
+		require.NoError(t, err)
+		require.False(t, results[0].IsSuccessful())
+		require.True(t, errors.Is(results[0].Errors[0], errCountsMismatch))
+		require.Equal(t, uint64(10), results[0].SourceCount)
+		require.Equal(t, uint64(9), results[0].DestinationCount)
+	})
+	t.Run("extra destination documents should be allowed when creating documents", func(t *testing.T) {
+		reindexerStub := &mock.ReindexerHandlerStub{
+			GetCountsForIntervalCalled: func(_ string, _, _ int64) (uint64, uint64, error) {
+				return 10, 12, nil
+			},
+		}
+		cfg := createMultiWriteConfig(0, testIndex)
+		checkpoint, _ := NewFileCheckpoint(filepath.Join(t.TempDir(), "checkpoint.json"), false)
+		rmw, _ := NewReindexerMultiWrite(reindexerStub, cfg, checkpoint, &mock.MetricsHandlerStub{})
+
+		results, err := rmw.ProcessWithTimestamp(false, false)
+		require.NoError(t, err)
+		require.False(t, results[0].IsSuccessful())
+
+		cfg.Documents.BulkAction = bulkActionCreate
+		checkpoint, _ = NewFileCheckpoint(filepath.Join(t.TempDir(), "checkpoint.json"), false)
+		rmw, _ = NewReindexerMultiWrite(reindexerStub, cfg, checkpoint, &mock.MetricsHandlerStub{})
+
+		results, err = rmw.ProcessWithTimestamp(false, false)
+		require.NoError(t, err)
+		require.True(t, results[0].IsSuccessful())
+		require.True(t, checkpoint.IsIndexCompleted(testIndex))
+	})
+	t.Run("failed interval should be retried from the last checkpoint", func(t *testing.T) {
+		numCalls := 0
+		resumedFrom := int64(0)
+		reindexerStub := &mock.ReindexerHandlerStub{
+			ProcessIndexWithTimestampCalled: func(_ string, start, _ int64, _ *uint64, progressHandler func(int64, []string) error) error {
+				numCalls++
+				if numCalls == 1 {
+					_ = progressHandler(start+10, nil)
+					return errors.New("first attempt fails")
+				}
+
+				resumedFrom = start
+				return nil
+			},
+		}
+		numRetries, numCompleted := 0, 0
+		metricsStub := &mock.MetricsHandlerStub{
+			AddIntervalRetryCalled: func(_ string) {
+				numRetries++
+			},
+			MarkIntervalCompletedCalled: func(_ string) {
+				numCompleted++
+			},
+		}
+		cfg := createMultiWriteConfig(1, testIndex)
+		checkpoint, _ := NewFileCheckpoint(filepath.Join(t.TempDir(), "checkpoint.json"), false)
+		rmw, _ := NewReindexerMultiWrite(reindexerStub, cfg, checkpoint, metricsStub)
+
+		results, err := rmw.ProcessWithTimestamp(false, false)
+		require.NoError(t, err)
+		require.True(t, results[0].IsSuccessful())
+		require.Equal(t, 2, numCalls)
+		require.Equal(t, cfg.WithTimestamp.BlockchainStartTime+10, resumedFrom)
+		require.True(t, checkpoint.IsIndexCompleted(testIndex))
+		require.Equal(t, 1, numRetries)
+		require.Equal(t, 1, numCompleted)
+	})
+	t.Run("permanently rejected document should not fail the interval", func(t *testing.T) {
+		cfg := createMultiWriteConfig(1, testIndex)
+		timestamp := cfg.WithTimestamp.BlockchainStartTime + 10
+		source := &mock.ElasticClientStub{
+			DoScrollRequestAllDocumentsCalled: func(_ string, _ []byte, handlerFunc func(responseBytes []byte) error) error {
+				return handlerFunc([]byte(fmt.Sprintf(`{"hits":{"hits":[
+					{"_id":"a","_source":{"timestamp":%d}},
+					{"_id":"b","_source":{"timestamp":%d}}
+				]}}`, timestamp, timestamp)))
+			},
+			GetCountWithBodyCalled: func(_ string, _ []byte) (uint64, error) {
+				return 2, nil
+			},
+		}
+		destination := &mock.ElasticClientStub{
+			DoBulkRequestCalled: func(_ *bytes.Buffer, _ string) error {
+				return &elastic.BulkError{
+					NumItems:    2,
+					FailedItems: []*elastic.BulkItemError{{Position: 1, ID: "b", Status: 400}},
+				}
+			},
+			GetCountWithBodyCalled: func(_ string, _ []byte) (uint64, error) {
+				return 1, nil
+			},
+		}
+		args := createMockArgsReindexer(source, destination, nil)
+		deadLetter := &deadLetterStub{}
+		args.DeadLetter = deadLetter
+		r, _ := newReindexer(args)
+		numRetries := 0
+		metricsStub := &mock.MetricsHandlerStub{
+			AddIntervalRetryCalled: func(_ string) {
+				numRetries++
+			},
+		}
+		checkpoint, _ := NewFileCheckpoint(filepath.Join(t.TempDir(), "checkpoint.json"), false)
+		rmw, _ := NewReindexerMultiWrite(r, cfg, checkpoint, metricsStub)
+
+		results, err := rmw.ProcessWithTimestamp(false, true)
+		require.NoError(t, err)
+		require.True(t, results[0].IsSuccessful())
+		require.Equal(t, uint64(1), results[0].RejectedCount)
+		require.Equal(t, 0, numRetries)
+		require.Len(t, deadLetter.documents, 1)
+		require.True(t, checkpoint.IsIndexCompleted(testIndex))
+	})
+}
+
+func TestReindexerMultiWrite_GetNumRejectedShouldCountTheBoundaryDocumentsOnce(t *testing.T) {
+	checkpoint, _ := NewFileCheckpoint(filepath.Join(t.TempDir(), "checkpoint.json"), false)
+	require.NoError(t, checkpoint.SetIntervals(testIndex, []*IntervalProgress{
+		{Start: 100, Stop: 200, RejectedIDs: []string{"a", "b"}},
+		{Start: 200, Stop: 300, RejectedIDs: []string{"b", "c"}},
+	}))
+	rmw, _ := NewReindexerMultiWrite(&mock.ReindexerHandlerStub{}, createMultiWriteConfig(0, testIndex), checkpoint, &mock.MetricsHandlerStub{})
+
+	require.Equal(t, uint64(2), rmw.getNumRejected(testIndex, 0))
+	require.Equal(t, uint64(2), rmw.getNumRejected(testIndex, 1))
+	require.Equal(t, uint64(3), rmw.getNumRejected(testIndex, -1))
+}
@@ -2,14 +2,29 @@ package process
 
 import (
 	"bytes"
+	"io/ioutil"
+	"path/filepath"
 	"testing"
 
+	"github.com/multiversx/mx-chain-tools-go/elasticreindexer/config"
+	"github.com/multiversx/mx-chain-tools-go/elasticreindexer/elastic"
 	"github.com/multiversx/mx-chain-tools-go/elasticreindexer/process/mock"
 	"github.com/stretchr/testify/require"
 )
 
 const testIndex = "index"
 
+func createMockArgsReindexer(sourceClient ElasticClientHandler, destinationClient ElasticClientHandler, indices []string) ArgsReindexer {
+	return ArgsReindexer{
+		SourceElastic:      sourceClient,
+		DestinationElastic: destinationClient,
+		Indices:            indices,
+		RateLimiter:        NewRateLimiter(0, 0),
+		Metrics:            &mock.MetricsHandlerStub{},
+		DeadLetter:         &deadLetterStub{},
+	}
+}
+
 func TestCopyMapping(t *testing.T) {
 	t.Run("no overwrite - no alias - no index => should create",
 		testCopyMappingNoOverwriteShouldCreate)
@@ -60,7 +75,7 @@ func testCopyMappingNoOverwriteShouldCreate(t *testing.T) {
 		},
 	}
 
-	r, _ := newReindexer(sourceClient, destinationClient, []string{"index"})
+	r, _ := newReindexer(createMockArgsReindexer(sourceClient, destinationClient, []string{"index"}))
 
 	err := r.copyMappingIfNecessary(testIndex, false, false)
 	require.NoError(t, err)
@@ -79,7 +94,7 @@ func testCopyMappingNoOverwriteAliasExistsIndexDoesNotExistShouldErr(t *testing.
 		},
 	}
 
-	r, _ := newReindexer(&mock.ElasticClientStub{}, destinationClient, []string{"index"})
+	r, _ := newReindexer(createMockArgsReindexer(&mock.ElasticClientStub{}, destinationClient, []string{"index"}))
 
 	err := r.copyMappingIfNecessary(testIndex, false, false)
 	require.Error(t, err)
@@ -96,7 +111,7 @@ func testCopyMappingNoOverwriteAliasAndIndexExistShouldErr(t *testing.T) {
 		},
 	}
 
-	r, _ := newReindexer(&mock.ElasticClientStub{}, destinationClient, []string{"index"})
+	r, _ := newReindexer(createMockArgsReindexer(&mock.ElasticClientStub{}, destinationClient, []string{"index"}))
 
 	err := r.copyMappingIfNecessary(testIndex, false, false)
 	require.Error(t, err)
@@ -110,7 +125,7 @@ func testCopyMappingNoOverwriteAliasDoesNotExistsIndexExistsShouldErr(t *testing
 		},
 	}
 
-	r, _ := newReindexer(&mock.ElasticClientStub{}, destinationClient, []string{"test-index"})
+	r, _ := newReindexer(createMockArgsReindexer(&mock.ElasticClientStub{}, destinationClient, []string{"test-index"}))
 
 	err := r.copyMappingIfNecessary("test-index", false, false)
 	require.Error(t, err)
@@ -145,7 +160,7 @@ func testCopyMappingOverwriteAliasAndIndexExistShouldNotCreate(t *testing.T) {
 		},
 	}
 
-	r, _ := newReindexer(sourceClient, destinationClient, []string{"index"})
+	r, _ := newReindexer(createMockArgsReindexer(sourceClient, destinationClient, []string{"index"}))
 
 	err := r.copyMappingIfNecessary(testIndex, true, false)
 	require.NoError(t, err)
@@ -174,7 +189,7 @@ func testCopyMappingOverwriteAliasDoesNotExistShouldCreateAlias(t *testing.T) {
 		},
 	}
 
-	r, _ := newReindexer(&mock.ElasticClientStub{}, destinationClient, []string{"index"})
+	r, _ := newReindexer(createMockArgsReindexer(&mock.ElasticClientStub{}, destinationClient, []string{"index"}))
 
 	err := r.copyMappingIfNecessary(testIndex, true, false)
 	require.NoError(t, err)
@@ -202,7 +217,7 @@ func testCopyMappingOverwriteAliasExistsIndexDoesNotExistShouldCreateIndex(t *te
 		},
 	}
 
-	r, _ := newReindexer(&mock.ElasticClientStub{}, destinationClient, []string{"index"})
+	r, _ := newReindexer(createMockArgsReindexer(&mock.ElasticClientStub{}, destinationClient, []string{"index"}))
 
 	err := r.copyMappingIfNecessary(testIndex, true, false)
 	require.NoError(t, err)
@@ -230,7 +245,7 @@ func testCopyMappingOverwriteAliasAndIndexExistShouldCreate(t *testing.T) {
 		},
 	}
 
-	r, _ := newReindexer(&mock.ElasticClientStub{}, destinationClient, []string{"index"})
+	r, _ := newReindexer(createMockArgsReindexer(&mock.ElasticClientStub{}, destinationClient, []string{"index"}))
 
 	err := r.copyMappingIfNecessary(testIndex, true, false)
 	require.NoError(t, err)
@@ -259,9 +274,228 @@ func testSkipMappings(t *testing.T) {
 		},
 	}
 
-	r, _ := newReindexer(&mock.ElasticClientStub{}, destinationClient, []string{"index"})
+	r, _ := newReindexer(createMockArgsReindexer(&mock.ElasticClientStub{}, destinationClient, []string{"index"}))
 
 	err := r.copyMappingIfNecessary(testIndex, false, true)
 	require.NoError(t, err)
 	require.False(t, called)
 }
+
+func TestCopyMapping_CompareMappings(t *testing.T) {
+	sourceMapping := `{"mappings":{"properties":{"nonce":{"type":"long"},"sender":{"type":"keyword"}}}}`
+	createStubs := func(destinationMapping string) (*mock.ElasticClientStub, *mock.ElasticClientStub) {
+		sourceClient := &mock.ElasticClientStub{
+			GetMappingCalled: func(_ string) (*bytes.Buffer, error) {
+				return bytes.NewBufferString(sourceMapping), nil
+			},
+		}
+		destinationClient := &mock.ElasticClientStub{
+			DoesIndexExistCalled: func(_ string) bool {
+				return true
+			},
+			DoesAliasExistCalled: func(_ string) bool {
+				return true
+			},
+			GetMappingCalled: func(_ string) (*bytes.Buffer, error) {
+				return bytes.NewBufferString(destinationMapping), nil
+			},
+		}
+
+		return sourceClient, destinationClient
+	}
+
+	t.Run("incompatible existing destination mapping should err", func(t *testing.T) {
+		sourceClient, destinationClient := createStubs(`{"mappings":{"properties":{"nonce":{"type":"keyword"}}}}`)
+		args := createMockArgsReindexer(sourceClient, destinationClient, nil)
+		args.Mappings.Compare = true
+		r, _ := newReindexer(args)
+
+		err := r.copyMappingIfNecessary(testIndex, true, false)
+		require.ErrorIs(t, err, errIncompatibleMappings)
+		require.Contains(t, err.Error(), "nonce: long -> keyword")
+	})
+	t.Run("incompatible mappings should not err when forced", func(t *testing.T) {
+		sourceClient, destinationClient := createStubs(`{"mappings":{"properties":{"nonce":{"type":"keyword"}}}}`)
+		args := createMockArgsReindexer(sourceClient, destinationClient, nil)
+		args.Mappings.Compare = true
+		args.Mappings.ForceIncompatible = true
+		r, _ := newReindexer(args)
+
+		require.NoError(t, r.copyMappingIfNecessary(testIndex, true, false))
+	})
+	t.Run("destination with additional fields should not err", func(t *testing.T) {
+		sourceClient, destinationClient := createStubs(
+			`{"mappings":{"properties":{"nonce":{"type":"long"},"sender":{"type":"keyword"},"extra":{"type":"long"}}}}`)
+		args := createMockArgsReindexer(sourceClient, destinationClient, nil)
+		args.Mappings.Compare = true
+		r, _ := newReindexer(args)
+
+		require.NoError(t, r.copyMappingIfNecessary(testIndex, true, false))
+	})
+	t.Run("index with transformed fields should not be compared", func(t *testing.T) {
+		sourceClient, destinationClient := createStubs(`{"mappings":{"properties":{"nonce":{"type":"long"},"from":{"type":"keyword"}}}}`)
+		args := createMockArgsReindexer(sourceClient, destinationClient, nil)
+		args.Mappings.Compare = true
+		args.IndicesSettings = map[string]config.PerIndexConfig{
+			testIndex: {RenameFields: []config.RenameFieldConfig{{From: "sender", To: "from"}}},
+		}
+		r, _ := newReindexer(args)
+
+		require.NoError(t, r.copyMappingIfNecessary(testIndex, true, false))
+	})
+	t.Run("index should be created from the template and compared with it", func(t *testing.T) {
+		templatesDirectory := t.TempDir()
+		template := `{"index_patterns":["index-*"],"settings":{"number_of_shards":1},"mappings":{"properties":{"nonce":{"type":"long"}}}}`
+		require.NoError(t, ioutil.WriteFile(filepath.Join(templatesDirectory, testIndex+".json"), []byte(template), 0644))
+
+		createdBody := ""
+		sourceClient, destinationClient := createStubs("")
+		destinationClient.DoesIndexExistCalled = func(_ string) bool {
+			return false
+		}
+		destinationClient.CreateIndexWithMappingCalled = func(_ string, body *bytes.Buffer) error {
+			createdBody = body.String()
+			return nil
+		}
+		args := createMockArgsReindexer(sourceClient, destinationClient, nil)
+		args.Mappings.TemplatesDirectory = templatesDirectory
+		r, _ := newReindexer(args)
+
+		require.NoError(t, r.copyMappingIfNecessary(testIndex, true, false))
+		require.JSONEq(t, `{"settings":{"number_of_shards":1},"mappings":{"properties":{"nonce":{"type":"long"}}}}`, createdBody)
+
+		args.Mappings.Compare = true
+		r, _ = newReindexer(args)
+		err := r.copyMappingIfNecessary(testIndex, true, false)
+		require.ErrorIs(t, err, errIncompatibleMappings)
+		require.Contains(t, err.Error(), "removed [sender]")
+	})
+}
+
+func TestCopyMapping_WithIndexPrefixes(t *testing.T) {
+	sourceIndices := make([]string, 0)
+	destinationIndices := make([]string, 0)
+	sourceClient := &mock.ElasticClientStub{
+		GetMappingCalled: func(index string) (*bytes.Buffer, error) {
+			sourceIndices = append(sourceIndices, index)
+			return bytes.NewBufferString(`{}`), nil
+		},
+	}
+	destinationClient := &mock.ElasticClientStub{
+		DoesIndexExistCalled: func(index string) bool {
+			destinationIndices = append(destinationIndices, index)
+			return false
+		},
+		DoesAliasExistCalled: func(alias string) bool {
+			destinationIndices = append(destinationIndices, alias)
+			return false
+		},
+		CreateIndexWithMappingCalled: func(index string, _ *bytes.Buffer) error {
+			destinationIndices = append(destinationIndices, index)
+			return nil
+		},
+		PutAliasCalled: func(index string, alias string) error {
+			destinationIndices = append(destinationIndices, index, alias)
+			return nil
+		},
+	}
+
+	args := createMockArgsReindexer(sourceClient, destinationClient, nil)
+	args.SourcePrefix = "mainnet-"
+	args.DestinationPrefix = "devnet-"
+	r, _ := newReindexer(args)
+
+	require.NoError(t, r.copyMappingIfNecessary(testIndex, false, false))
+	require.Equal(t, []string{"mainnet-index"}, sourceIndices)
+	for _, index := range destinationIndices {
+		require.Contains(t, index, "devnet-index")
+	}
+	require.Contains(t, destinationIndices, "devnet-index-000001")
+}
+
+func TestReindexer_GetCountsForIntervalShouldRefreshTheDestinationFirst(t *testing.T) {
+	operations := make([]string, 0)
+	source := &mock.ElasticClientStub{
+		GetCountWithBodyCalled: func(_ string, _ []byte) (uint64, error) {
+			operations = append(operations, "count source")
+			return 10, nil
+		},
+	}
+	destination := &mock.ElasticClientStub{
+		RefreshIndexCalled: func(index string) error {
+			operations = append(operations, "refresh "+index)
+			return nil
+		},
+		GetCountWithBodyCalled: func(_ string, _ []byte) (uint64, error) {
+			operations = append(operations, "count destination")
+			return 9, nil
+		},
+	}
+	r, _ := newReindexer(createMockArgsReindexer(source, destination, nil))
+
+	countSource, countDestination, err := r.GetCountsForInterval(testIndex, 100, 200)
+	require.NoError(t, err)
+	require.Equal(t, uint64(10), countSource)
+	require.Equal(t, uint64(9), countDestination)
+	require.Equal(t, []string{"refresh " + testIndex, "count source", "count destination"}, operations)
+}
+
+func TestReindexer_ProcessShouldCompareTheCountsOfTheIndicesWithoutTimestamp(t *testing.T) {
+	createStubs := func(destinationCount uint64, operations *[]string) (*mock.ElasticClientStub, *mock.ElasticClientStub) {
+		source := &mock.ElasticClientStub{
+			GetCountWithBodyCalled: func(_ string, _ []byte) (uint64, error) {
+				return 3, nil
+			},
+			DoScrollRequestAllDocumentsCalled: func(_ string, _ []byte, handler func(responseBytes []byte) error) error {
+				return handler([]byte(`{"hits":{"hits":[{"_id":"a","_source":{"nonce":1}},{"_id":"b","_source":{"nonce":2}},{"_id":"c","_source":{"nonce":3}}]}}`))
+			},
+		}
+		destination := &mock.ElasticClientStub{
+			DoBulkRequestCalled: func(_ *bytes.Buffer, _ string) error {
+				return &elastic.BulkError{
+					NumItems:    3,
+					FailedItems: []*elastic.BulkItemError{{Position: 2, ID: "c", Index: "index-000001", Status: 400}},
+				}
+			},
+			RefreshIndexCalled: func(index string) error {
+				*operations = append(*operations, "refresh "+index)
+				return nil
+			},
+			GetCountWithBodyCalled: func(_ string, _ []byte) (uint64, error) {
+				*operations = append(*operations, "count destination")
+				return destinationCount, nil
+			},
+		}
+
+		return source, destination
+	}
+
+	t.Run("rejected documents should be subtracted from the source count", func(t *testing.T) {
+		operations := make([]string, 0)
+		source, destination := createStubs(2, &operations)
+		r, _ := newReindexer(createMockArgsReindexer(source, destination, nil))
+
+		require.NoError(t, r.Process(true, true, testIndex))
+		require.Equal(t, []string{"refresh " + testIndex, "count destination"}, operations)
+	})
+	t.Run("missing documents should err", func(t *testing.T) {
+		operations := make([]string, 0)
+		source, destination := createStubs(1, &operations)
+		r, _ := newReindexer(createMockArgsReindexer(source, destination, nil))
+
+		err := r.Process(true, true, testIndex)
+		require.ErrorIs(t, err, errCountsMismatch)
+		require.Contains(t, err.Error(), "count source 3, count destination 1, count rejected 1")
+	})
+	t.Run("shared destination should allow extra documents", func(t *testing.T) {
+		operations := make([]string, 0)
+		source, destination := createStubs(10, &operations)
+		args := createMockArgsReindexer(source, destination, nil)
+		r, _ := newReindexer(args)
+		require.ErrorIs(t, r.Process(true, true, testIndex), errCountsMismatch)
+
+		args.SharedDestination = true
+		r, _ = newReindexer(args)
+		require.NoError(t, r.Process(true, true, testIndex))
+	})
+}
@@ -0,0 +1,414 @@
+package process
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+
+	"github.com/multiversx/mx-chain-core-go/core/check"
+	"github.com/multiversx/mx-chain-tools-go/elasticreindexer/config"
+)
+
+var errNilReportWriter = errors.New("nil report writer")
+
+type documentHash = [sha256.Size]byte
+
+// ArgsVerifier is the DTO used in the NewVerifier constructor function
+type ArgsVerifier struct {
+	SourceElastic      ElasticClientHandler
+	DestinationElastic ElasticClientHandler
+	IndicesConfig      config.IndicesConfig
+	ReportWriter       io.Writer
+	SourceTag          string
+}
+
+// VerificationResult holds the outcome of the verification of an index
+type VerificationResult struct {
+	Index        string
+	NumChecked   uint64
+	NumMissing   uint64
+	NumExtra     uint64
+	NumDiffering uint64
+	Errors       []error
+}
+
+// IsSuccessful returns true if the destination holds exactly the same documents as the source
+func (vr *VerificationResult) IsSuccessful() bool {
+	return len(vr.Errors) == 0 && vr.NumMissing == 0 && vr.NumExtra == 0 && vr.NumDiffering == 0
+}
+
+type intervalReport struct {
+	Index     string   `json:"index"`
+	Start     int64    `json:"start,omitempty"`
+	Stop      int64    `json:"stop,omitempty"`
+	Checked   uint64   `json:"checked"`
+	Missing   []string `json:"missing"`
+	Extra     []string `json:"extra"`
+	Differing []string `json:"differing"`
+	Error     string   `json:"error,omitempty"`
+}
+
+// verifier compares the documents from the source and the destination by ID and by a hash of their source, one page
+// of documents at a time
+type verifier struct {
+	sourceElastic        ElasticClientHandler
+	destinationElastic   ElasticClientHandler
+	indicesNoTimestamp   []string
+	indicesWithTimestamp []string
+	numParallelVerify    int
+	blockChainStartTime  int64
+	pageSize             int
+	indicesSettings      map[string]*indexSettings
+	names                indexNames
+	documents            *documentsWriter
+
+	mutReport    sync.Mutex
+	reportWriter io.Writer
+}
+
+// NewVerifier creates a new verifier instance
+func NewVerifier(args ArgsVerifier) (*verifier, error) {
+	if check.IfNil(args.SourceElastic) {
+		return nil, fmt.Errorf("%w for source", errNilElasticHandler)
+	}
+	if check.IfNil(args.DestinationElastic) {
+		return nil, fmt.Errorf("%w for destination", errNilElasticHandler)
+	}
+	if args.ReportWriter == nil {
+		return nil, errNilReportWriter
+	}
+
+	indicesSettings, err := createIndicesSettings(args.IndicesConfig.PerIndex)
+	if err != nil {
+		return nil, err
+	}
+	err = checkTimestampFieldKept(args.IndicesConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	documents, err := newDocumentsWriter(args.IndicesConfig.Documents, args.IndicesConfig.PerIndex, args.SourceTag)
+	if err != nil {
+		return nil, err
+	}
+
+	v := &verifier{
+		sourceElastic:       args.SourceElastic,
+		destinationElastic:  args.DestinationElastic,
+		indicesNoTimestamp:  args.IndicesConfig.Indices,
+		numParallelVerify:   args.IndicesConfig.WithTimestamp.NumParallelWrites,
+		blockChainStartTime: args.IndicesConfig.WithTimestamp.BlockchainStartTime,
+		pageSize:            args.IndicesConfig.PageSize,
+		indicesSettings:     indicesSettings,
+		reportWriter:        args.ReportWriter,
+		names:               newIndexNames(args.IndicesConfig),
+		documents:           documents,
+	}
+	if args.IndicesConfig.WithTimestamp.Enabled {
+		v.indicesWithTimestamp = args.IndicesConfig.WithTimestamp.IndicesWithTimestamp
+	}
+
+	return v, nil
+}
+
+// Verify will compare all the configured indices, the ones with timestamp being verified in parallel, on intervals.
+// The differences are written in the report
+func (v *verifier) Verify(stopTimestamp int64) ([]*VerificationResult, error) {
+	results := make([]*VerificationResult, 0, len(v.indicesNoTimestamp)+len(v.indicesWithTimestamp))
+	for _, index := range v.indicesNoTimestamp {
+		if index == "" {
+			continue
+		}
+
+		log.Info("verifying index", "index", index)
+		query := getAll(v.pageSize, v.getIndexSettings(index).query).Bytes()
+		report := v.verifyQuery(index, query)
+		results = append(results, v.aggregateReports(index, []*intervalReport{report}))
+	}
+
+	if len(v.indicesWithTimestamp) == 0 {
+		return results, nil
+	}
+
+	intervals, err := computeIntervals(v.blockChainStartTime, stopTimestamp, int64(v.numParallelVerify))
+	if err != nil {
+		return nil, err
+	}
+
+	for _, index := range v.indicesWithTimestamp {
+		if index == "" {
+			continue
+		}
+
+		log.Info("verifying index", "index", index, "num intervals", len(intervals))
+		reports := v.verifyIntervals(index, intervals)
+		results = append(results, v.aggregateReports(index, reports))
+	}
+
+	return results, nil
+}
+
+func (v *verifier) getIndexSettings(index string) *indexSettings {
+	return getSettingsForIndex(v.indicesSettings, index)
+}
+
+func (v *verifier) verifyIntervals(index string, intervals []*interval) []*intervalReport {
+	reports := make([]*intervalReport, len(intervals))
+	filterQuery := v.getIndexSettings(index).query
+
+	wg := &sync.WaitGroup{}
+	wg.Add(len(intervals))
+	for idx, interv := range intervals {
+		go func(idx int, interv *interval) {
+			query := getWithTimestamp(interv.start, interv.stop, true, false, v.pageSize, filterQuery).Bytes()
+			reports[idx] = v.verifyQuery(index, query)
+			reports[idx].Start = interv.start
+			reports[idx].Stop = interv.stop
+
+			log.Info("verified interval", "interval nr", idx, "index", index, "checked", reports[idx].Checked,
+				"missing", len(reports[idx].Missing), "extra", len(reports[idx].Extra), "differing", len(reports[idx].Differing))
+			wg.Done()
+		}(idx, interv)
+	}
+
+	wg.Wait()
+
+	return reports
+}
+
+// verifyQuery compares the documents matched by the query page by page, so only the documents of a page are kept in
+// memory: the destination documents of every source page are fetched by their ids and, if the destination should not
+// hold extra documents, the source documents of every destination page are looked up the same way
+func (v *verifier) verifyQuery(index string, query []byte) *intervalReport {
+	report := &intervalReport{
+		Index:     index,
+		Missing:   make([]string, 0),
+		Extra:     make([]string, 0),
+		Differing: make([]string, 0),
+	}
+
+	err := v.sourceElastic.DoScrollRequestAllDocuments(v.names.source(index), query, func(responseBytes []byte) error {
+		return v.verifySourcePage(index, responseBytes, report)
+	})
+	if err != nil {
+		report.Error = fmt.Sprintf("%s while verifying the source documents", err.Error())
+		return report
+	}
+
+	if v.documents.allowsExtraDocuments(index) {
+		return report
+	}
+
+	err = v.destinationElastic.DoScrollRequestAllDocuments(v.names.destination(index), query, func(responseBytes []byte) error {
+		return v.findExtraDocuments(index, responseBytes, report)
+	})
+	if err != nil {
+		report.Error = fmt.Sprintf("%s while verifying the destination documents", err.Error())
+	}
+
+	return report
+}
+
+// verifySourcePage compares the documents of a source page with the destination documents having the same ids
+func (v *verifier) verifySourcePage(index string, responseBytes []byte, report *intervalReport) error {
+	documentIDHandler := func(id string) string {
+		return v.documents.documentID(index, id)
+	}
+	sourceHashes := make(map[string]documentHash)
+	err := collectHashes(responseBytes, v.getIndexSettings(index), documentIDHandler, sourceHashes)
+	if err != nil {
+		return err
+	}
+	if len(sourceHashes) == 0 {
+		return nil
+	}
+
+	report.Checked += uint64(len(sourceHashes))
+	destinationHits, err := getDocumentsByIDs(v.destinationElastic, v.names.destination(index), sortedIDs(sourceHashes), true, nil)
+	if err != nil {
+		return fmt.Errorf("%w while reading the destination documents", err)
+	}
+
+	// the updated documents are merged into the existing ones, so their content can differ from the source
+	compareContent := v.documents.getBulkAction(index) != bulkActionUpdate
+	for _, hit := range destinationHits {
+		sourceHash, found := sourceHashes[hit.ID]
+		if !found {
+			continue
+		}
+
+		delete(sourceHashes, hit.ID)
+		if !compareContent {
+			continue
+		}
+
+		destinationHash, errH := computeDocumentHash(hit.Source)
+		if errH != nil {
+			return fmt.Errorf("%w for destination document with id %s", errH, hit.ID)
+		}
+		if sourceHash != destinationHash {
+			report.Differing = append(report.Differing, hit.ID)
+		}
+	}
+
+	report.Missing = append(report.Missing, sortedIDs(sourceHashes)...)
+
+	return nil
+}
+
+// findExtraDocuments reports the documents of a destination page not found in the source. The destination documents
+// have the ids of the source ones, as no id template is used when extra documents are not allowed
+func (v *verifier) findExtraDocuments(index string, responseBytes []byte, report *intervalReport) error {
+	esResponse, err := unmarshalEsResponse(responseBytes)
+	if err != nil {
+		return err
+	}
+	if len(esResponse.Hits.Hits) == 0 {
+		return nil
+	}
+
+	ids := make([]string, 0, len(esResponse.Hits.Hits))
+	for _, hit := range esResponse.Hits.Hits {
+		ids = append(ids, hit.ID)
+	}
+
+	sourceHits, err := getDocumentsByIDs(v.sourceElastic, v.names.source(index), ids, false, v.getIndexSettings(index).query)
+	if err != nil {
+		return fmt.Errorf("%w while reading the source documents", err)
+	}
+
+	foundIDs := make(map[string]struct{}, len(sourceHits))
+	for _, hit := range sourceHits {
+		foundIDs[hit.ID] = struct{}{}
+	}
+	for _, id := range ids {
+		_, found := foundIDs[id]
+		if !found {
+			report.Extra = append(report.Extra, id)
+		}
+	}
+
+	return nil
+}
+
+func getDocumentsByIDs(
+	client ElasticClientHandler,
+	index string,
+	ids []string,
+	withSource bool,
+	filterQuery json.RawMessage,
+) ([]elasticHit, error) {
+	hits := make([]elasticHit, 0, len(ids))
+	query := getByIDs(ids, withSource, filterQuery).Bytes()
+	err := client.DoScrollRequestAllDocuments(index, query, func(responseBytes []byte) error {
+		esResponse, err := unmarshalEsResponse(responseBytes)
+		if err != nil {
+			return err
+		}
+
+		hits = append(hits, esResponse.Hits.Hits...)
+		return nil
+	})
+
+	return hits, err
+}
+
+// sortedIDs returns the sorted ids of the provided hashes
+func sortedIDs(hashes map[string]documentHash) []string {
+	keys := make([]string, 0, len(hashes))
+	for key := range hashes {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
+
+// collectHashes hashes every document of the page, after applying the index transformations, so the result can be
+// compared with what was written in the destination. The hashes are keyed by the ids of the documents in the destination
+func collectHashes(
+	responseBytes []byte,
+	settings *indexSettings,
+	documentIDHandler func(id string) string,
+	hashes map[string]documentHash,
+) error {
+	esResponse, err := unmarshalEsResponse(responseBytes)
+	if err != nil {
+		return err
+	}
+
+	for _, hit := range esResponse.Hits.Hits {
+		source, errT := settings.transformSource(hit.Source)
+		if errT != nil {
+			return fmt.Errorf("%w for source document with id %s", errT, hit.ID)
+		}
+
+		hashes[documentIDHandler(hit.ID)], err = computeDocumentHash(source)
+		if err != nil {
+			return fmt.Errorf("%w for source document with id %s", err, hit.ID)
+		}
+	}
+
+	return nil
+}
+
+// computeDocumentHash hashes the canonical form of the document, so the fields order and the formatting do not matter
+func computeDocumentHash(source json.RawMessage) (documentHash, error) {
+	decoder := json.NewDecoder(bytes.NewReader(source))
+	decoder.UseNumber()
+
+	var document interface{}
+	err := decoder.Decode(&document)
+	if err != nil {
+		return documentHash{}, err
+	}
+
+	canonical, err := json.Marshal(document)
+	if err != nil {
+		return documentHash{}, err
+	}
+
+	return sha256.Sum256(canonical), nil
+}
+
+func (v *verifier) aggregateReports(index string, reports []*intervalReport) *VerificationResult {
+	result := &VerificationResult{
+		Index: index,
+	}
+
+	for _, report := range reports {
+		result.NumChecked += report.Checked
+		result.NumMissing += uint64(len(report.Missing))
+		result.NumExtra += uint64(len(report.Extra))
+		result.NumDiffering += uint64(len(report.Differing))
+		if report.Error != "" {
+			result.Errors = append(result.Errors, errors.New(report.Error))
+		}
+
+		err := v.writeReport(report)
+		if err != nil {
+			result.Errors = append(result.Errors, fmt.Errorf("%w while writing the report", err))
+		}
+	}
+
+	return result
+}
+
+func (v *verifier) writeReport(report *intervalReport) error {
+	reportBytes, err := json.Marshal(report)
+	if err != nil {
+		return err
+	}
+
+	v.mutReport.Lock()
+	defer v.mutReport.Unlock()
+
+	_, err = v.reportWriter.Write(append(reportBytes, '\n'))
+
+	return err
+}
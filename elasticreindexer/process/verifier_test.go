@@ -0,0 +1,245 @@
+package process
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/multiversx/mx-chain-tools-go/elasticreindexer/config"
+	"github.com/multiversx/mx-chain-tools-go/elasticreindexer/process/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func createScrollStub(response string) *mock.ElasticClientStub {
+	return &mock.ElasticClientStub{
+		DoScrollRequestAllDocumentsCalled: func(_ string, _ []byte, handlerFunc func(responseBytes []byte) error) error {
+			return handlerFunc([]byte(response))
+		},
+	}
+}
+
+func TestNewVerifier(t *testing.T) {
+	v, err := NewVerifier(ArgsVerifier{DestinationElastic: &mock.ElasticClientStub{}, ReportWriter: &bytes.Buffer{}})
+	require.Nil(t, v)
+	require.ErrorIs(t, err, errNilElasticHandler)
+
+	v, err = NewVerifier(ArgsVerifier{SourceElastic: &mock.ElasticClientStub{}, ReportWriter: &bytes.Buffer{}})
+	require.Nil(t, v)
+	require.ErrorIs(t, err, errNilElasticHandler)
+
+	v, err = NewVerifier(ArgsVerifier{SourceElastic: &mock.ElasticClientStub{}, DestinationElastic: &mock.ElasticClientStub{}})
+	require.Nil(t, v)
+	require.Equal(t, errNilReportWriter, err)
+
+	v, err = NewVerifier(ArgsVerifier{
+		SourceElastic:      &mock.ElasticClientStub{},
+		DestinationElastic: &mock.ElasticClientStub{},
+		ReportWriter:       &bytes.Buffer{},
+	})
+	require.NotNil(t, v)
+	require.NoError(t, err)
+}
+
+func TestVerifier_VerifyShouldReportMissingExtraAndDifferingDocuments(t *testing.T) {
+	source := createScrollStub(`{"hits":{"hits":[
+		{"_id":"same","_source":{"a":1,"b":"x"}},
+		{"_id":"reordered","_source":{"a":2,"b":"y"}},
+		{"_id":"changed","_source":{"a":3}},
+		{"_id":"missing","_source":{"a":4}}
+	]}}`)
+	destination := createScrollStub(`{"hits":{"hits":[
+		{"_id":"same","_source":{"a":1,"b":"x"}},
+		{"_id":"reordered","_source":{ "b" : "y", "a" : 2 }},
+		{"_id":"changed","_source":{"a":33}},
+		{"_id":"extra","_source":{"a":5}}
+	]}}`)
+
+	report := &bytes.Buffer{}
+	v, _ := NewVerifier(ArgsVerifier{
+		SourceElastic:      source,
+		DestinationElastic: destination,
+		IndicesConfig:      config.IndicesConfig{Indices: []string{testIndex}},
+		ReportWriter:       report,
+	})
+
+	results, err := v.Verify(0)
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	require.False(t, results[0].IsSuccessful())
+	require.Equal(t, uint64(4), results[0].NumChecked)
+	require.Equal(t, uint64(1), results[0].NumMissing)
+	require.Equal(t, uint64(1), results[0].NumExtra)
+	require.Equal(t, uint64(1), results[0].NumDiffering)
+
+	written := &intervalReport{}
+	require.NoError(t, json.Unmarshal(report.Bytes(), written))
+	require.Equal(t, []string{"missing"}, written.Missing)
+	require.Equal(t, []string{"extra"}, written.Extra)
+	require.Equal(t, []string{"changed"}, written.Differing)
+}
+
+func TestVerifier_VerifyShouldApplyTransformationsOnSource(t *testing.T) {
+	source := createScrollStub(`{"hits":{"hits":[{"_id":"1","_source":{"sender":"erd1","data":"aa"}}]}}`)
+	destination := createScrollStub(`{"hits":{"hits":[{"_id":"1","_source":{"from":"erd1"}}]}}`)
+
+	cfg := config.IndicesConfig{
+		Indices: []string{testIndex},
+		PerIndex: map[string]config.PerIndexConfig{
+			testIndex: {
+				ExcludeFields: []string{"data"},
+				RenameFields:  []config.RenameFieldConfig{{From: "sender", To: "from"}},
+			},
+		},
+	}
+	v, _ := NewVerifier(ArgsVerifier{
+		SourceElastic:      source,
+		DestinationElastic: destination,
+		IndicesConfig:      cfg,
+		ReportWriter:       &bytes.Buffer{},
+	})
+
+	results, err := v.Verify(0)
+	require.NoError(t, err)
+	require.True(t, results[0].IsSuccessful())
+}
+
+// createPagedStub returns the documents in pages of the provided size, or only the requested ones for the ids queries
+func createPagedStub(pageSize int, documents map[string]string, requestedIDs *[][]string) *mock.ElasticClientStub {
+	return &mock.ElasticClientStub{
+		DoScrollRequestAllDocumentsCalled: func(_ string, body []byte, handlerFunc func(responseBytes []byte) error) error {
+			query := &struct {
+				Query struct {
+					IDs struct {
+						Values []string `json:"values"`
+					} `json:"ids"`
+				} `json:"query"`
+			}{}
+			_ = json.Unmarshal(body, query)
+
+			ids := query.Query.IDs.Values
+			if len(ids) > 0 {
+				*requestedIDs = append(*requestedIDs, ids)
+			} else {
+				for id := range documents {
+					ids = append(ids, id)
+				}
+				sort.Strings(ids)
+			}
+
+			for len(ids) > 0 {
+				numHits := pageSize
+				if numHits > len(ids) {
+					numHits = len(ids)
+				}
+
+				hits := make([]string, 0, numHits)
+				for _, id := range ids[:numHits] {
+					document, found := documents[id]
+					if found {
+						hits = append(hits, fmt.Sprintf(`{"_id":"%s","_source":%s}`, id, document))
+					}
+				}
+				err := handlerFunc([]byte(`{"hits":{"hits":[` + strings.Join(hits, ",") + `]}}`))
+				if err != nil {
+					return err
+				}
+				ids = ids[numHits:]
+			}
+
+			return nil
+		},
+	}
+}
+
+func TestVerifier_VerifyShouldCompareTheDocumentsPageByPage(t *testing.T) {
+	sourceLookups := make([][]string, 0)
+	source := createPagedStub(2, map[string]string{
+		"a": `{"v":1}`,
+		"b": `{"v":2}`,
+		"c": `{"v":3}`,
+	}, &sourceLookups)
+	destinationLookups := make([][]string, 0)
+	destination := createPagedStub(2, map[string]string{
+		"a": `{"v":1}`,
+		"c": `{"v":33}`,
+		"d": `{"v":4}`,
+	}, &destinationLookups)
+
+	report := &bytes.Buffer{}
+	v, _ := NewVerifier(ArgsVerifier{
+		SourceElastic:      source,
+		DestinationElastic: destination,
+		IndicesConfig:      config.IndicesConfig{Indices: []string{testIndex}},
+		ReportWriter:       report,
+	})
+
+	results, err := v.Verify(0)
+	require.NoError(t, err)
+	require.Equal(t, uint64(3), results[0].NumChecked)
+	require.Equal(t, [][]string{{"a", "b"}, {"c"}}, destinationLookups)
+	require.Equal(t, [][]string{{"a", "c"}, {"d"}}, sourceLookups)
+
+	written := &intervalReport{}
+	require.NoError(t, json.Unmarshal(report.Bytes(), written))
+	require.Equal(t, []string{"b"}, written.Missing)
+	require.Equal(t, []string{"d"}, written.Extra)
+	require.Equal(t, []string{"c"}, written.Differing)
+}
+
+func TestVerifier_VerifyWithUpdateActionShouldSkipContentAndExtraDocuments(t *testing.T) {
+	source := createScrollStub(`{"hits":{"hits":[{"_id":"1","_source":{"a":1}}]}}`)
+	destination := createScrollStub(`{"hits":{"hits":[
+		{"_id":"1","_source":{"a":1,"merged":true}},
+		{"_id":"2","_source":{"a":2}}
+	]}}`)
+
+	cfg := config.IndicesConfig{Indices: []string{testIndex}}
+	cfg.Documents.BulkAction = bulkActionUpdate
+	v, _ := NewVerifier(ArgsVerifier{
+		SourceElastic:      source,
+		DestinationElastic: destination,
+		IndicesConfig:      cfg,
+		ReportWriter:       &bytes.Buffer{},
+	})
+
+	results, err := v.Verify(0)
+	require.NoError(t, err)
+	require.True(t, results[0].IsSuccessful())
+	require.Equal(t, uint64(1), results[0].NumChecked)
+}
+
+func TestVerifier_VerifyWithTimestampShouldWriteAReportForEachInterval(t *testing.T) {
+	cfg := config.IndicesConfig{}
+	cfg.WithTimestamp.Enabled = true
+	cfg.WithTimestamp.BlockchainStartTime = 1000
+	cfg.WithTimestamp.NumParallelWrites = 4
+	cfg.WithTimestamp.IndicesWithTimestamp = []string{testIndex}
+
+	report := &bytes.Buffer{}
+	v, _ := NewVerifier(ArgsVerifier{
+		SourceElastic:      &mock.ElasticClientStub{},
+		DestinationElastic: &mock.ElasticClientStub{},
+		IndicesConfig:      cfg,
+		ReportWriter:       report,
+	})
+
+	results, err := v.Verify(2000)
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	require.True(t, results[0].IsSuccessful())
+
+	lines := strings.Split(strings.TrimSpace(report.String()), "\n")
+	require.Len(t, lines, 4)
+
+	starts := make([]int, 0, len(lines))
+	for _, line := range lines {
+		written := &intervalReport{}
+		require.NoError(t, json.Unmarshal([]byte(line), written))
+		starts = append(starts, int(written.Start))
+	}
+	sort.Ints(starts)
+	require.Equal(t, 1000, starts[0])
+}
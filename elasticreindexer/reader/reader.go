@@ -2,20 +2,46 @@ package reader
 
 import (
 	"bytes"
+	"encoding/json"
 	"fmt"
 	"io/ioutil"
 	"path/filepath"
 )
 
-// GetElasticTemplatesAndPolicies will return elastic templates and policies
-// TODO implement policies when will start to use it again
-func GetElasticTemplatesAndPolicies(path string, indexes []string) (map[string]*bytes.Buffer, map[string]*bytes.Buffer, error) {
+// GetElasticTemplatesAndPolicies will return the elastic templates of the provided indices and the lifecycle policies
+// used by them. The templates are read from <templatesPath>/<index>.json and the policies from <policiesPath>/<policy>.json.
+// If an index prefix is provided, the templates are returned by the prefixed index name and their index patterns are
+// prefixed as well
+func GetElasticTemplatesAndPolicies(
+	templatesPath string,
+	policiesPath string,
+	indexes []string,
+	indicesPolicies map[string]string,
+	indexPrefix string,
+) (map[string]*bytes.Buffer, map[string]*bytes.Buffer, error) {
 	indexTemplates := make(map[string]*bytes.Buffer)
 	indexPolicies := make(map[string]*bytes.Buffer)
-	var err error
 
 	for _, index := range indexes {
-		indexTemplates[index], err = getTemplateByIndex(path, index)
+		template, err := readJSONFile(templatesPath, index)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		indexTemplates[indexPrefix+index], err = prefixIndexPatterns(template, indexPrefix)
+		if err != nil {
+			return nil, nil, fmt.Errorf("%w for the template of index %s", err, index)
+		}
+
+		policy, found := indicesPolicies[index]
+		if !found {
+			continue
+		}
+		if _, alreadyRead := indexPolicies[policy]; alreadyRead {
+			continue
+		}
+
+		indexPolicies[policy], err = readJSONFile(policiesPath, policy)
 		if err != nil {
 			return nil, nil, err
 		}
@@ -24,21 +50,96 @@ func GetElasticTemplatesAndPolicies(path string, indexes []string) (map[string]*
 	return indexTemplates, indexPolicies, nil
 }
 
-func getTemplateByIndex(path string, index string) (*bytes.Buffer, error) {
-	indexTemplate := &bytes.Buffer{}
+// prefixIndexPatterns returns the template with the provided prefix added to every entry of its index_patterns field
+func prefixIndexPatterns(template *bytes.Buffer, indexPrefix string) (*bytes.Buffer, error) {
+	if indexPrefix == "" {
+		return template, nil
+	}
+
+	fields := make(map[string]json.RawMessage)
+	err := json.Unmarshal(template.Bytes(), &fields)
+	if err != nil {
+		return nil, err
+	}
+
+	rawPatterns, found := fields["index_patterns"]
+	if !found {
+		return template, nil
+	}
+
+	patterns := make([]string, 0)
+	err = json.Unmarshal(rawPatterns, &patterns)
+	if err != nil {
+		// a single pattern can also be provided as a string
+		pattern := ""
+		err = json.Unmarshal(rawPatterns, &pattern)
+		if err != nil {
+			return nil, err
+		}
+		patterns = append(patterns, pattern)
+	}
+
+	for idx := range patterns {
+		patterns[idx] = indexPrefix + patterns[idx]
+	}
+
+	fields["index_patterns"], err = json.Marshal(patterns)
+	if err != nil {
+		return nil, err
+	}
+
+	templateBytes, err := json.Marshal(fields)
+	if err != nil {
+		return nil, err
+	}
+
+	return bytes.NewBuffer(templateBytes), nil
+}
+
+func readJSONFile(path string, name string) (*bytes.Buffer, error) {
+	content := &bytes.Buffer{}
 
-	fileName := fmt.Sprintf("%s.json", index)
+	fileName := fmt.Sprintf("%s.json", name)
 	filePath := filepath.Join(path, fileName)
 	fileBytes, err := ioutil.ReadFile(filePath)
 	if err != nil {
-		return nil, fmt.Errorf("getTemplateByIndex: %w, path %s, error %s", err, filePath, err.Error())
+		return nil, fmt.Errorf("readJSONFile: %w, path %s, error %s", err, filePath, err.Error())
+	}
+
+	content.Grow(len(fileBytes))
+	_, err = content.Write(fileBytes)
+	if err != nil {
+		return nil, fmt.Errorf("readJSONFile: %w, path %s, error %s", err, filePath, err.Error())
+	}
+
+	return content, nil
+}
+
+// GetIndexBodyFromTemplate returns the body used to create an index with the mappings, settings and aliases of its template
+func GetIndexBodyFromTemplate(path string, index string) (*bytes.Buffer, error) {
+	template, err := readJSONFile(path, index)
+	if err != nil {
+		return nil, err
+	}
+
+	fields := make(map[string]json.RawMessage)
+	err = json.Unmarshal(template.Bytes(), &fields)
+	if err != nil {
+		return nil, fmt.Errorf("GetIndexBodyFromTemplate: %w, index %s", err, index)
+	}
+
+	body := make(map[string]json.RawMessage)
+	for _, field := range []string{"mappings", "settings", "aliases"} {
+		value, found := fields[field]
+		if found {
+			body[field] = value
+		}
 	}
 
-	indexTemplate.Grow(len(fileBytes))
-	_, err = indexTemplate.Write(fileBytes)
+	bodyBytes, err := json.Marshal(body)
 	if err != nil {
-		return nil, fmt.Errorf("getTemplateByIndex: %w, path %s, error %s", err, filePath, err.Error())
+		return nil, err
 	}
 
-	return indexTemplate, nil
+	return bytes.NewBuffer(bodyBytes), nil
 }
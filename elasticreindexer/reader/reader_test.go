@@ -0,0 +1,37 @@
+package reader
+
+import (
+	"bytes"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetElasticTemplatesAndPolicies_WithIndexPrefix(t *testing.T) {
+	templatesPath := t.TempDir()
+	policiesPath := t.TempDir()
+	template := `{"index_patterns":["transactions-*"],"mappings":{"properties":{"nonce":{"type":"long"}}}}`
+	require.NoError(t, ioutil.WriteFile(filepath.Join(templatesPath, "transactions.json"), []byte(template), 0644))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(policiesPath, "rollover.json"), []byte(`{"policy":{}}`), 0644))
+
+	templates, policies, err := GetElasticTemplatesAndPolicies(
+		templatesPath, policiesPath, []string{"transactions"}, map[string]string{"transactions": "rollover"}, "devnet-")
+	require.NoError(t, err)
+	require.Len(t, templates, 1)
+	require.JSONEq(t,
+		`{"index_patterns":["devnet-transactions-*"],"mappings":{"properties":{"nonce":{"type":"long"}}}}`,
+		templates["devnet-transactions"].String())
+	require.Contains(t, policies, "rollover")
+
+	templates, _, err = GetElasticTemplatesAndPolicies(templatesPath, policiesPath, []string{"transactions"}, nil, "")
+	require.NoError(t, err)
+	require.Equal(t, template, templates["transactions"].String())
+}
+
+func TestPrefixIndexPatterns_SinglePattern(t *testing.T) {
+	prefixed, err := prefixIndexPatterns(bytes.NewBufferString(`{"index_patterns":"logs-*"}`), "testnet-")
+	require.NoError(t, err)
+	require.JSONEq(t, `{"index_patterns":["testnet-logs-*"]}`, prefixed.String())
+}
@@ -1,6 +1,9 @@
 package config
 
-import "github.com/multiversx/mx-chain-tools-go/trieTools/trieToolsCommon"
+import (
+	reindexerConfig "github.com/multiversx/mx-chain-tools-go/elasticreindexer/config"
+	"github.com/multiversx/mx-chain-tools-go/trieTools/trieToolsCommon"
+)
 
 // ContextFlagsZeroBalanceSysAccChecker holds all flags required for zeroBalanceSystemAccountChecker tool
 type ContextFlagsZeroBalanceSysAccChecker struct {
@@ -23,9 +26,13 @@ type Config struct {
 
 // ElasticIndexerConfig holds the configuration needed for connecting to an Elasticsearch instance
 type ElasticIndexerConfig struct {
-	URL      string `toml:"url"`
-	Username string `toml:"username"`
-	Password string `toml:"password"`
+	URL       string                    `toml:"url"`
+	Addresses []string                  `toml:"addresses"`
+	CloudID   string                    `toml:"cloud-id"`
+	Username  string                    `toml:"username"`
+	Password  string                    `toml:"password"`
+	APIKey    string                    `toml:"api-key"`
+	TLS       reindexerConfig.TLSConfig `toml:"tls"`
 }
 
 // Gateway holds the configuration needed to cross-check address-token trie
@@ -125,10 +125,15 @@ func crossCheckExtraTokens(globalExtraTokens map[string]struct{}, extraTokensPer
 	}
 
 	nftGetter := newTokenBalanceGetter(cfg.Config.Gateway.URL, http.Get)
+	elasticConfig := cfg.Config.ElasticIndexerConfig
 	elasticClient, err := elastic.NewElasticClient(config.ElasticInstanceConfig{
-		URL:      cfg.Config.ElasticIndexerConfig.URL,
-		Username: cfg.Config.ElasticIndexerConfig.Username,
-		Password: cfg.Config.ElasticIndexerConfig.Password,
+		URL:       elasticConfig.URL,
+		Addresses: elasticConfig.Addresses,
+		CloudID:   elasticConfig.CloudID,
+		Username:  elasticConfig.Username,
+		Password:  elasticConfig.Password,
+		APIKey:    elasticConfig.APIKey,
+		TLS:       elasticConfig.TLS,
 	})
 	if err != nil {
 		return err